@@ -0,0 +1,88 @@
+// Package client is a protocol independent client of game servers which
+// support Source RCON, 7 Days to Die telnet and Rust Web RCON. Errors of the
+// protocol packages are classified by kinds, so consumers can branch on them
+// with errors.Is instead of string matching.
+package client
+
+import (
+	"errors"
+	"net"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/telnet"
+	"github.com/gorcon/websocket"
+)
+
+var (
+	// ErrAuthFailed is returned when the server rejects the password.
+	ErrAuthFailed = errors.New("authentication failed")
+
+	// ErrConnectTimeout is returned when the server does not accept the
+	// connection in time.
+	ErrConnectTimeout = errors.New("connect timeout")
+
+	// ErrBadResponse is returned when the server response violates the
+	// protocol, e.g. the server is not an RCON server.
+	ErrBadResponse = errors.New("bad response")
+
+	// ErrTooLong is returned when the command exceeds the protocol limit.
+	ErrTooLong = errors.New("command too long")
+)
+
+// Error is the error of the protocol package with its kind. Message of the
+// original error is kept.
+type Error struct {
+	// Kind is one of ErrAuthFailed, ErrConnectTimeout, ErrBadResponse and
+	// ErrTooLong.
+	Kind error
+	Err  error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the original error.
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Classify wraps err of rcon, telnet and websocket packages with its kind.
+// Errors of unknown kinds and already classified errors are returned as is.
+func Classify(err error) error {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	if kind := kindOf(err); kind != nil {
+		return &Error{Kind: kind, Err: err}
+	}
+
+	return err
+}
+
+// kindOf returns the kind of err or nil.
+func kindOf(err error) error {
+	var opErr *net.OpError
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, rcon.ErrAuthFailed), errors.Is(err, telnet.ErrAuthFailed),
+		errors.Is(err, websocket.ErrAuthFailed):
+		return ErrAuthFailed
+	case errors.Is(err, rcon.ErrCommandTooLong), errors.Is(err, telnet.ErrCommandTooLong),
+		errors.Is(err, websocket.ErrCommandTooLong):
+		return ErrTooLong
+	case errors.Is(err, rcon.ErrAuthNotRCON), errors.Is(err, rcon.ErrInvalidAuthResponse),
+		errors.Is(err, rcon.ErrInvalidPacketID), errors.Is(err, rcon.ErrInvalidPacketPadding),
+		errors.Is(err, rcon.ErrResponseTooSmall), errors.Is(err, telnet.ErrAuthUnexpectedMessage):
+		return ErrBadResponse
+	case errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return ErrConnectTimeout
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,857 @@
+package client_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/gorcon/telnet"
+	"github.com/gorcon/telnet/telnettest"
+	"github.com/gorcon/websocket"
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{rcon.ErrAuthFailed, client.ErrAuthFailed},
+		{telnet.ErrAuthFailed, client.ErrAuthFailed},
+		{fmt.Errorf("auth: %w", websocket.ErrAuthFailed), client.ErrAuthFailed},
+		{rcon.ErrCommandTooLong, client.ErrTooLong},
+		{websocket.ErrCommandTooLong, client.ErrTooLong},
+		{rcon.ErrAuthNotRCON, client.ErrBadResponse},
+		{rcon.ErrInvalidPacketID, client.ErrBadResponse},
+		{telnet.ErrAuthUnexpectedMessage, client.ErrBadResponse},
+		{&net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}, client.ErrConnectTimeout},
+	}
+
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			err := client.Classify(test.err)
+			assert.ErrorIs(t, err, test.kind)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.err.Error(), err.Error())
+		})
+	}
+
+	t.Run("unknown kind", func(t *testing.T) {
+		err := errors.New("connection reset")
+		assert.Equal(t, err, client.Classify(err))
+		assert.NoError(t, client.Classify(nil))
+
+		err = &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}
+		assert.NotErrorIs(t, client.Classify(err), client.ErrConnectTimeout)
+	})
+}
+
+type countDialer struct {
+	net.Dialer
+	dials int
+}
+
+func (d *countDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.dials++
+
+	return d.Dialer.DialContext(ctx, network, address)
+}
+
+func TestDial(t *testing.T) {
+	server := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			if c.Request().Body() == "sleep" {
+				time.Sleep(500 * time.Millisecond)
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "Can I help you?").WriteTo(c.Conn())
+		}),
+	)
+	defer server.Close()
+
+	t.Run("execute", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, server.Addr(), "password")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		response, err := conn.Execute("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?", response)
+	})
+
+	t.Run("auth failed", func(t *testing.T) {
+		_, err := client.Dial(context.Background(), client.ProtocolRCON, server.Addr(), "wrong")
+		assert.ErrorIs(t, err, client.ErrAuthFailed)
+	})
+
+	t.Run("conn interface", func(t *testing.T) {
+		var conn client.Conn
+
+		conn, err := client.New(client.ProtocolRCON, server.Addr())
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = conn.Execute("help")
+		assert.ErrorIs(t, err, client.ErrNotAuthenticated)
+
+		if !assert.NoError(t, conn.Auth(context.Background(), "password")) {
+			return
+		}
+		defer conn.Close()
+
+		response, err := conn.Execute("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?", response)
+
+		streamer, ok := conn.(client.Streamer)
+		if assert.True(t, ok) {
+			_, err = streamer.Stream(context.Background())
+			assert.ErrorIs(t, err, client.ErrStreamUnsupported)
+		}
+	})
+
+	t.Run("unsupported protocol", func(t *testing.T) {
+		_, err := client.New("ftp", server.Addr())
+		assert.ErrorIs(t, err, client.ErrUnsupportedProtocol)
+	})
+
+	t.Run("cancelled dial", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.Dial(ctx, client.ProtocolRCON, server.Addr(), "password")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("cancelled execute", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, server.Addr(), "password")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = conn.ExecuteContext(ctx, "sleep")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("with dialer and logger", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		dialer := countDialer{}
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, server.Addr(), "password",
+			client.WithTimeout(time.Second), client.WithDialer(&dialer), client.WithLogger(logger))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		response, err := conn.ExecuteContext(context.Background(), "help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?", response)
+		assert.Equal(t, 1, dialer.dials)
+		assert.Contains(t, buf.String(), "msg=dial")
+		assert.Contains(t, buf.String(), "msg=execute command=help")
+	})
+}
+
+func TestOpenStream(t *testing.T) {
+	t.Run("telnet", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer listener.Close()
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			password := make([]byte, len("password"+telnet.CRLF))
+			_, _ = conn.Read(password)
+
+			if string(password) != "password"+telnet.CRLF {
+				_, _ = conn.Write([]byte(telnet.ResponseAuthIncorrectPassword + telnet.CRLF))
+
+				return
+			}
+
+			_, _ = conn.Write([]byte(telnet.ResponseAuthSuccess + telnet.CRLF + telnet.ResponseWelcome + telnet.CRLF +
+				"INF Player connected" + telnet.CRLF + "INF Time: 12:00" + telnet.CRLF))
+		}()
+
+		stream, err := client.OpenStream(context.Background(), client.ProtocolTELNET, listener.Addr().String(), "password")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Close()
+
+		var lines []string
+		for line := range stream.Lines() {
+			lines = append(lines, line)
+		}
+
+		assert.Equal(t, []string{"INF Player connected", "INF Time: 12:00"}, lines)
+	})
+
+	t.Run("web", func(t *testing.T) {
+		upgrader := gorilla.Upgrader{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ws, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer ws.Close()
+
+			_ = ws.WriteJSON(websocket.Message{Message: "[CHAT] Player: hello", Identifier: 0, Type: "Chat"})
+			_ = ws.WriteJSON(websocket.Message{Message: "Saved 1234 ents\n", Identifier: -1, Type: "Generic"})
+		}))
+		defer server.Close()
+
+		address := strings.TrimPrefix(server.URL, "http://")
+
+		stream, err := client.OpenStream(context.Background(), client.ProtocolWebRCON, address, "password")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Close()
+
+		var lines []string
+		for line := range stream.Lines() {
+			lines = append(lines, line)
+		}
+
+		assert.Equal(t, []string{"[CHAT] Player: hello", "Saved 1234 ents"}, lines)
+	})
+
+	t.Run("rcon", func(t *testing.T) {
+		_, err := client.OpenStream(context.Background(), client.ProtocolRCON, "127.0.0.1:0", "password")
+		assert.ErrorIs(t, err, client.ErrStreamUnsupported)
+	})
+}
+
+func TestDetect(t *testing.T) {
+	serverRCON := rcontest.NewServer(rcontest.SetSettings(rcontest.Settings{Password: "password"}))
+	defer serverRCON.Close()
+
+	serverTELNET := telnettest.NewServer(telnettest.SetSettings(telnettest.Settings{Password: "password"}))
+	defer serverTELNET.Close()
+
+	upgrader := gorilla.Upgrader{}
+	serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ws, err := upgrader.Upgrade(w, r, nil); err == nil {
+			_ = ws.Close()
+		}
+	}))
+	defer serverWebRCON.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	tests := []struct {
+		name     string
+		address  string
+		password string
+		protocol string
+		err      error
+	}{
+		{"rcon", serverRCON.Addr(), "password", client.ProtocolRCON, nil},
+		{"rcon wrong password", serverRCON.Addr(), "wrong", client.ProtocolRCON, client.ErrAuthFailed},
+		{"web", strings.TrimPrefix(serverWebRCON.URL, "http://"), "password", client.ProtocolWebRCON, nil},
+		{"telnet", serverTELNET.Addr(), "password", client.ProtocolTELNET, nil},
+		{"not detected", listener.Addr().String(), "password", "", client.ErrNotDetected},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			protocol, err := client.Detect(context.Background(), test.address, test.password,
+				client.WithTimeout(500*time.Millisecond))
+			assert.Equal(t, test.protocol, protocol)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+
+	t.Run("auto client", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolAuto, serverRCON.Addr(), "password")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		assert.Equal(t, client.ProtocolRCON, conn.Protocol())
+	})
+}
+
+func TestSplitCommand(t *testing.T) {
+	statement := "say " + strings.Repeat("a", 596)
+
+	parts, err := client.SplitCommand("status")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"status"}, parts)
+
+	parts, err = client.SplitCommand(statement + "; " + statement + ";" + statement)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{statement, statement, statement}, parts)
+
+	quoted := `say "` + strings.Repeat("a;", 300) + `"`
+	parts, err = client.SplitCommand(quoted + ";" + statement)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{quoted, statement}, parts)
+
+	parts, err = client.SplitCommand(strings.Repeat("kick a;", 150))
+	assert.NoError(t, err)
+
+	if assert.Len(t, parts, 2) {
+		assert.LessOrEqual(t, len(parts[0]), client.MaxCommandLen)
+		assert.Equal(t, 150, strings.Count(parts[0]+";"+parts[1], "kick a"))
+	}
+
+	_, err = client.SplitCommand(strings.Repeat("a", client.MaxCommandLen+1) + ";status")
+	assert.ErrorIs(t, err, client.ErrTooLong)
+	assert.EqualError(t, err, "command too long: 1001 bytes exceed limit of 1000 bytes, send it as several commands")
+}
+
+func TestStatements(t *testing.T) {
+	assert.Equal(t, []string{"say a", "status"}, client.Statements("say a; status;", ";"))
+	assert.Equal(t, []string{`say "a; b"`, "status"}, client.Statements(`say "a; b";status`, ";"))
+	assert.Equal(t, []string{"a", "b"}, client.Statements("a && b", "&&"))
+	assert.Equal(t, []string{"a;b"}, client.Statements(" a;b ", ""))
+}
+
+// sourceServer starts Source RCON server which answers requests with
+// respond and returns its address.
+func sourceServer(t *testing.T, respond func(conn net.Conn, request *rcon.Packet)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				for {
+					request := &rcon.Packet{}
+					if _, readErr := request.ReadFrom(conn); readErr != nil {
+						return
+					}
+
+					if request.Type == rcon.SERVERDATA_AUTH {
+						_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, request.ID, "").WriteTo(conn)
+
+						continue
+					}
+
+					respond(conn, request)
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestWithCollect(t *testing.T) {
+	fragment := strings.Repeat("a", 4096)
+
+	address := sourceServer(t, func(conn net.Conn, request *rcon.Packet) {
+		if request.Body() == "list" {
+			// Minecraft sends the fragments of the response without the end marker.
+			_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, fragment).WriteTo(conn)
+			_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "tail").WriteTo(conn)
+
+			return
+		}
+
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "unknown command").WriteTo(conn)
+	})
+
+	conn, err := client.Dial(context.Background(), client.ProtocolRCON, address, "password",
+		client.WithCollect(client.DefaultCollectWait))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	response, err := conn.Execute("list")
+	assert.NoError(t, err)
+	assert.Equal(t, fragment+"tail", response)
+
+	response, err = conn.Execute("help")
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown command", response)
+}
+
+func TestWithPacketID(t *testing.T) {
+	echo := sourceServer(t, func(conn net.Conn, request *rcon.Packet) {
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, strconv.Itoa(int(request.ID))).WriteTo(conn)
+	})
+
+	zero := sourceServer(t, func(conn net.Conn, request *rcon.Packet) {
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, 0, request.Body()).WriteTo(conn)
+	})
+
+	t.Run("sequential", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, echo, "password",
+			client.WithPacketID(client.PacketIDSequential))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		// ID 1 is used by the auth request.
+		for _, want := range []string{"2", "3"} {
+			response, err := conn.Execute("status")
+			assert.NoError(t, err)
+			assert.Equal(t, want, response)
+		}
+	})
+
+	t.Run("random", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, echo, "password",
+			client.WithPacketID(client.PacketIDRandom))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		response, err := conn.Execute("status")
+		assert.NoError(t, err)
+
+		id, _ := strconv.Atoi(response)
+		assert.Positive(t, id)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, zero, "password",
+			client.WithPacketID(client.PacketIDSequential))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		_, err = conn.Execute("status")
+		assert.ErrorIs(t, err, client.ErrBadResponse)
+		assert.EqualError(t, err, "response for another request: got id 0, want 2")
+	})
+
+	t.Run("accept zero", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolRCON, zero, "password",
+			client.WithPacketID(client.PacketIDRandom), client.WithAcceptZeroID())
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		response, err := conn.Execute("status")
+		assert.NoError(t, err)
+		assert.Equal(t, "status", response)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := client.New(client.ProtocolRCON, echo, client.WithPacketID("even"))
+		assert.ErrorIs(t, err, client.ErrUnsupportedPacketID)
+	})
+}
+
+func TestWithReauth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			return
+		}
+		defer conn.Close()
+
+		// The server forgets the auth after each command.
+		authenticated := false
+
+		for {
+			request := &rcon.Packet{}
+			if _, acceptErr = request.ReadFrom(conn); acceptErr != nil {
+				return
+			}
+
+			switch {
+			case request.Type == rcon.SERVERDATA_AUTH:
+				authenticated = true
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, request.ID, "").WriteTo(conn)
+			case authenticated:
+				authenticated = false
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "ok").WriteTo(conn)
+			default:
+				_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "not authenticated").WriteTo(conn)
+			}
+		}
+	}()
+
+	conn, err := client.Dial(context.Background(), client.ProtocolRCON, listener.Addr().String(), "password",
+		client.WithReauth())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	for i := 0; i < 3; i++ {
+		response, err := conn.Execute("status")
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", response)
+	}
+}
+
+func TestWithTLS(t *testing.T) {
+	upgrader := gorilla.Upgrader{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/password" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+			var request websocket.Message
+			if err = ws.ReadJSON(&request); err != nil {
+				return
+			}
+
+			// Broadcast before the response is skipped.
+			_ = ws.WriteJSON(websocket.Message{Message: "[CHAT] Player: hello", Identifier: 0, Type: "Chat"})
+			_ = ws.WriteJSON(websocket.Message{Message: "echo " + request.Message, Identifier: request.Identifier})
+		}
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	t.Run("trusted", func(t *testing.T) {
+		conn, err := client.Dial(context.Background(), client.ProtocolWebRCON, address, "password",
+			client.WithTLS(server.Client().Transport.(*http.Transport).TLSClientConfig))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		response, err := conn.Execute("status")
+		assert.NoError(t, err)
+		assert.Equal(t, "echo status", response)
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		_, err := client.Dial(context.Background(), client.ProtocolWebRCON, address, "password",
+			client.WithTLS(&tls.Config{MinVersion: tls.VersionTLS12}))
+		assert.Error(t, err)
+	})
+}
+
+func TestWithHeartbeat(t *testing.T) {
+	newServer := func(pong bool, pings chan<- struct{}) *httptest.Server {
+		upgrader := gorilla.Upgrader{}
+
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ws, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer ws.Close()
+
+			ws.SetPingHandler(func(data string) error {
+				select {
+				case pings <- struct{}{}:
+				default:
+				}
+
+				if !pong {
+					return nil
+				}
+
+				return ws.WriteControl(gorilla.PongMessage, []byte(data), time.Now().Add(time.Second))
+			})
+
+			for {
+				if _, _, err = ws.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}))
+	}
+
+	t.Run("ping", func(t *testing.T) {
+		pings := make(chan struct{}, 1)
+
+		server := newServer(true, pings)
+		defer server.Close()
+
+		stream, err := client.OpenStream(context.Background(), client.ProtocolWebRCON,
+			strings.TrimPrefix(server.URL, "http://"), "password", client.WithHeartbeat(20*time.Millisecond, 0))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Close()
+
+		select {
+		case <-pings:
+		case <-time.After(time.Second):
+			assert.Fail(t, "ping is not sent")
+		}
+	})
+
+	t.Run("pong timeout", func(t *testing.T) {
+		server := newServer(false, make(chan struct{}, 1))
+		defer server.Close()
+
+		stream, err := client.OpenStream(context.Background(), client.ProtocolWebRCON,
+			strings.TrimPrefix(server.URL, "http://"), "password",
+			client.WithHeartbeat(20*time.Millisecond, 20*time.Millisecond))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Close()
+
+		done := make(chan struct{})
+
+		go func() {
+			for range stream.Lines() {
+			}
+
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.Fail(t, "dead connection is not detected")
+		}
+	})
+}
+
+func TestExecute_Pipelined(t *testing.T) {
+	const commands = 3
+
+	upgrader := gorilla.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+			// Requests are collected and answered in reverse order.
+			requests := make([]websocket.Message, commands)
+			for i := range requests {
+				if err = ws.ReadJSON(&requests[i]); err != nil {
+					return
+				}
+			}
+
+			for i := len(requests) - 1; i >= 0; i-- {
+				if requests[i].Message == "slow" {
+					continue
+				}
+
+				_ = ws.WriteJSON(websocket.Message{Message: "[CHAT] Player: hello", Identifier: 0, Type: "Chat"})
+				_ = ws.WriteJSON(websocket.Message{Message: "echo " + requests[i].Message, Identifier: requests[i].Identifier})
+			}
+		}
+	}))
+	defer server.Close()
+
+	conn, err := client.Dial(context.Background(), client.ProtocolWebRCON, strings.TrimPrefix(server.URL, "http://"),
+		"password", client.WithTimeout(time.Second))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < commands; i++ {
+			command := "command " + strconv.Itoa(i)
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				response, err := conn.Execute(command)
+				assert.NoError(t, err)
+				assert.Equal(t, "echo "+command, response)
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		done := make(chan error, 1)
+
+		go func() {
+			_, err := conn.ExecuteContext(ctx, "slow")
+			done <- err
+		}()
+
+		responses := make(chan string, 2)
+
+		for _, command := range []string{"status", "players"} {
+			command := command
+
+			go func() {
+				response, _ := conn.Execute(command)
+				responses <- response
+			}()
+		}
+
+		assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+		assert.ElementsMatch(t, []string{"echo status", "echo players"}, []string{<-responses, <-responses})
+	})
+}
+
+func TestWithPrompt(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	negotiated := make(chan []byte, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Character mode: the server echoes input.
+		_, _ = conn.Write([]byte{255, 251, 1, 255, 251, 3})
+
+		r := bufio.NewReader(conn)
+
+		if password, _ := r.ReadString('\n'); password != "password"+telnet.CRLF {
+			return
+		}
+
+		_, _ = conn.Write([]byte(telnet.ResponseAuthSuccess + telnet.CRLF + telnet.ResponseWelcome + telnet.CRLF + "> "))
+
+		for {
+			command, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			// Answers to the negotiation are received after the password.
+			if answers := "\xff\xfd\x01\xff\xfd\x03"; strings.HasPrefix(command, answers) {
+				negotiated <- []byte(answers)
+				command = strings.TrimPrefix(command, answers)
+			}
+
+			command = strings.TrimSpace(command)
+			if command == telnet.DefaultExitCommand {
+				return
+			}
+
+			_, _ = conn.Write([]byte(command + telnet.CRLF + "Players: 2" + telnet.CRLF + "> "))
+		}
+	}()
+
+	start := time.Now()
+	dialer := countDialer{}
+
+	conn, err := client.Dial(context.Background(), client.ProtocolTELNET, listener.Addr().String(), "password",
+		client.WithPrompt(regexp.MustCompile(`^> $`)), client.WithDialer(&dialer))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	for i := 0; i < 2; i++ {
+		response, err := conn.Execute("listplayers")
+		assert.NoError(t, err)
+		assert.Equal(t, "Players: 2", response)
+	}
+
+	assert.Equal(t, 1, dialer.dials)
+
+	select {
+	case answers := <-negotiated:
+		assert.Equal(t, []byte{255, 253, 1, 255, 253, 3}, answers)
+	default:
+		assert.Fail(t, "echo and suppress go ahead are not accepted")
+	}
+
+	assert.Less(t, time.Since(start), telnet.ExecuteTickTimeout)
+}
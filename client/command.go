@@ -0,0 +1,104 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gorcon/rcon"
+)
+
+// MaxCommandLen is the maximum length of the command in bytes which is
+// accepted by the protocol packages.
+const MaxCommandLen = rcon.MaxCommandLen
+
+// CommandSeparator separates several console commands in one command of
+// Source servers.
+const CommandSeparator = ";"
+
+// CheckCommand returns ErrTooLong with the length and the limit if command
+// exceeds MaxCommandLen.
+func CheckCommand(command string) error {
+	if len(command) <= MaxCommandLen {
+		return nil
+	}
+
+	return &Error{Kind: ErrTooLong, Err: fmt.Errorf(
+		"%w: %d bytes exceed limit of %d bytes, send it as several commands",
+		ErrTooLong, len(command), MaxCommandLen)}
+}
+
+// SplitCommand splits command which exceeds MaxCommandLen to commands of
+// allowed length at CommandSeparator outside of double quotes, as Source servers execute semicolon
+// separated console commands one by one. Returns ErrTooLong if a single
+// console command exceeds the limit.
+func SplitCommand(command string) ([]string, error) {
+	if len(command) <= MaxCommandLen {
+		return []string{command}, nil
+	}
+
+	var (
+		parts   []string
+		current string
+	)
+
+	for _, statement := range Statements(command, CommandSeparator) {
+		if err := CheckCommand(statement); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case current == "":
+			current = statement
+		case len(current)+len(CommandSeparator)+len(statement) <= MaxCommandLen:
+			current += CommandSeparator + statement
+		default:
+			parts = append(parts, current)
+			current = statement
+		}
+	}
+
+	if current != "" {
+		parts = append(parts, current)
+	}
+
+	return parts, nil
+}
+
+// Statements splits command by the separator which is not inside double
+// quotes. Statements are trimmed, empty ones are dropped.
+func Statements(command string, separator string) []string {
+	if separator == "" {
+		if command = strings.TrimSpace(command); command == "" {
+			return nil
+		}
+
+		return []string{command}
+	}
+
+	var (
+		statements []string
+		quoted     bool
+		start      int
+	)
+
+	add := func(statement string) {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	for i := 0; i < len(command); i++ {
+		switch {
+		case command[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(command[i:], separator):
+			add(command[start:i])
+			start = i + len(separator)
+			i = start - 1
+		}
+	}
+
+	add(command[start:])
+
+	return statements
+}
@@ -0,0 +1,332 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/telnet"
+)
+
+// Supported protocols.
+const (
+	ProtocolRCON    = "rcon"
+	ProtocolTELNET  = "telnet"
+	ProtocolWebRCON = "web"
+
+	// ProtocolAuto is not a protocol, it means that the protocol is
+	// detected with Detect.
+	ProtocolAuto = "auto"
+)
+
+var (
+	// ErrUnsupportedProtocol is returned when the protocol is not one of
+	// ProtocolRCON, ProtocolTELNET and ProtocolWebRCON.
+	ErrUnsupportedProtocol = errors.New("unsupported protocol")
+
+	// ErrNotAuthenticated is returned when command is sent before Auth.
+	ErrNotAuthenticated = errors.New("not authenticated")
+)
+
+// Conn is the connection to the server of any supported protocol. Auth
+// connects to the server and authenticates with password, Execute sends
+// commands over the authenticated connection.
+type Conn interface {
+	Auth(ctx context.Context, password string) error
+	Execute(command string) (string, error)
+	Close() error
+}
+
+// Streamer is the optional interface of connections which can stream
+// console output of the server.
+type Streamer interface {
+	Stream(ctx context.Context) (*Stream, error)
+}
+
+// conn is the connection of the protocol package.
+type conn interface {
+	Execute(command string) (string, error)
+	Close() error
+}
+
+// pipelined is the optional interface of connections which execute
+// concurrent commands over one socket and match responses to requests.
+// Execution of one command is interrupted without closing the connection.
+type pipelined interface {
+	ExecuteContext(ctx context.Context, command string) (string, error)
+}
+
+// dialFunc opens authenticated connection of the protocol package.
+type dialFunc func(address, password string, timeout time.Duration) (conn, error)
+
+// protocols contains dial functions of supported protocols.
+//
+//nolint:gochecknoglobals // Registry of protocol packages.
+var protocols = map[string]dialFunc{
+	"":              dialRCON,
+	ProtocolRCON:    dialRCON,
+	ProtocolTELNET:  dialTELNET,
+	ProtocolWebRCON: dialWebRCON,
+}
+
+// Client is the connection to the server of any supported protocol.
+type Client struct {
+	protocol string
+	address  string
+	password string
+	options  options
+	dial     dialFunc
+
+	// mu guards conn which is replaced by Auth, exec serializes commands
+	// of connections which are not pipelined.
+	mu   sync.RWMutex
+	exec sync.Mutex
+	conn conn
+}
+
+// Client implements Conn and Streamer.
+var (
+	_ Conn     = (*Client)(nil)
+	_ Streamer = (*Client)(nil)
+)
+
+// New returns not authenticated Client of the protocol. Empty protocol means
+// ProtocolRCON, ProtocolAuto means that the protocol is detected on Auth.
+func New(protocol, address string, opts ...Option) (*Client, error) {
+	dial, ok := protocols[protocol]
+	if !ok && protocol != ProtocolAuto {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProtocol, protocol)
+	}
+
+	settings := newOptions(opts)
+
+	switch settings.packetID {
+	case "", PacketIDRandom, PacketIDSequential:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPacketID, settings.packetID)
+	}
+
+	return &Client{protocol: protocol, address: address, options: settings, dial: dial}, nil
+}
+
+// Dial connects to the server of the protocol and authenticates with
+// password. Empty protocol means ProtocolRCON. Dial is interrupted when ctx
+// is done.
+func Dial(ctx context.Context, protocol, address, password string, opts ...Option) (*Client, error) {
+	c, err := New(protocol, address, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.Auth(ctx, password); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Auth connects to the server and authenticates with password. Auth is
+// interrupted when ctx is done.
+func (c *Client) Auth(ctx context.Context, password string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.protocol == ProtocolAuto {
+		protocol, err := detect(ctx, c.address, password, c.options)
+		if protocol != "" {
+			c.protocol, c.dial = protocol, protocols[protocol]
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+
+	type result struct {
+		conn conn
+		err  error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		dialed, err := c.open(ctx, password)
+		done <- result{conn: dialed, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		c.log("dial", start, r.err)
+
+		if r.err != nil {
+			return Classify(r.err)
+		}
+
+		c.conn = r.conn
+		c.password = password
+
+		return nil
+	case <-ctx.Done():
+		go func() {
+			if r := <-done; r.err == nil {
+				_ = r.conn.Close()
+			}
+		}()
+
+		c.log("dial", start, ctx.Err())
+
+		return ctx.Err()
+	}
+}
+
+// Protocol returns the protocol of the Client. It is the detected protocol
+// after Auth if the Client is created with ProtocolAuto.
+func (c *Client) Protocol() string {
+	return c.protocol
+}
+
+// Stream opens the console output stream of the authenticated server. It
+// returns ErrStreamUnsupported for ProtocolRCON.
+func (c *Client) Stream(ctx context.Context) (*Stream, error) {
+	return openStream(ctx, c.protocol, c.address, c.password, c.options)
+}
+
+// Execute sends command to the server and returns its response.
+func (c *Client) Execute(command string) (string, error) {
+	return c.ExecuteContext(context.Background(), command)
+}
+
+// ExecuteContext sends command to the server and returns its response. It is
+// safe for concurrent use: commands of ProtocolWebRCON are pipelined over one
+// connection, commands of other protocols are sent one by one. When ctx is
+// done before the response, ctx error is returned. Connections of other
+// protocols than ProtocolWebRCON are closed then, the Client can not be used
+// after that.
+func (c *Client) ExecuteContext(ctx context.Context, command string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.conn == nil {
+		return "", ErrNotAuthenticated
+	}
+
+	if err := CheckCommand(command); err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+
+	if p, ok := c.conn.(pipelined); ok {
+		response, err := p.ExecuteContext(ctx, command)
+		c.log("execute", start, err, slog.String("command", command))
+
+		return response, Classify(err)
+	}
+
+	c.exec.Lock()
+	defer c.exec.Unlock()
+
+	if ctx.Done() == nil {
+		response, err := c.conn.Execute(command)
+		c.log("execute", start, err, slog.String("command", command))
+
+		return response, Classify(err)
+	}
+
+	type result struct {
+		response string
+		err      error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		response, err := c.conn.Execute(command)
+		done <- result{response: response, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		c.log("execute", start, r.err, slog.String("command", command))
+
+		return r.response, Classify(r.err)
+	case <-ctx.Done():
+		_ = c.conn.Close()
+		<-done
+
+		c.log("execute", start, ctx.Err(), slog.String("command", command))
+
+		return "", ctx.Err()
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// open opens connection of the protocol package. Connections with custom
+// dialer are opened by own implementations of the protocols, because the
+// protocol packages can not use custom dialers.
+func (c *Client) open(ctx context.Context, password string) (conn, error) {
+	if c.protocol == ProtocolWebRCON {
+		return dialWebConn(ctx, c.address, password, c.options)
+	}
+
+	custom := c.options.dialer != nil
+
+	if (custom || c.options.source()) && (c.protocol == "" || c.protocol == ProtocolRCON) {
+		return dialSource(ctx, c.address, password, c.options)
+	}
+
+	if (custom || c.options.prompt != nil || len(c.options.login) != 0) && c.protocol == ProtocolTELNET {
+		return dialTelnet(ctx, c.address, password, c.options)
+	}
+
+	return c.dial(c.address, password, c.options.timeout)
+}
+
+// dialRCON opens Source RCON connection.
+func dialRCON(address, password string, timeout time.Duration) (conn, error) {
+	return rcon.Dial(address, password, rcon.SetDialTimeout(timeout), rcon.SetDeadline(timeout))
+}
+
+// dialTELNET opens 7 Days to Die telnet connection.
+func dialTELNET(address, password string, timeout time.Duration) (conn, error) {
+	return telnet.Dial(address, password, telnet.SetDialTimeout(timeout))
+}
+
+// dialWebRCON opens Rust Web RCON connection.
+func dialWebRCON(address, password string, timeout time.Duration) (conn, error) {
+	return dialWebConn(context.Background(), address, password, newOptions([]Option{WithTimeout(timeout)}))
+}
+
+// log writes debug record of the operation to the logger if it is set.
+func (c *Client) log(msg string, start time.Time, err error, attrs ...any) {
+	if c.options.logger == nil {
+		return
+	}
+
+	attrs = append(attrs,
+		slog.String("protocol", c.protocol), slog.String("address", c.address),
+		slog.Duration("duration", time.Since(start)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	c.options.logger.Debug(msg, attrs...)
+}
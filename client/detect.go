@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DetectTimeout is the maximum dial and auth timeout of each protocol tried
+// by Detect.
+const DetectTimeout = 2 * time.Second
+
+// ErrNotDetected is returned when the server does not answer on any of the
+// supported protocols.
+var ErrNotDetected = errors.New("protocol is not detected")
+
+// Detect tries ProtocolRCON, ProtocolWebRCON and ProtocolTELNET against the
+// address in turn and returns the first protocol the server answers on. The
+// wrong password identifies the protocol too, it is returned with
+// ErrAuthFailed.
+func Detect(ctx context.Context, address, password string, opts ...Option) (string, error) {
+	return detect(ctx, address, password, newOptions(opts))
+}
+
+// detect detects the protocol with settings.
+func detect(ctx context.Context, address, password string, settings options) (string, error) {
+	settings.timeout = min(settings.timeout, DetectTimeout)
+
+	for _, protocol := range []string{ProtocolRCON, ProtocolWebRCON, ProtocolTELNET} {
+		err := Classify(try(ctx, protocol, address, password, settings))
+		if err == nil || errors.Is(err, ErrAuthFailed) {
+			return protocol, err
+		}
+
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrNotDetected, address)
+}
+
+// try connects to the server with the protocol and closes the connection.
+func try(ctx context.Context, protocol, address, password string, settings options) error {
+	if protocol == ProtocolWebRCON {
+		// Web RCON package waits for the handshake without timeout, which
+		// never ends on servers of other protocols.
+		dialer := settings.dialer
+		if dialer == nil {
+			dialer = &net.Dialer{Timeout: settings.timeout}
+		}
+
+		conn, err := streamWebRCON(ctx, dialer, address, password, settings)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close() //nolint:wrapcheck // Detection result is the dial error.
+	}
+
+	c := Client{protocol: protocol, address: address, options: settings, dial: protocols[protocol]}
+	if err := c.Auth(ctx, password); err != nil {
+		return err
+	}
+
+	return c.Close()
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// DefaultTimeout contains the default dial and execute timeout.
+const DefaultTimeout = 10 * time.Second
+
+// Dialer is the interface of net.Dialer and proxy dialers which connect to
+// the server instead of the protocol packages.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Option configures the Client.
+type Option func(*options)
+
+// options contains settings of the Client.
+type options struct {
+	timeout time.Duration
+	dialer  Dialer
+	logger  *slog.Logger
+	collect time.Duration
+
+	packetID     string
+	acceptZeroID bool
+	reauth       bool
+
+	tls      *tls.Config
+	proxy    *url.URL
+	header   http.Header
+	compress bool
+
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+
+	messageTypes []string
+
+	prompt *regexp.Regexp
+	login  []LoginStep
+}
+
+// newOptions returns settings with applied opts.
+func newOptions(opts []Option) options {
+	settings := options{timeout: DefaultTimeout}
+
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	return settings
+}
+
+// WithTimeout sets dial and execute timeout. Zero value keeps DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithDialer sets dialer which opens connection to the server.
+func WithDialer(dialer Dialer) Option {
+	return func(o *options) {
+		o.dialer = dialer
+	}
+}
+
+// WithLogger sets logger which receives debug records of dials and commands.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithCollect makes Source RCON connections assemble responses which the
+// server splits into several packets without the end marker, e.g. Minecraft.
+// Fragments are collected while the next one arrives within wait.
+func WithCollect(wait time.Duration) Option {
+	return func(o *options) {
+		o.collect = wait
+	}
+}
+
+// WithPacketID sets ID strategy of Source RCON requests, PacketIDRandom or
+// PacketIDSequential. Responses must have the ID of the request.
+func WithPacketID(strategy string) Option {
+	return func(o *options) {
+		o.packetID = strategy
+	}
+}
+
+// WithAcceptZeroID makes Source RCON connections accept responses with ID 0
+// in addition to the request ID. Some servers echo 0 instead of the ID.
+func WithAcceptZeroID() Option {
+	return func(o *options) {
+		o.acceptZeroID = true
+	}
+}
+
+// WithReauth makes Source RCON connections authenticate again before each
+// command. It is the workaround for servers which invalidate auth after
+// each command.
+func WithReauth() Option {
+	return func(o *options) {
+		o.reauth = true
+	}
+}
+
+// WithTLS makes Web RCON connections use TLS (wss) with config, e.g. with
+// the pinned certificate fingerprint. Other protocols have no TLS.
+func WithTLS(config *tls.Config) Option {
+	return func(o *options) {
+		o.tls = config
+	}
+}
+
+// WithProxy makes Web RCON connections go through HTTP proxy instead of the
+// proxy of HTTP_PROXY and HTTPS_PROXY environment variables. Other protocols
+// are not proxied.
+func WithProxy(proxy *url.URL) Option {
+	return func(o *options) {
+		o.proxy = proxy
+	}
+}
+
+// WithHeader adds header to the handshake request of Web RCON connections,
+// e.g. Origin or Authorization of the authenticating reverse proxy.
+func WithHeader(header http.Header) Option {
+	return func(o *options) {
+		o.header = header
+	}
+}
+
+// WithCompression makes Web RCON connections negotiate permessage-deflate
+// compression. Messages are not compressed if the server does not support
+// it.
+func WithCompression() Option {
+	return func(o *options) {
+		o.compress = true
+	}
+}
+
+// WithHeartbeat makes Web RCON connections send ping every interval, so
+// connections survive idle timeouts of NAT and proxies. Streams are closed
+// if pong is not received within pongTimeout after ping, zero pongTimeout
+// keeps the dial timeout.
+func WithHeartbeat(interval, pongTimeout time.Duration) Option {
+	return func(o *options) {
+		o.pingInterval = interval
+		o.pongTimeout = pongTimeout
+	}
+}
+
+// WithMessageTypes makes Web RCON streams contain only messages of types,
+// e.g. "Chat" broadcasts of Rust. All messages are streamed by default.
+func WithMessageTypes(types ...string) Option {
+	return func(o *options) {
+		o.messageTypes = types
+	}
+}
+
+// WithPrompt makes telnet connections wait for the prompt of the server
+// after each command instead of the fixed time, the response ends before the
+// line which matches prompt. Echo of servers in character mode is removed
+// from responses.
+func WithPrompt(prompt *regexp.Regexp) Option {
+	return func(o *options) {
+		o.prompt = prompt
+	}
+}
+
+// WithLogin makes telnet connections authenticate with login steps instead
+// of 7 Days to Die login, e.g. with the username and password prompts of
+// other servers.
+func WithLogin(steps ...LoginStep) Option {
+	return func(o *options) {
+		o.login = steps
+	}
+}
+
+// source returns true if settings require quirks of Source RCON connection
+// which rcon package does not support.
+func (o options) source() bool {
+	return o.collect > 0 || o.packetID != "" || o.acceptZeroID || o.reauth
+}
+
+// dial connects to address with the dialer of options or with the timeout
+// if the dialer is not set.
+func (o options) dial(ctx context.Context, address string) (net.Conn, error) {
+	var dialer Dialer = &net.Dialer{Timeout: o.timeout}
+	if o.dialer != nil {
+		dialer = o.dialer
+	}
+
+	return dialer.DialContext(ctx, "tcp", address) //nolint:wrapcheck // Wrapped by caller.
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// DefaultCollectWait is the time to wait for the next fragment of the
+// response of Minecraft server.
+const DefaultCollectWait = 100 * time.Millisecond
+
+// Packet ID strategies of Source RCON requests.
+const (
+	// PacketIDRandom sends each request with random positive ID.
+	PacketIDRandom = "random"
+
+	// PacketIDSequential sends requests with IDs 1, 2, 3 and so on.
+	PacketIDSequential = "sequential"
+)
+
+// ErrUnsupportedPacketID is returned when the packet ID strategy is not one
+// of PacketIDRandom and PacketIDSequential.
+var ErrUnsupportedPacketID = errors.New("unsupported packet id strategy")
+
+// maxFragmentSize is the body size of the full packet. Servers which split
+// long responses send the next fragment only after the full one.
+const maxFragmentSize = int(rcon.MaxPacketSize - rcon.MinPacketSize)
+
+// sourceConn is Source RCON connection which is used instead of rcon package
+// for quirks of third-party servers. It assembles responses split into
+// several packets and validates response IDs against IDs of requests.
+//
+// Minecraft sends fragments without the end marker, so the fragments are
+// collected while the server sends the next one within collect after the
+// full packet. Servers which invalidate auth after each command get the
+// auth request before each command if reauth is set.
+type sourceConn struct {
+	conn       net.Conn
+	r          *bufio.Reader
+	timeout    time.Duration
+	collect    time.Duration
+	packetID   string
+	acceptZero bool
+	lastID     int32
+
+	reauth   bool
+	password string
+	executed bool
+}
+
+// dialSource opens Source RCON connection with quirks of settings.
+func dialSource(ctx context.Context, address, password string, settings options) (conn, error) {
+	netConn, err := settings.dial(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("rcon: %w", err)
+	}
+
+	c := &sourceConn{
+		conn:       netConn,
+		r:          bufio.NewReader(netConn),
+		timeout:    settings.timeout,
+		collect:    settings.collect,
+		packetID:   settings.packetID,
+		acceptZero: settings.acceptZeroID,
+		reauth:     settings.reauth,
+		password:   password,
+	}
+
+	if err = c.auth(password); err != nil {
+		_ = netConn.Close()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Execute sends command to the server and returns the assembled response.
+func (c *sourceConn) Execute(command string) (string, error) {
+	if c.reauth && c.executed {
+		if err := c.auth(c.password); err != nil {
+			return "", err
+		}
+	}
+
+	c.executed = true
+
+	id := c.nextID(rcon.SERVERDATA_EXECCOMMAND_ID)
+	if err := c.write(rcon.SERVERDATA_EXECCOMMAND, id, command); err != nil {
+		return "", err
+	}
+
+	packet, err := c.read()
+	if err != nil {
+		return "", err
+	}
+
+	if err = c.check(packet, id); err != nil {
+		return "", err
+	}
+
+	var response strings.Builder
+
+	response.WriteString(packet.Body())
+
+	for c.collect > 0 && len(packet.Body()) >= maxFragmentSize {
+		if err = c.wait(c.collect); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+
+			return response.String(), fmt.Errorf("rcon: %w", err)
+		}
+
+		if packet, err = c.read(); err != nil {
+			return response.String(), err
+		}
+
+		if err = c.check(packet, id); err != nil {
+			return response.String(), err
+		}
+
+		response.WriteString(packet.Body())
+	}
+
+	return response.String(), nil
+}
+
+// Close closes the connection.
+func (c *sourceConn) Close() error {
+	return c.conn.Close() //nolint:wrapcheck // Error of net connection.
+}
+
+// auth authenticates with password. The empty response value sent by some
+// servers before the auth response is skipped.
+func (c *sourceConn) auth(password string) error {
+	id := c.nextID(rcon.SERVERDATA_AUTH_ID)
+	if err := c.write(rcon.SERVERDATA_AUTH, id, password); err != nil {
+		return err
+	}
+
+	packet, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	if packet.Type == rcon.SERVERDATA_RESPONSE_VALUE {
+		if packet, err = c.read(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case packet.Type != rcon.SERVERDATA_AUTH_RESPONSE:
+		return rcon.ErrInvalidAuthResponse
+	case packet.ID == -1:
+		return rcon.ErrAuthFailed
+	}
+
+	return c.check(packet, id)
+}
+
+// nextID returns ID of the next request by the strategy or fixed ID of
+// rcon package if the strategy is not set.
+func (c *sourceConn) nextID(fixed int32) int32 {
+	switch c.packetID {
+	case PacketIDRandom:
+		return rand.Int31n(math.MaxInt32) + 1 //nolint:gosec // Packet IDs are not secrets.
+	case PacketIDSequential:
+		if c.lastID == math.MaxInt32 {
+			c.lastID = 0
+		}
+
+		c.lastID++
+
+		return c.lastID
+	default:
+		return fixed
+	}
+}
+
+// check returns error if the packet responds to another request than id.
+// Servers which echo 0 instead of the request ID are tolerated if
+// acceptZero is set.
+func (c *sourceConn) check(packet *rcon.Packet, id int32) error {
+	if packet.ID == id || (c.acceptZero && packet.ID == 0) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: got id %d, want %d", rcon.ErrInvalidPacketID, packet.ID, id)
+}
+
+// write sends packet to the server.
+func (c *sourceConn) write(packetType, packetID int32, body string) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+
+	if _, err := rcon.NewPacket(packetType, packetID, body).WriteTo(c.conn); err != nil {
+		return fmt.Errorf("rcon: %w", err)
+	}
+
+	return nil
+}
+
+// read reads the next packet.
+func (c *sourceConn) read() (*rcon.Packet, error) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+	packet := &rcon.Packet{}
+	if _, err := packet.ReadFrom(c.r); err != nil {
+		return nil, err //nolint:wrapcheck // Errors of rcon package are wrapped.
+	}
+
+	return packet, nil
+}
+
+// wait waits for the next packet within timeout without consuming it, so
+// the connection stays usable when the server sends nothing.
+func (c *sourceConn) wait(timeout time.Duration) error {
+	_ = c.conn.SetReadDeadline(time.Now().Add(timeout))
+
+	_, err := c.r.Peek(1)
+
+	return err //nolint:wrapcheck // Wrapped by caller.
+}
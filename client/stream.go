@@ -0,0 +1,242 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorcon/telnet"
+	"github.com/gorcon/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// ErrStreamUnsupported is returned when the protocol has no console output
+// which could be streamed, e.g. Source RCON.
+var ErrStreamUnsupported = errors.New("stream is not supported by protocol")
+
+// Stream is the console output of the server. It is read incrementally with
+// Read or by lines with Lines until Close is called or the connection is
+// lost.
+type Stream struct {
+	r      *io.PipeReader
+	closer io.Closer
+	err    error
+}
+
+// OpenStream connects to the console of the server of ProtocolTELNET or
+// ProtocolWebRCON and returns its output. Telnet console is streamed as is,
+// Web RCON messages including broadcasts are streamed one per line.
+func OpenStream(ctx context.Context, protocol, address, password string, opts ...Option) (*Stream, error) {
+	return openStream(ctx, protocol, address, password, newOptions(opts))
+}
+
+// openStream opens the stream with settings.
+func openStream(ctx context.Context, protocol, address, password string, settings options) (*Stream, error) {
+	dialer := settings.dialer
+	if dialer == nil {
+		dialer = &net.Dialer{Timeout: settings.timeout}
+	}
+
+	switch protocol {
+	case ProtocolTELNET:
+		conn, output, err := streamTELNET(ctx, dialer, address, password, settings)
+		if err != nil {
+			return nil, Classify(err)
+		}
+
+		r, w := io.Pipe()
+
+		go func() {
+			_, copyErr := io.Copy(w, output)
+			_ = w.CloseWithError(copyErr)
+		}()
+
+		return &Stream{r: r, closer: conn}, nil
+	case ProtocolWebRCON:
+		conn, err := streamWebRCON(ctx, dialer, address, password, settings)
+		if err != nil {
+			return nil, Classify(err)
+		}
+
+		pongTimeout := settings.pongTimeout
+		if pongTimeout <= 0 {
+			pongTimeout = settings.timeout
+		}
+
+		heartbeat(conn, settings.pingInterval, pongTimeout)
+
+		r, w := io.Pipe()
+
+		go func() {
+			_ = w.CloseWithError(copyMessages(w, conn, settings.messageTypes))
+		}()
+
+		return &Stream{r: r, closer: conn}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrStreamUnsupported, protocol)
+	}
+}
+
+// Read reads console output of the server.
+func (s *Stream) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+// Lines returns channel of console output lines. The channel is closed when
+// the stream ends, it must be drained to release the stream.
+func (s *Stream) Lines() <-chan string {
+	lines := make(chan string)
+
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(s.r)
+		for scanner.Scan() {
+			lines <- strings.TrimRight(scanner.Text(), "\r"+telnet.NullString)
+		}
+
+		s.err = scanner.Err()
+	}()
+
+	return lines
+}
+
+// Err returns the error which ended the stream of Lines, e.g. the lost
+// connection. It is nil if the connection was closed by the server and must
+// be called only after the channel of Lines is closed.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Close closes the connection to the console.
+func (s *Stream) Close() error {
+	err := s.closer.Close()
+	_ = s.r.Close()
+
+	return err //nolint:wrapcheck // Errors of net and gorilla/websocket connections.
+}
+
+// streamTELNET authenticates in telnet console with login steps of settings
+// or 7 Days to Die login and returns the connection with reader of the
+// console output following the login.
+func streamTELNET(
+	ctx context.Context, dialer Dialer, address, password string, settings options,
+) (net.Conn, io.Reader, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("telnet: %w", err)
+	}
+
+	if len(settings.login) != 0 {
+		r := &negotiator{conn: conn}
+		if err = login(conn, r, password, settings.login, settings.timeout); err != nil {
+			_ = conn.Close()
+
+			return nil, nil, err
+		}
+
+		return conn, r, nil
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(settings.timeout))
+
+	if _, err = conn.Write([]byte(password + telnet.CRLF)); err != nil {
+		_ = conn.Close()
+
+		return nil, nil, fmt.Errorf("telnet: %w", err)
+	}
+
+	output := bufio.NewReader(conn)
+
+	for {
+		var line string
+		if line, err = output.ReadString('\n'); err != nil {
+			_ = conn.Close()
+
+			return nil, nil, fmt.Errorf("telnet: %w", err)
+		}
+
+		switch {
+		case strings.Contains(line, telnet.ResponseAuthIncorrectPassword),
+			strings.Contains(line, telnet.ResponseAuthTooManyFails):
+			_ = conn.Close()
+
+			return nil, nil, telnet.ErrAuthFailed
+		case strings.Contains(line, telnet.ResponseWelcome):
+			_ = conn.SetDeadline(time.Time{})
+
+			return conn, output, nil
+		}
+	}
+}
+
+// streamWebRCON opens Web RCON connection with password, handshake header
+// and compression of settings. Connection is opened over TLS if TLS config
+// of settings is set and through HTTP proxy of settings or of HTTP_PROXY and
+// HTTPS_PROXY environment variables.
+func streamWebRCON(ctx context.Context, dialer Dialer, address, password string, settings options) (*gorilla.Conn, error) {
+	ws := gorilla.Dialer{
+		NetDialContext:    dialer.DialContext,
+		HandshakeTimeout:  settings.timeout,
+		TLSClientConfig:   settings.tls,
+		Proxy:             http.ProxyFromEnvironment,
+		EnableCompression: settings.compress,
+	}
+
+	if settings.proxy != nil {
+		ws.Proxy = http.ProxyURL(settings.proxy)
+	}
+
+	u := url.URL{Scheme: "ws", Host: address, Path: password}
+	if settings.tls != nil {
+		u.Scheme = "wss"
+	}
+
+	conn, resp, err := ws.DialContext(ctx, u.String(), settings.header)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+
+	if err != nil {
+		if strings.Contains(err.Error(), `malformed HTTP response "\x88\x02\x03\xe8"`) {
+			return nil, websocket.ErrAuthFailed
+		}
+
+		return nil, fmt.Errorf("webrcon: %w", err)
+	}
+
+	return conn, nil
+}
+
+// copyMessages writes texts of Web RCON messages to w one per line. Only
+// messages of types are written if types are set.
+func copyMessages(w io.Writer, conn *gorilla.Conn, types []string) error {
+	for {
+		_, p, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("webrcon: %w", err)
+		}
+
+		var message websocket.Message
+		if err = json.Unmarshal(p, &message); err != nil {
+			return fmt.Errorf("webrcon: %w", err)
+		}
+
+		if len(types) != 0 && !slices.Contains(types, message.Type) {
+			continue
+		}
+
+		if _, err = io.WriteString(w, strings.TrimRight(message.Message, "\n")+"\n"); err != nil {
+			return err //nolint:wrapcheck // Closed pipe of the stream.
+		}
+	}
+}
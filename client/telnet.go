@@ -0,0 +1,345 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorcon/telnet"
+)
+
+// drainWait is the time to wait for the output which is left from the
+// previous command, e.g. the prompt printed after login.
+const drainWait = 10 * time.Millisecond
+
+// Telnet commands and options of option negotiation, RFC 854 and RFC 857.
+const (
+	iac  = 255
+	dont = 254
+	do   = 253
+	wont = 252
+	will = 251
+	sb   = 250
+	se   = 240
+
+	optEcho            = 1
+	optSuppressGoAhead = 3
+)
+
+// LoginStep is the step of telnet login sequence. Output of the server is
+// read until it matches Expect, then Send or the password is sent.
+type LoginStep struct {
+	Expect *regexp.Regexp
+	// Send is the line sent after Expect, nothing is sent if it is empty.
+	Send string
+	// Password sends the password instead of Send.
+	Password bool
+}
+
+// telnetConn is telnet connection which is used instead of telnet package
+// for servers with prompts or custom login. Response of the command ends
+// with the prompt instead of waiting for the fixed time of telnet package.
+//
+// Servers in character mode negotiate the echo of input: it is accepted and
+// the echoed command is removed from the response. Other options are
+// refused.
+type telnetConn struct {
+	conn    net.Conn
+	r       io.Reader
+	timeout time.Duration
+	prompt  *regexp.Regexp
+	login   []LoginStep
+}
+
+// dialTelnet opens telnet connection with the prompt of settings and
+// authenticates with password and login steps of settings.
+func dialTelnet(ctx context.Context, address, password string, settings options) (conn, error) {
+	netConn, err := settings.dial(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("telnet: %w", err)
+	}
+
+	c := &telnetConn{
+		conn:    netConn,
+		r:       &negotiator{conn: netConn},
+		timeout: settings.timeout,
+		prompt:  settings.prompt,
+		login:   settings.login,
+	}
+
+	if err = c.auth(password); err != nil {
+		_ = netConn.Close()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Execute sends command to the server and returns the output printed before
+// the prompt.
+func (c *telnetConn) Execute(command string) (string, error) {
+	if command == "" {
+		return "", telnet.ErrCommandEmpty
+	}
+
+	if len(command) > telnet.MaxCommandLen {
+		return "", telnet.ErrCommandTooLong
+	}
+
+	c.drain()
+
+	if err := c.write(command); err != nil {
+		return "", err
+	}
+
+	output, err := c.readPrompt()
+	if err != nil {
+		return "", err
+	}
+
+	// Echo of character mode and line mode servers.
+	output = strings.TrimPrefix(strings.TrimLeft(output, "\r\n"), command)
+
+	return strings.TrimSpace(output), nil
+}
+
+// Close sends exit command and closes the connection.
+func (c *telnetConn) Close() error {
+	_ = c.write(telnet.DefaultExitCommand)
+
+	return c.conn.Close() //nolint:wrapcheck // Error of net connection.
+}
+
+// auth sends password and waits for the result of 7 Days to Die login or
+// passes login steps.
+func (c *telnetConn) auth(password string) error {
+	if len(c.login) != 0 {
+		return login(c.conn, c.r, password, c.login, c.timeout)
+	}
+
+	if err := c.write(password); err != nil {
+		return err
+	}
+
+	var output []byte
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+	chunk := make([]byte, 1024)
+
+	for {
+		n, err := c.r.Read(chunk)
+		output = append(output, chunk[:n]...)
+
+		switch {
+		case bytes.Contains(output, []byte(telnet.ResponseAuthIncorrectPassword)),
+			bytes.Contains(output, []byte(telnet.ResponseAuthTooManyFails)):
+			return telnet.ErrAuthFailed
+		case bytes.Contains(output, []byte(telnet.ResponseWelcome)):
+			return nil
+		case err != nil:
+			return fmt.Errorf("telnet: %w", err)
+		}
+	}
+}
+
+// readPrompt reads the output until its last line matches the prompt and
+// returns the output before the prompt. Without the prompt the output is
+// read for the fixed time of telnet package.
+func (c *telnetConn) readPrompt() (string, error) {
+	var output strings.Builder
+
+	if c.prompt == nil {
+		_ = c.conn.SetReadDeadline(time.Now().Add(telnet.ExecuteTickTimeout))
+
+		_, err := io.Copy(&output, c.r)
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			err = nil
+		}
+
+		return strings.ReplaceAll(output.String(), telnet.NullString, ""), err //nolint:wrapcheck // Error of net connection.
+	}
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+	chunk := make([]byte, 1024)
+
+	for {
+		n, err := c.r.Read(chunk)
+		output.Write(bytes.ReplaceAll(chunk[:n], []byte(telnet.NullString), nil))
+
+		text := output.String()
+		last := text[strings.LastIndex(text, "\n")+1:]
+
+		if c.prompt.MatchString(strings.TrimRight(last, "\r")) {
+			return strings.TrimSuffix(text, last), nil
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("telnet: %w", err)
+		}
+	}
+}
+
+// login passes login steps: reads the output of conn from r until it
+// matches the expected text and sends the answer. The output which is not
+// received in timeout returns telnet.ErrAuthUnexpectedMessage.
+func login(conn net.Conn, r io.Reader, password string, steps []LoginStep, timeout time.Duration) error {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	defer conn.SetDeadline(time.Time{}) //nolint:errcheck // Deadline of the open connection.
+
+	chunk := make([]byte, 1024)
+
+	for _, step := range steps {
+		var output []byte
+
+		for !step.Expect.Match(output) {
+			n, err := r.Read(chunk)
+			output = append(output, chunk[:n]...)
+
+			if err != nil && !step.Expect.Match(output) {
+				return fmt.Errorf("%w: %q is not received: %s", telnet.ErrAuthUnexpectedMessage, step.Expect, err)
+			}
+		}
+
+		line := step.Send
+		if step.Password {
+			line = password
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if _, err := conn.Write([]byte(line + telnet.CRLF)); err != nil {
+			return fmt.Errorf("telnet: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// drain skips the output which is left from the previous command.
+func (c *telnetConn) drain() {
+	chunk := make([]byte, 1024)
+
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(drainWait))
+
+		if _, err := c.r.Read(chunk); err != nil {
+			return
+		}
+	}
+}
+
+// write sends the line to the server.
+func (c *telnetConn) write(line string) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+
+	if _, err := c.conn.Write([]byte(line + telnet.CRLF)); err != nil {
+		return fmt.Errorf("telnet: %w", err)
+	}
+
+	return nil
+}
+
+// negotiator reads data of telnet connection without commands of option
+// negotiation and answers them. Echo and suppress go ahead options of
+// character mode are accepted, other options are refused.
+type negotiator struct {
+	conn net.Conn
+
+	// command contains bytes of the incomplete command split between reads.
+	command []byte
+}
+
+// Read reads data without negotiation commands.
+func (n *negotiator) Read(p []byte) (int, error) {
+	buf := make([]byte, len(p))
+
+	read, err := n.conn.Read(buf)
+	data := append(n.command, buf[:read]...) //nolint:gocritic // Data continues the incomplete command.
+	n.command = nil
+
+	written := 0
+
+	for i := 0; i < len(data); i++ {
+		switch {
+		case data[i] != iac:
+			p[written] = data[i]
+			written++
+		case i+1 < len(data) && data[i+1] == iac:
+			// Escaped 255 byte of data.
+			p[written] = iac
+			written++
+			i++
+		default:
+			size := n.answer(data[i:])
+			if size == 0 {
+				n.command = append([]byte(nil), data[i:]...)
+
+				return written, err //nolint:wrapcheck // Error of net connection.
+			}
+
+			i += size - 1
+		}
+	}
+
+	return written, err //nolint:wrapcheck // Error of net connection.
+}
+
+// answer answers the command at the beginning of data and returns its size.
+// Zero size means the incomplete command.
+func (n *negotiator) answer(data []byte) int {
+	if len(data) < 2 { //nolint:gomnd // IAC and command.
+		return 0
+	}
+
+	switch data[1] {
+	case sb:
+		end := bytes.Index(data, []byte{iac, se})
+		if end == -1 {
+			return 0
+		}
+
+		return end + 2 //nolint:gomnd // IAC and SE.
+	case do, dont, will, wont:
+		if len(data) < 3 { //nolint:gomnd // IAC, command and option.
+			return 0
+		}
+
+		n.reply(data[1], data[2])
+
+		return 3 //nolint:gomnd // IAC, command and option.
+	default:
+		return 2 //nolint:gomnd // IAC and command.
+	}
+}
+
+// reply answers the negotiation command of the option.
+func (n *negotiator) reply(command, option byte) {
+	var answer byte
+
+	switch {
+	case command == will && (option == optEcho || option == optSuppressGoAhead):
+		answer = do
+	case command == will:
+		answer = dont
+	case command == do:
+		answer = wont
+	default:
+		// Refusals are not answered.
+		return
+	}
+
+	_, _ = n.conn.Write([]byte{iac, answer, option})
+}
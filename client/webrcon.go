@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorcon/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// webConn is Rust Web RCON connection which is used instead of websocket
+// package for TLS, HTTP proxy, handshake header, compression, custom dialer
+// and concurrent commands. Requests are pipelined over one socket: the
+// reader matches responses to pending requests by identifier and skips
+// broadcasts.
+type webConn struct {
+	conn    *gorilla.Conn
+	timeout time.Duration
+
+	// write serializes writes, gorilla/websocket supports one writer.
+	write sync.Mutex
+
+	mu      sync.Mutex
+	pending map[int]chan string
+	err     error
+	done    chan struct{}
+}
+
+// webConn implements pipelined.
+var _ pipelined = (*webConn)(nil)
+
+// dialWebConn opens Web RCON connection with the dialer, TLS config, HTTP
+// proxy, handshake header and compression of settings.
+func dialWebConn(ctx context.Context, address, password string, settings options) (conn, error) {
+	var dialer Dialer = &net.Dialer{Timeout: settings.timeout}
+	if settings.dialer != nil {
+		dialer = settings.dialer
+	}
+
+	ws, err := streamWebRCON(ctx, dialer, address, password, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Responses are waited with the timeout of the command, so pongs extend
+	// nothing and dead connections are detected by commands.
+	heartbeat(ws, settings.pingInterval, 0)
+
+	c := &webConn{conn: ws, timeout: settings.timeout, pending: make(map[int]chan string), done: make(chan struct{})}
+	go c.read()
+
+	return c, nil
+}
+
+// Execute sends command to the server and returns the response with the
+// identifier of the request.
+func (c *webConn) Execute(command string) (string, error) {
+	return c.ExecuteContext(context.Background(), command)
+}
+
+// ExecuteContext sends command to the server and returns the response with
+// the identifier of the request. It is safe for concurrent use. Waiting is
+// stopped when ctx is done or timeout expires, the late response is skipped
+// and the connection stays usable.
+func (c *webConn) ExecuteContext(ctx context.Context, command string) (string, error) {
+	if command == "" {
+		return "", websocket.ErrCommandEmpty
+	}
+
+	if len(command) > websocket.MaxCommandLen {
+		return "", websocket.ErrCommandTooLong
+	}
+
+	identifier, response, err := c.register()
+	if err != nil {
+		return "", err
+	}
+	defer c.unregister(identifier)
+
+	if err = c.send(websocket.Message{Message: command, Identifier: identifier}); err != nil {
+		return "", err
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case message := <-response:
+		return message, nil
+	case <-c.done:
+		return "", c.err
+	case <-timer.C:
+		return "", fmt.Errorf("webrcon: %w", os.ErrDeadlineExceeded)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Close closes the connection. Pending commands get the error of the
+// closed connection.
+func (c *webConn) Close() error {
+	return c.conn.Close() //nolint:wrapcheck // Error of gorilla/websocket connection.
+}
+
+// register returns the unique identifier of the new request and channel of
+// its response.
+func (c *webConn) register() (int, chan string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return 0, nil, c.err
+	}
+
+	identifier := rand.Intn(websocket.RandIdentifierLimit) + 1 //nolint:gosec // Identifiers are not secrets.
+	for c.pending[identifier] != nil {
+		identifier = rand.Intn(websocket.RandIdentifierLimit) + 1 //nolint:gosec // Identifiers are not secrets.
+	}
+
+	response := make(chan string, 1)
+	c.pending[identifier] = response
+
+	return identifier, response, nil
+}
+
+// unregister removes the request, so its late response is skipped.
+func (c *webConn) unregister(identifier int) {
+	c.mu.Lock()
+	delete(c.pending, identifier)
+	c.mu.Unlock()
+}
+
+// send writes the request to the server.
+func (c *webConn) send(request websocket.Message) error {
+	c.write.Lock()
+	defer c.write.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+
+	if err := c.conn.WriteJSON(request); err != nil {
+		return fmt.Errorf("webrcon: %w", err)
+	}
+
+	return nil
+}
+
+// read delivers responses to pending requests until the connection is
+// closed. Broadcasts and messages which are not JSON are skipped.
+func (c *webConn) read() {
+	for {
+		_, p, err := c.conn.ReadMessage()
+		if err != nil {
+			c.mu.Lock()
+			c.err = fmt.Errorf("webrcon: %w", err)
+			c.mu.Unlock()
+
+			close(c.done)
+
+			return
+		}
+
+		var message websocket.Message
+		if err = json.Unmarshal(p, &message); err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		response := c.pending[message.Identifier]
+		delete(c.pending, message.Identifier)
+		c.mu.Unlock()
+
+		if response != nil {
+			response <- message.Message
+		}
+	}
+}
+
+// heartbeat sends ping to conn every interval until the connection is closed.
+// If pongTimeout is set, the read deadline of conn is extended by each pong,
+// so reads fail when the server stops responding. Nothing is done if
+// interval is not set.
+func heartbeat(conn *gorilla.Conn, interval, pongTimeout time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	if pongTimeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(interval + pongTimeout))
+
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(interval + pongTimeout)) //nolint:wrapcheck // Read error.
+		})
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := conn.WriteControl(gorilla.PingMessage, nil, time.Now().Add(interval)); err != nil {
+				return
+			}
+		}
+	}()
+}
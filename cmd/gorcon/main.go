@@ -3,8 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime/debug"
 
 	"github.com/gorcon/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/terminal"
 )
 
 // Version displays service version in semantic versioning (http://semver.org/).
@@ -12,12 +15,26 @@ import (
 var Version = "develop"
 
 func main() {
+	// Panic messages of the main goroutine are printed without passwords.
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "panic: %s\n\n%s", redact.String(fmt.Sprint(r)), redact.String(string(debug.Stack())))
+			os.Exit(2)
+		}
+	}()
+
+	terminal.EnableANSI(os.Stdout)
+
 	exec := executor.NewExecutor(os.Stdin, os.Stdout, Version)
 
 	if err := exec.Run(os.Args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		code, reported := executor.ExitCode(err)
+		if !reported {
+			fmt.Fprintln(os.Stderr, redact.Error(err))
+		}
+
 		exec.Close()
-		os.Exit(1)
+		os.Exit(code)
 	}
 
 	exec.Close()
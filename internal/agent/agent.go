@@ -0,0 +1,174 @@
+// Package agent keeps unlocked credentials in memory of a background process
+// behind a Unix socket, so several CLI invocations share entered passwords
+// like ssh-agent.
+//
+// Requests and responses are JSON objects, one pair per connection.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/credcache"
+)
+
+// EnvSocket is the environment variable with the path to the agent socket.
+// Commands use the agent only if it is set.
+const EnvSocket = "RCON_AGENT_SOCK"
+
+// EnvRuntimeDir is the environment variable with the private runtime
+// directory of the user where the socket is created by default.
+const EnvRuntimeDir = "XDG_RUNTIME_DIR"
+
+// DefaultTimeout is the timeout of requests to the agent.
+const DefaultTimeout = time.Second
+
+// Operations of agent requests.
+const (
+	OpGet  = "get"
+	OpSet  = "set"
+	OpStop = "stop"
+)
+
+var (
+	// ErrRunning is returned when the agent already listens on the socket.
+	ErrRunning = errors.New("agent is already running")
+
+	// ErrUnknownOp is returned when the request has unknown operation.
+	ErrUnknownOp = errors.New("unknown operation")
+
+	// ErrNotOwned is returned when the socket is not owned by the current
+	// user, so the agent can be run by another user to collect passwords.
+	ErrNotOwned = errors.New("agent socket is not owned by the current user")
+)
+
+// Request is the request to the agent.
+type Request struct {
+	Op       string `json:"op"`
+	Address  string `json:"address,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// Response is the response of the agent. OK is false if the password is not
+// kept by the agent.
+type Response struct {
+	OK       bool   `json:"ok"`
+	Password string `json:"password,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Socket returns the path to the agent socket from EnvSocket variable or the
+// default path in the runtime directory of the user. Without the runtime
+// directory the socket is placed in the private directory of the user in the
+// temporary directory.
+func Socket() string {
+	if socket := os.Getenv(EnvSocket); socket != "" {
+		return socket
+	}
+
+	if dir := os.Getenv(EnvRuntimeDir); dir != "" {
+		return filepath.Join(dir, "rcon-agent.sock")
+	}
+
+	return filepath.Join(os.TempDir(), "rcon-agent-"+strconv.Itoa(os.Getuid()), "agent.sock")
+}
+
+// Listen listens on the socket which is accessible by the current user only.
+// Missing directory of the socket is created accessible by the current user
+// only too. Stale socket of stopped agent is removed.
+func Listen(socket string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", socket, DefaultTimeout); err == nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("%w: %s", ErrRunning, socket)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(socket), 0o700); err != nil {
+		return nil, fmt.Errorf("agent: %w", err)
+	}
+
+	_ = os.Remove(socket)
+
+	listener, err := listen(socket)
+	if err != nil {
+		return nil, fmt.Errorf("agent: %w", err)
+	}
+
+	if err = os.Chmod(socket, 0o600); err != nil {
+		_ = listener.Close()
+
+		return nil, fmt.Errorf("agent: %w", err)
+	}
+
+	return listener, nil
+}
+
+// Serve keeps credentials in cache and answers requests until ctx is done
+// or stop request is received. The listener is closed on return.
+func Serve(ctx context.Context, listener net.Listener, cache *credcache.Cache) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("agent: %w", err)
+		}
+
+		if handle(conn, cache) {
+			cancel()
+		}
+	}
+}
+
+// handle answers the request of the connection. Returns true on stop
+// request.
+func handle(conn net.Conn, cache *credcache.Cache) bool {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(DefaultTimeout))
+
+	var (
+		req  Request
+		resp Response
+	)
+
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		resp.Error = err.Error()
+		_ = json.NewEncoder(conn).Encode(resp)
+
+		return false
+	}
+
+	switch req.Op {
+	case OpGet:
+		resp.Password, resp.OK = cache.Get(req.Address)
+	case OpSet:
+		cache.Set(req.Address, req.Password)
+		resp.OK = true
+	case OpStop:
+		resp.OK = true
+	default:
+		resp.Error = fmt.Sprintf("%s: %s", ErrUnknownOp, req.Op)
+	}
+
+	_ = json.NewEncoder(conn).Encode(resp)
+
+	return req.Op == OpStop
+}
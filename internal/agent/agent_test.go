@@ -0,0 +1,91 @@
+package agent_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/agent"
+	"github.com/gorcon/rcon-cli/internal/credcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgent(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "private", "agent.sock")
+
+	listener, err := agent.Listen(socket)
+	assert.NoError(t, err)
+
+	cache, err := credcache.New()
+	assert.NoError(t, err)
+
+	done := make(chan error)
+
+	go func() {
+		done <- agent.Serve(context.Background(), listener, cache)
+	}()
+
+	t.Run("get and set", func(t *testing.T) {
+		client := agent.NewClient(socket)
+
+		_, ok := client.Get("127.0.0.1:16260")
+		assert.False(t, ok)
+
+		client.Set("127.0.0.1:16260", "password")
+
+		password, ok := client.Get("127.0.0.1:16260")
+		assert.True(t, ok)
+		assert.Equal(t, "password", password)
+	})
+
+	t.Run("private directory", func(t *testing.T) {
+		info, err := os.Stat(filepath.Dir(socket))
+		if assert.NoError(t, err) {
+			assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+		}
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv(agent.EnvSocket, "")
+		assert.Nil(t, agent.FromEnv())
+
+		t.Setenv(agent.EnvSocket, socket)
+
+		password, ok := agent.FromEnv().Get("127.0.0.1:16260")
+		assert.True(t, ok)
+		assert.Equal(t, "password", password)
+	})
+
+	t.Run("running", func(t *testing.T) {
+		_, err := agent.Listen(socket)
+		assert.ErrorIs(t, err, agent.ErrRunning)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		assert.NoError(t, agent.NewClient(socket).Stop())
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("agent is not stopped")
+		}
+
+		_, ok := agent.NewClient(socket).Get("127.0.0.1:16260")
+		assert.False(t, ok)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var client *agent.Client
+
+		assert.NotPanics(t, func() {
+			client.Set("127.0.0.1:16260", "password")
+		})
+
+		_, ok := client.Get("127.0.0.1:16260")
+		assert.False(t, ok)
+		assert.NoError(t, client.Stop())
+	})
+}
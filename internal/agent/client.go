@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ErrAgent is returned when the agent responds with error.
+var ErrAgent = errors.New("agent error")
+
+// Client sends requests to the agent. Methods of nil Client do nothing, so
+// callers do not check if the agent is enabled.
+type Client struct {
+	socket string
+}
+
+// NewClient creates a new Client of the agent on socket.
+func NewClient(socket string) *Client {
+	return &Client{socket: socket}
+}
+
+// FromEnv creates a new Client of the agent on the socket from EnvSocket
+// variable. Returns nil if the variable is not set, so passwords are shared
+// only with the agent which is explicitly enabled.
+func FromEnv() *Client {
+	socket := os.Getenv(EnvSocket)
+	if socket == "" {
+		return nil
+	}
+
+	return NewClient(socket)
+}
+
+// Get returns the password of the address which is kept by the agent. The
+// second returned value is false if the password is not kept or the agent is
+// not running.
+func (c *Client) Get(address string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	resp, err := c.request(Request{Op: OpGet, Address: address})
+	if err != nil {
+		return "", false
+	}
+
+	return resp.Password, resp.OK
+}
+
+// Set passes the password of the address to the agent. Errors are ignored,
+// so passwords are not kept if the agent is not running.
+func (c *Client) Set(address, password string) {
+	if c == nil {
+		return
+	}
+
+	_, _ = c.request(Request{Op: OpSet, Address: address, Password: password})
+}
+
+// Stop stops the agent.
+func (c *Client) Stop() error {
+	if c == nil {
+		return nil
+	}
+
+	_, err := c.request(Request{Op: OpStop})
+
+	return err
+}
+
+// request sends req and reads the response. The request is not sent if the
+// socket is owned by another user.
+func (c *Client) request(req Request) (Response, error) {
+	var resp Response
+
+	info, err := os.Stat(c.socket)
+	if err != nil {
+		return resp, fmt.Errorf("agent: %w", err)
+	}
+
+	if !owned(info) {
+		return resp, fmt.Errorf("%w: %s", ErrNotOwned, c.socket)
+	}
+
+	conn, err := net.DialTimeout("unix", c.socket, DefaultTimeout)
+	if err != nil {
+		return resp, fmt.Errorf("agent: %w", err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(DefaultTimeout))
+
+	if err = json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, fmt.Errorf("agent: %w", err)
+	}
+
+	if err = json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("agent: %w", err)
+	}
+
+	if resp.Error != "" {
+		return resp, fmt.Errorf("%w: %s", ErrAgent, resp.Error)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package agent
+
+import (
+	"net"
+	"os"
+)
+
+// owned is not supported on this platform, access to the socket is
+// restricted by the permissions of its directory.
+func owned(_ os.FileInfo) bool {
+	return true
+}
+
+// listen listens on the socket.
+func listen(socket string) (net.Listener, error) {
+	return net.Listen("unix", socket) //nolint:wrapcheck // Wrapped by caller.
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package agent
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// owned returns true if the file is owned by the current user.
+func owned(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+
+	return ok && int(stat.Uid) == os.Getuid()
+}
+
+// listen listens on the socket which is created accessible by the current
+// user only, so there is no moment when other users can connect to it.
+func listen(socket string) (net.Listener, error) {
+	mask := syscall.Umask(0o177)
+	defer syscall.Umask(mask)
+
+	return net.Listen("unix", socket) //nolint:wrapcheck // Wrapped by caller.
+}
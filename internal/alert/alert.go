@@ -0,0 +1,211 @@
+// Package alert sends notifications to chat webhooks when lines of responses
+// match patterns, e.g. "Exception" or "server crashed".
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout limits time to deliver one notification.
+const DefaultTimeout = 10 * time.Second
+
+var (
+	// ErrInvalidAlert is returned when alert in the config is incomplete.
+	ErrInvalidAlert = errors.New("invalid alert")
+
+	// ErrNotify is returned when the webhook rejects the notification.
+	ErrNotify = errors.New("notify")
+)
+
+// Rule maps the pattern of response lines to the webhook which is notified
+// about matched lines.
+type Rule struct {
+	// Pattern is the regular expression which is matched against each line
+	// of responses.
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Notify is the URL of Discord or Slack incoming webhook. Any other URL
+	// receives the same JSON payload.
+	Notify string `json:"notify" yaml:"notify"`
+	// Cooldown suppresses repeated notifications of the alert for the
+	// duration, so flood of matched lines sends one message.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+// Rules maps alert names to rules.
+type Rules map[string]Rule
+
+// Validate checks that all rules have valid pattern and webhook URL.
+func (rules Rules) Validate() error {
+	for _, name := range rules.names() {
+		rule := rules[name]
+
+		if rule.Pattern == "" {
+			return fmt.Errorf("%w: %s has no pattern", ErrInvalidAlert, name)
+		}
+
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("%w: %s has invalid pattern: %s", ErrInvalidAlert, name, err)
+		}
+
+		if u, err := url.Parse(rule.Notify); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("%w: %s has no http or https notify URL", ErrInvalidAlert, name)
+		}
+	}
+
+	return nil
+}
+
+// names returns sorted names of rules.
+func (rules Rules) names() []string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Payload is JSON body of the notification. Discord reads content and Slack
+// reads text, other fields are for custom receivers.
+type Payload struct {
+	Content string `json:"content"`
+	Text    string `json:"text"`
+	Alert   string `json:"alert"`
+	Address string `json:"address"`
+	Line    string `json:"line"`
+}
+
+// Alerter matches responses against rules and sends notifications. Methods
+// of nil Alerter do nothing. It is safe for concurrent use.
+type Alerter struct {
+	names    []string
+	rules    Rules
+	patterns map[string]*regexp.Regexp
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// New compiles rules. Returns nil Alerter if rules are empty.
+func New(rules Rules) (*Alerter, error) {
+	if len(rules) == 0 {
+		return nil, nil //nolint:nilnil // Nil Alerter does nothing.
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &Alerter{
+		names:    rules.names(),
+		rules:    rules,
+		patterns: make(map[string]*regexp.Regexp, len(rules)),
+		timeout:  DefaultTimeout,
+		sent:     make(map[string]time.Time),
+	}
+
+	for name, rule := range rules {
+		a.patterns[name] = regexp.MustCompile(rule.Pattern)
+	}
+
+	return a, nil
+}
+
+// Check sends notification for each alert which pattern matches a line of
+// the response of the server at address. The first matched line of the
+// response is reported. Returns errors of delivery.
+func (a *Alerter) Check(address string, response string) error {
+	if a == nil || response == "" {
+		return nil
+	}
+
+	var errs []error
+
+	for _, name := range a.names {
+		line, ok := a.match(name, response)
+		if !ok || !a.ready(name) {
+			continue
+		}
+
+		if err := a.notify(name, address, line); err != nil {
+			errs = append(errs, fmt.Errorf("alert %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// match returns the first line of text which matches the alert pattern.
+func (a *Alerter) match(name string, text string) (string, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" && a.patterns[name].MatchString(line) {
+			return line, true
+		}
+	}
+
+	return "", false
+}
+
+// ready returns true and marks the alert as sent if its cooldown is over.
+func (a *Alerter) ready(name string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := a.sent[name]; ok && now.Sub(last) < a.rules[name].Cooldown {
+		return false
+	}
+
+	a.sent[name] = now
+
+	return true
+}
+
+// notify posts the payload to the webhook of the alert.
+func (a *Alerter) notify(name, address, line string) error {
+	message := fmt.Sprintf("[%s] %s: %s", name, address, line)
+
+	body, err := json.Marshal(Payload{Content: message, Text: message, Alert: name, Address: address, Line: line})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotify, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.rules[name].Notify, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotify, err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotify, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 { //nolint:gomnd // Any 2xx status is success.
+		text, _ := io.ReadAll(io.LimitReader(response.Body, 512)) //nolint:gomnd // Error message is short.
+
+		return fmt.Errorf("%w: %s: %s", ErrNotify, response.Status, strings.TrimSpace(string(text)))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package alert_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Validate(t *testing.T) {
+	assert.NoError(t, alert.Rules{"crash": {Pattern: "crashed", Notify: "https://discord.com/api/webhooks/1"}}.Validate())
+
+	err := alert.Rules{"crash": {Notify: "https://discord.com/api/webhooks/1"}}.Validate()
+	assert.ErrorIs(t, err, alert.ErrInvalidAlert)
+	assert.EqualError(t, err, "invalid alert: crash has no pattern")
+
+	err = alert.Rules{"crash": {Pattern: "(", Notify: "https://discord.com/api/webhooks/1"}}.Validate()
+	assert.ErrorIs(t, err, alert.ErrInvalidAlert)
+
+	err = alert.Rules{"crash": {Pattern: "crashed", Notify: "discord"}}.Validate()
+	assert.EqualError(t, err, "invalid alert: crash has no http or https notify URL")
+}
+
+func TestAlerter_Check(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		payloads []alert.Payload
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "invalid webhook", http.StatusNotFound)
+
+			return
+		}
+
+		var payload alert.Payload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	t.Run("nil", func(t *testing.T) {
+		a, err := alert.New(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, a)
+		assert.NoError(t, a.Check("127.0.0.1:16260", "Exception"))
+	})
+
+	t.Run("notify", func(t *testing.T) {
+		payloads = nil
+
+		a, err := alert.New(alert.Rules{
+			"exception": {Pattern: "Exception", Notify: server.URL, Cooldown: time.Hour},
+			"crash":     {Pattern: "(?i)server crashed", Notify: server.URL},
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, a.Check("127.0.0.1:16260", "Players: 0"))
+		assert.NoError(t, a.Check("127.0.0.1:16260", "ok\n NullReferenceException at Foo \nIndexException"))
+		assert.NoError(t, a.Check("127.0.0.1:16260", "ArgumentException"))
+		assert.NoError(t, a.Check("127.0.0.1:16260", "Server crashed"))
+		assert.NoError(t, a.Check("127.0.0.1:16260", "server crashed again"))
+
+		assert.Equal(t, []alert.Payload{
+			{
+				Content: "[exception] 127.0.0.1:16260: NullReferenceException at Foo",
+				Text:    "[exception] 127.0.0.1:16260: NullReferenceException at Foo",
+				Alert:   "exception", Address: "127.0.0.1:16260", Line: "NullReferenceException at Foo",
+			},
+			{
+				Content: "[crash] 127.0.0.1:16260: Server crashed",
+				Text:    "[crash] 127.0.0.1:16260: Server crashed",
+				Alert:   "crash", Address: "127.0.0.1:16260", Line: "Server crashed",
+			},
+			{
+				Content: "[crash] 127.0.0.1:16260: server crashed again",
+				Text:    "[crash] 127.0.0.1:16260: server crashed again",
+				Alert:   "crash", Address: "127.0.0.1:16260", Line: "server crashed again",
+			},
+		}, payloads)
+	})
+
+	t.Run("delivery error", func(t *testing.T) {
+		a, err := alert.New(alert.Rules{"crash": {Pattern: "crashed", Notify: server.URL + "/broken"}})
+		assert.NoError(t, err)
+
+		err = a.Check("127.0.0.1:16260", "crashed")
+		assert.ErrorIs(t, err, alert.ErrNotify)
+		assert.EqualError(t, err, "alert crash: notify: 404 Not Found: invalid webhook")
+	})
+}
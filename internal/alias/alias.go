@@ -0,0 +1,139 @@
+// Package alias expands user defined command shortcuts with arguments.
+//
+// Alias is called with exclamation mark prefix. Placeholders $1-$9 in the
+// alias template are replaced with positional arguments and $* is replaced
+// with all arguments. Example: alias `warn: say Restart in $1 minutes`
+// expands `!warn 5` to `say Restart in 5 minutes`.
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prefix marks the command as alias call.
+const Prefix = "!"
+
+var (
+	// ErrUnknownAlias is returned when called alias is not defined.
+	ErrUnknownAlias = errors.New("unknown alias")
+
+	// ErrMissingArgument is returned when alias is called with less
+	// arguments than its template requires.
+	ErrMissingArgument = errors.New("missing alias argument")
+
+	// ErrInvalidName is returned when alias name is empty or contains spaces.
+	ErrInvalidName = errors.New("invalid alias name")
+)
+
+// placeholder matches positional and all arguments placeholders.
+var placeholder = regexp.MustCompile(`\$([1-9*])`)
+
+// Aliases maps alias names to command templates.
+type Aliases map[string]string
+
+// IsCall returns true if command is an alias call.
+func IsCall(command string) bool {
+	return strings.HasPrefix(command, Prefix)
+}
+
+// Set defines alias name with template.
+func (a Aliases) Set(name string, template string) error {
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	a[name] = template
+
+	return nil
+}
+
+// Names returns sorted alias names.
+func (a Aliases) Names() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Expand replaces alias call with its template. Commands without alias
+// prefix are returned unchanged.
+func (a Aliases) Expand(command string) (string, error) {
+	if !IsCall(command) {
+		return command, nil
+	}
+
+	args := Split(strings.TrimPrefix(command, Prefix))
+	if len(args) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrUnknownAlias, command)
+	}
+
+	template, ok := a[args[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownAlias, args[0])
+	}
+
+	args = args[1:]
+
+	var err error
+
+	expanded := placeholder.ReplaceAllStringFunc(template, func(s string) string {
+		if s == "$*" {
+			return strings.Join(args, " ")
+		}
+
+		i, _ := strconv.Atoi(s[1:])
+		if i > len(args) {
+			err = fmt.Errorf("%w: %s requires %s", ErrMissingArgument, command, s)
+
+			return s
+		}
+
+		return args[i-1]
+	})
+
+	return strings.TrimSpace(expanded), err
+}
+
+// Split splits s to words by spaces. Double quoted parts are kept
+// as a single word with quotes removed.
+func Split(s string) []string {
+	var words []string
+
+	var word strings.Builder
+
+	quoted, started := false, false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if started {
+				words = append(words, word.String())
+				word.Reset()
+
+				started = false
+			}
+		default:
+			word.WriteRune(r)
+
+			started = true
+		}
+	}
+
+	if started {
+		words = append(words, word.String())
+	}
+
+	return words
+}
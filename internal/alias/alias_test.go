@@ -0,0 +1,55 @@
+package alias_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/alias"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliases_Expand(t *testing.T) {
+	aliases := alias.Aliases{
+		"warn":   "say Restart in $1 minutes",
+		"kick":   "kickuser \"$1\" -r \"$2\"",
+		"notify": "servermsg $*",
+	}
+
+	tests := []struct {
+		name     string
+		command  string
+		expected string
+	}{
+		{"not alias", "players", "players"},
+		{"positional argument", "!warn 5", "say Restart in 5 minutes"},
+		{"quoted arguments", "!kick \"John Doe\" \"spawn kill\"", "kickuser \"John Doe\" -r \"spawn kill\""},
+		{"all arguments", "!notify server restarts soon", "servermsg server restarts soon"},
+		{"extra arguments", "!warn 5 10", "say Restart in 5 minutes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := aliases.Expand(tt.command)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expanded)
+		})
+	}
+
+	t.Run("unknown alias", func(t *testing.T) {
+		_, err := aliases.Expand("!ban admin")
+		assert.ErrorIs(t, err, alias.ErrUnknownAlias)
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		_, err := aliases.Expand("!warn")
+		assert.ErrorIs(t, err, alias.ErrMissingArgument)
+	})
+}
+
+func TestAliases_Set(t *testing.T) {
+	aliases := alias.Aliases{}
+
+	assert.NoError(t, aliases.Set("save", "save-all"))
+	assert.ErrorIs(t, aliases.Set("save all", "save-all"), alias.ErrInvalidName)
+	assert.ErrorIs(t, aliases.Set("", "save-all"), alias.ErrInvalidName)
+	assert.Equal(t, []string{"save"}, aliases.Names())
+}
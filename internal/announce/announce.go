@@ -0,0 +1,149 @@
+// Package announce renders named message templates for server announcements.
+//
+// Template text uses text/template syntax, for example
+// `Server restarts at {{.Time}}`. A template can have texts for several
+// locales.
+package announce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is used when template has no text for requested locale.
+const DefaultLocale = "en"
+
+var (
+	// ErrUnknownTemplate is returned when template is not defined.
+	ErrUnknownTemplate = errors.New("unknown template")
+
+	// ErrUnknownLocale is returned when template has no text for requested
+	// and default locales.
+	ErrUnknownLocale = errors.New("unknown locale")
+
+	// ErrInvalidVar is returned when variable is not in key=value format.
+	ErrInvalidVar = errors.New("invalid variable: must be key=value")
+)
+
+// Template maps locales to template texts. In the config template can be
+// set as a single string for the default locale or as a map of locales.
+type Template map[string]string
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (t *Template) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = Template{DefaultLocale: node.Value}
+
+		return nil
+	}
+
+	texts := make(map[string]string)
+	if err := node.Decode(&texts); err != nil {
+		return err
+	}
+
+	*t = texts
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Template) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*t = Template{DefaultLocale: text}
+
+		return nil
+	}
+
+	texts := make(map[string]string)
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return err
+	}
+
+	*t = texts
+
+	return nil
+}
+
+// Templates maps template names to templates.
+type Templates map[string]Template
+
+// Validate returns an error if any template text can not be parsed.
+func (t Templates) Validate() error {
+	for name, texts := range t {
+		for locale, text := range texts {
+			if _, err := template.New(name).Parse(text); err != nil {
+				return fmt.Errorf("template %s (%s): %w", name, locale, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Names returns sorted template names.
+func (t Templates) Names() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Render renders template name for locale with variables vars. Text for
+// DefaultLocale is used if template has no text for locale.
+func (t Templates) Render(name string, locale string, vars map[string]string) (string, error) {
+	texts, ok := t[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	text, ok := texts[locale]
+	if !ok {
+		if text, ok = texts[DefaultLocale]; !ok {
+			return "", fmt.Errorf("%w: %s for %s template", ErrUnknownLocale, locale, name)
+		}
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+
+	var b strings.Builder
+	if err = tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+
+	return b.String(), nil
+}
+
+// ParseVars converts key=value pairs to variables map.
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidVar, pair)
+		}
+
+		vars[key] = value
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,133 @@
+package announce_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/announce"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTemplates(t *testing.T) {
+	templates := announce.Templates{
+		"restart": {"en": "Server restarts at {{.Time}}", "ru": "Рестарт сервера в {{.Time}}"},
+		"rules":   {"en": "Be nice"},
+	}
+
+	t.Run("render", func(t *testing.T) {
+		vars := map[string]string{"Time": "18:00"}
+
+		text, err := templates.Render("restart", "", vars)
+		assert.NoError(t, err)
+		assert.Equal(t, "Server restarts at 18:00", text)
+
+		text, err = templates.Render("restart", "ru", vars)
+		assert.NoError(t, err)
+		assert.Equal(t, "Рестарт сервера в 18:00", text)
+
+		text, err = templates.Render("rules", "ru", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Be nice", text)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := templates.Render("unknown", "", nil)
+		assert.ErrorIs(t, err, announce.ErrUnknownTemplate)
+
+		_, err = announce.Templates{"ru": {"ru": "Привет"}}.Render("ru", "de", nil)
+		assert.ErrorIs(t, err, announce.ErrUnknownLocale)
+
+		_, err = templates.Render("restart", "", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		assert.NoError(t, templates.Validate())
+		assert.Error(t, announce.Templates{"bad": {"en": "{{.Time"}}.Validate())
+	})
+
+	t.Run("names", func(t *testing.T) {
+		assert.Equal(t, []string{"restart", "rules"}, templates.Names())
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		var templates announce.Templates
+
+		err := yaml.Unmarshal([]byte("rules: Be nice\nrestart:\n  en: Restart\n  ru: Рестарт\n"), &templates)
+		assert.NoError(t, err)
+		assert.Equal(t, announce.Templates{
+			"rules":   {"en": "Be nice"},
+			"restart": {"en": "Restart", "ru": "Рестарт"},
+		}, templates)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var templates announce.Templates
+
+		err := json.Unmarshal([]byte(`{"rules": "Be nice", "restart": {"en": "Restart", "ru": "Рестарт"}}`), &templates)
+		assert.NoError(t, err)
+		assert.Equal(t, announce.Templates{
+			"rules":   {"en": "Be nice"},
+			"restart": {"en": "Restart", "ru": "Рестарт"},
+		}, templates)
+	})
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := announce.ParseVars([]string{"Time=18:00", "Reason=update=1.2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Time": "18:00", "Reason": "update=1.2"}, vars)
+
+	_, err = announce.ParseVars([]string{"Time"})
+	assert.ErrorIs(t, err, announce.ErrInvalidVar)
+}
+
+func TestSchedules_Validate(t *testing.T) {
+	templates := announce.Templates{"rules": {announce.DefaultLocale: "Be nice"}}
+
+	err := announce.Schedules{
+		"rules": {Cron: "0 */2 * * *", Envs: []string{"@eu"}, Template: "rules", Jitter: time.Minute},
+		"vote":  {Cron: "@daily", Envs: []string{"eu1"}, Message: "Vote for the server"},
+	}.Validate(templates)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		schedule announce.Schedule
+		msg      string
+	}{
+		{"cron", announce.Schedule{Cron: "0 * *", Envs: []string{"eu1"}, Message: "hi"}, "invalid cron schedule"},
+		{"envs", announce.Schedule{Cron: "@hourly", Message: "hi"}, "rules has no envs"},
+		{"text", announce.Schedule{Cron: "@hourly", Envs: []string{"eu1"}}, "rules has no template or message"},
+		{"jitter", announce.Schedule{Cron: "@hourly", Envs: []string{"eu1"}, Message: "hi", Jitter: -1}, "negative jitter"},
+		{
+			"template",
+			announce.Schedule{Cron: "@hourly", Envs: []string{"eu1"}, Template: "motd"},
+			"rules uses unknown template motd",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := announce.Schedules{"rules": test.schedule}.Validate(templates)
+			assert.ErrorIs(t, err, announce.ErrInvalidSchedule)
+			assert.ErrorContains(t, err, test.msg)
+		})
+	}
+}
+
+func TestSchedule_Text(t *testing.T) {
+	templates := announce.Templates{"rules": {announce.DefaultLocale: "Rules: {{.URL}}", "ru": "Правила: {{.URL}}"}}
+
+	text, err := announce.Schedule{Template: "rules", Vars: map[string]string{"URL": "example.com"}}.Text(templates, "ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Правила: example.com", text)
+
+	text, err = announce.Schedule{Message: "Vote for the server"}.Text(templates, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Vote for the server", text)
+}
@@ -0,0 +1,82 @@
+package announce
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/cron"
+)
+
+// ErrInvalidSchedule is returned when recurring announcement in the config
+// is incomplete.
+var ErrInvalidSchedule = errors.New("invalid announcement schedule")
+
+// Schedule is the recurring announcement which is broadcast by crontab
+// schedule to environments.
+type Schedule struct {
+	// Cron is the crontab schedule, e.g. "0 */2 * * *" every 2 hours.
+	Cron string `json:"cron" yaml:"cron"`
+	// Envs contains environments and groups with @ prefix.
+	Envs []string `json:"envs" yaml:"envs"`
+	// Template is the name of the announcement template. Message is used
+	// if template is not set.
+	Template string            `json:"template" yaml:"template"`
+	Vars     map[string]string `json:"vars" yaml:"vars"`
+	Message  string            `json:"message" yaml:"message"`
+	// Jitter delays each announcement for random duration up to jitter, so
+	// servers do not announce at the same second.
+	Jitter time.Duration `json:"jitter" yaml:"jitter"`
+}
+
+// Schedules maps names of recurring announcements to schedules.
+type Schedules map[string]Schedule
+
+// Validate checks that all schedules have valid cron, environments and
+// template from templates or message.
+func (s Schedules) Validate(templates Templates) error {
+	for _, name := range s.Names() {
+		schedule := s[name]
+
+		if _, err := cron.Parse(schedule.Cron); err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidSchedule, name, err)
+		}
+
+		switch {
+		case len(schedule.Envs) == 0:
+			return fmt.Errorf("%w: %s has no envs", ErrInvalidSchedule, name)
+		case schedule.Template == "" && schedule.Message == "":
+			return fmt.Errorf("%w: %s has no template or message", ErrInvalidSchedule, name)
+		case schedule.Jitter < 0:
+			return fmt.Errorf("%w: %s has negative jitter", ErrInvalidSchedule, name)
+		}
+
+		if _, ok := templates[schedule.Template]; schedule.Template != "" && !ok {
+			return fmt.Errorf("%w: %s uses %w %s", ErrInvalidSchedule, name, ErrUnknownTemplate, schedule.Template)
+		}
+	}
+
+	return nil
+}
+
+// Names returns sorted names of schedules.
+func (s Schedules) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Text returns the message or renders the template for locale.
+func (s Schedule) Text(templates Templates, locale string) (string, error) {
+	if s.Template == "" {
+		return s.Message, nil
+	}
+
+	return templates.Render(s.Template, locale, s.Vars)
+}
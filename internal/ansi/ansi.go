@@ -0,0 +1,24 @@
+// Package ansi removes terminal escape sequences and game color codes
+// from text.
+package ansi
+
+import "regexp"
+
+// codes matches ANSI CSI sequences ending with a letter like colors, OSC
+// sequences ending with BEL like window titles and color codes of game
+// servers: Minecraft section sign codes (§a), Quake style codes (^1), Unity
+// rich text tags used by Rust (<color=red>) and 7 Days to Die hex codes
+// ([ff0000]). Other escape sequences are kept.
+var codes = regexp.MustCompile(
+	`\x1b\[[0-9;?]*[A-Za-z]` +
+		`|\x1b\][^\x07]*\x07` +
+		`|§[0-9a-fk-orA-FK-OR]` +
+		`|\^[0-9]` +
+		`|</?(?:color|b|i|size)(?:=[^>]*)?>` +
+		`|\[(?:[0-9a-fA-F]{6}|-)\]`,
+)
+
+// Strip returns text without escape sequences and color codes.
+func Strip(text string) string {
+	return codes.ReplaceAllString(text, "")
+}
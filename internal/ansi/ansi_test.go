@@ -0,0 +1,31 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"plain text", "There are 2 players online", "There are 2 players online"},
+		{"ansi colors", "\x1b[31mError\x1b[0m: \x1b[1;32mok\x1b[m", "Error: ok"},
+		{"ansi title", "\x1b]0;title\x07text", "text"},
+		{"not csi", "\x1b[200~[INFO] done", "\x1b[200~[INFO] done"},
+		{"minecraft", "§6There are §c2§6 players online", "There are 2 players online"},
+		{"quake", "^1Red ^7White", "Red White"},
+		{"rich text", "<color=#ff0000>Admin</color>: <b>hello</b>", "Admin: hello"},
+		{"7 days to die", "[ff0000]Warning[-] text [not a color]", "Warning text [not a color]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ansi.Strip(tt.text))
+		})
+	}
+}
@@ -0,0 +1,453 @@
+// Package batch parses batch files of commands for remote servers.
+//
+// Batch file is UTF-8 text. The byte order mark at the beginning of the file
+// is skipped, lines are ended with LF or CRLF. Each line is trimmed and
+// contains one command, blank lines and lines started with # are skipped.
+// Line ended with \ is continued on the next line: the backslash is removed
+// and the next line is appended without its leading spaces. Lines started
+// with @ are directives:
+//
+//	@sleep 30s   pause execution for the duration;
+//	@repeat 3    execute the next command several times;
+//	@env other   send the next commands to the server from another config
+//	             environment;
+//	@expect /Saved/ 1m
+//	             send the next command and wait until its response or the
+//	             console output has the line matching the regular expression,
+//	             the timeout is optional;
+//	@capture /steamid (?P<steamid>\d+)/
+//	             store named groups of the first match in the response of the
+//	             next command to variables;
+//	@if /0 players/
+//	             execute the next lines up to @else or @end if the response of
+//	             the previous command matches the regular expression, @if
+//	             !/regex/ executes them if it does not match;
+//	@else        execute the next lines up to @end if the condition of @if
+//	             is false;
+//	@end         end the block of @if, blocks can be nested.
+//
+// Variables are used in the next commands as ${name}. The variable which is
+// not captured by the previous lines is the error of the batch file.
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Batch file directives.
+const (
+	// DirectiveSleep pauses execution. Example: `@sleep 30s`.
+	DirectiveSleep = "@sleep"
+
+	// DirectiveRepeat executes the next command several times.
+	// Example: `@repeat 3`.
+	DirectiveRepeat = "@repeat"
+
+	// DirectiveEnv switches the next commands to another config environment.
+	// Example: `@env other`.
+	DirectiveEnv = "@env"
+
+	// DirectiveExpect waits for the output of the next command.
+	// Example: `@expect /Saved/ 1m`.
+	DirectiveExpect = "@expect"
+
+	// DirectiveCapture stores named groups of the response of the next
+	// command to variables. Example: `@capture /id=(?P<id>\d+)/`.
+	DirectiveCapture = "@capture"
+
+	// DirectiveIf executes the block if the response of the previous command
+	// matches the pattern. Example: `@if /0 players/`.
+	DirectiveIf = "@if"
+
+	// DirectiveElse starts the block which is executed if the condition of
+	// DirectiveIf is false.
+	DirectiveElse = "@else"
+
+	// DirectiveEnd ends the block of DirectiveIf.
+	DirectiveEnd = "@end"
+)
+
+// Special characters of the format.
+const (
+	Comment      = "#"
+	Continuation = `\`
+	Directive    = "@"
+	Delimiter    = "/"
+	Not          = "!"
+)
+
+var (
+	// ErrInvalidDirective is returned when batch file contains unknown or
+	// malformed directive.
+	ErrInvalidDirective = errors.New("invalid directive")
+
+	// ErrUnterminatedLine is returned when the last line of batch file is
+	// continued.
+	ErrUnterminatedLine = errors.New("unterminated line continuation")
+
+	// ErrUnknownVariable is returned when the command uses the variable
+	// which is not captured.
+	ErrUnknownVariable = errors.New("unknown variable")
+
+	// ErrNotCaptured is returned when the response does not match the
+	// pattern of DirectiveCapture.
+	ErrNotCaptured = errors.New("response does not match capture pattern")
+
+	// ErrUnbalancedBlock is returned when DirectiveElse or DirectiveEnd has
+	// no DirectiveIf or the block of DirectiveIf is not ended.
+	ErrUnbalancedBlock = errors.New("unbalanced block")
+)
+
+// bom is UTF-8 byte order mark which is added by some Windows editors.
+//
+//nolint:gochecknoglobals // Read only sequence of bytes.
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// variable matches the variable of the command.
+//
+//nolint:gochecknoglobals // Compiled once.
+var variable = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// Line is the command or the directive of batch file.
+type Line struct {
+	// Number is the number of the first line in the file, starting from 1.
+	Number int
+	// Command is the command to execute. It is empty for directives.
+	Command string
+	// Directive is one of DirectiveSleep, DirectiveRepeat and DirectiveEnv.
+	Directive string
+	// Duration is the argument of DirectiveSleep and the timeout of
+	// DirectiveExpect. Zero timeout means the default one.
+	Duration time.Duration
+	// Count is the argument of DirectiveRepeat.
+	Count int
+	// Env is the argument of DirectiveEnv.
+	Env string
+	// Pattern is the regular expression of DirectiveExpect,
+	// DirectiveCapture and DirectiveIf.
+	Pattern string
+	// Not negates the condition of DirectiveIf.
+	Not bool
+}
+
+// Parse reads batch file from r and returns its commands and directives.
+// Errors contain the number of the invalid line.
+func Parse(r io.Reader) ([]Line, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("batch: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, bom)))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(data)+1)
+
+	var (
+		lines   []Line
+		logical strings.Builder
+		start   int
+	)
+
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if logical.Len() == 0 {
+			if text == "" || strings.HasPrefix(text, Comment) {
+				continue
+			}
+
+			start = number
+		}
+
+		if strings.HasSuffix(text, Continuation) {
+			logical.WriteString(strings.TrimSuffix(text, Continuation))
+
+			continue
+		}
+
+		logical.WriteString(text)
+
+		var line Line
+		if line, err = parseLine(start, strings.TrimSpace(logical.String())); err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, line)
+
+		logical.Reset()
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("batch: %w", err)
+	}
+
+	if logical.Len() != 0 {
+		return nil, fmt.Errorf("%w on line %d", ErrUnterminatedLine, start)
+	}
+
+	if err = checkBlocks(lines); err != nil {
+		return nil, err
+	}
+
+	if err = checkVariables(lines); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// parseLine parses the command or the directive.
+func parseLine(number int, text string) (Line, error) {
+	line := Line{Number: number}
+
+	if !strings.HasPrefix(text, Directive) {
+		line.Command = text
+
+		return line, nil
+	}
+
+	directive, arg := text, ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i != -1 {
+		directive, arg = text[:i], strings.TrimSpace(text[i:])
+	}
+
+	line.Directive = directive
+
+	var err error
+
+	switch directive {
+	case DirectiveSleep:
+		if line.Duration, err = time.ParseDuration(arg); err != nil || line.Duration < 0 {
+			return line, fmt.Errorf("%w on line %d: sleep duration must be like 30s", ErrInvalidDirective, number)
+		}
+	case DirectiveRepeat:
+		if line.Count, err = strconv.Atoi(arg); err != nil || line.Count < 1 {
+			return line, fmt.Errorf("%w on line %d: repeat count must be positive number", ErrInvalidDirective, number)
+		}
+	case DirectiveEnv:
+		if line.Env = arg; arg == "" {
+			return line, fmt.Errorf("%w on line %d: env name is required", ErrInvalidDirective, number)
+		}
+	case DirectiveExpect:
+		return parseExpect(line, arg)
+	case DirectiveCapture:
+		return parseCapture(line, arg)
+	case DirectiveIf:
+		return parseIf(line, arg)
+	case DirectiveElse, DirectiveEnd:
+		if arg != "" {
+			return line, fmt.Errorf("%w on line %d: %s has no arguments", ErrInvalidDirective, number, directive)
+		}
+	default:
+		return line, fmt.Errorf("%w on line %d: %s", ErrInvalidDirective, number, directive)
+	}
+
+	return line, nil
+}
+
+// parseExpect parses the pattern and the optional timeout of
+// DirectiveExpect.
+func parseExpect(line Line, arg string) (Line, error) {
+	re, rest, err := parsePattern(line.Number, "expect", arg)
+	if err != nil {
+		return line, err
+	}
+
+	line.Pattern = re.String()
+
+	if rest != "" {
+		if line.Duration, err = time.ParseDuration(rest); err != nil || line.Duration <= 0 {
+			return line, fmt.Errorf("%w on line %d: expect timeout must be like 1m", ErrInvalidDirective, line.Number)
+		}
+	}
+
+	return line, nil
+}
+
+// parseCapture parses the pattern of DirectiveCapture which must have named
+// groups.
+func parseCapture(line Line, arg string) (Line, error) {
+	re, rest, err := parsePattern(line.Number, "capture", arg)
+	if err != nil {
+		return line, err
+	}
+
+	if rest != "" || len(names(re)) == 0 {
+		return line, fmt.Errorf("%w on line %d: capture pattern must be like /(?P<name>regex)/",
+			ErrInvalidDirective, line.Number)
+	}
+
+	line.Pattern = re.String()
+
+	return line, nil
+}
+
+// parseIf parses the pattern of DirectiveIf which is negated with Not.
+func parseIf(line Line, arg string) (Line, error) {
+	if strings.HasPrefix(arg, Not) {
+		line.Not, arg = true, strings.TrimSpace(strings.TrimPrefix(arg, Not))
+	}
+
+	re, rest, err := parsePattern(line.Number, "if", arg)
+	if err != nil {
+		return line, err
+	}
+
+	if rest != "" {
+		return line, fmt.Errorf("%w on line %d: if pattern must be like /regex/", ErrInvalidDirective, line.Number)
+	}
+
+	line.Pattern = re.String()
+
+	return line, nil
+}
+
+// parsePattern parses the regular expression between delimiters at the
+// beginning of arg of the directive and returns the rest of arg.
+func parsePattern(number int, directive, arg string) (*regexp.Regexp, string, error) {
+	end := strings.LastIndex(arg, Delimiter)
+	if !strings.HasPrefix(arg, Delimiter) || end == 0 {
+		return nil, "", fmt.Errorf("%w on line %d: %s pattern must be like /regex/", ErrInvalidDirective, number, directive)
+	}
+
+	pattern := arg[1:end]
+
+	re, err := regexp.Compile(pattern)
+	if err != nil || pattern == "" {
+		return nil, "", fmt.Errorf("%w on line %d: invalid %s pattern %q", ErrInvalidDirective, number, directive, pattern)
+	}
+
+	return re, strings.TrimSpace(arg[end+1:]), nil
+}
+
+// names returns names of named groups of re.
+func names(re *regexp.Regexp) []string {
+	var result []string
+
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// checkBlocks returns an error if blocks of DirectiveIf are not balanced.
+func checkBlocks(lines []Line) error {
+	// Lines of DirectiveIf of open blocks and whether they have DirectiveElse.
+	var (
+		open     []int
+		withElse []bool
+	)
+
+	for _, line := range lines {
+		switch line.Directive {
+		case DirectiveIf:
+			open, withElse = append(open, line.Number), append(withElse, false)
+		case DirectiveElse:
+			if len(open) == 0 || withElse[len(withElse)-1] {
+				return fmt.Errorf("%w on line %d: %s without %s", ErrUnbalancedBlock, line.Number, DirectiveElse, DirectiveIf)
+			}
+
+			withElse[len(withElse)-1] = true
+		case DirectiveEnd:
+			if len(open) == 0 {
+				return fmt.Errorf("%w on line %d: %s without %s", ErrUnbalancedBlock, line.Number, DirectiveEnd, DirectiveIf)
+			}
+
+			open, withElse = open[:len(open)-1], withElse[:len(withElse)-1]
+		}
+	}
+
+	if len(open) != 0 {
+		return fmt.Errorf("%w on line %d: %s without %s", ErrUnbalancedBlock, open[len(open)-1], DirectiveIf, DirectiveEnd)
+	}
+
+	return nil
+}
+
+// checkVariables returns an error if the command uses the variable which is
+// not captured by the previous lines.
+func checkVariables(lines []Line) error {
+	captured := make(map[string]bool)
+
+	for _, line := range lines {
+		if line.Directive == DirectiveCapture {
+			for _, name := range names(regexp.MustCompile(line.Pattern)) {
+				captured[name] = true
+			}
+		}
+
+		for _, match := range variable.FindAllStringSubmatch(line.Command, -1) {
+			if !captured[match[1]] {
+				return fmt.Errorf("%w on line %d: %s is not captured", ErrUnknownVariable, line.Number, match[0])
+			}
+		}
+	}
+
+	return nil
+}
+
+// Capture stores named groups of the first match of pattern in text to vars.
+func Capture(pattern, text string, vars map[string]string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return fmt.Errorf("%w /%s/", ErrNotCaptured, pattern)
+	}
+
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			vars[name] = match[i]
+		}
+	}
+
+	return nil
+}
+
+// Expand replaces variables of the command with values of vars.
+func Expand(command string, vars map[string]string) (string, error) {
+	var err error
+
+	expanded := variable.ReplaceAllStringFunc(command, func(v string) string {
+		value, ok := vars[variable.FindStringSubmatch(v)[1]]
+		if !ok && err == nil {
+			err = fmt.Errorf("%w: %s", ErrUnknownVariable, v)
+		}
+
+		return value
+	})
+
+	return expanded, err
+}
+
+// Count returns the number of commands which are sent when lines are
+// executed, repeated commands are counted several times.
+func Count(lines []Line) int {
+	count, repeat := 0, 1
+
+	for _, line := range lines {
+		switch {
+		case line.Directive == DirectiveRepeat:
+			repeat = line.Count
+		case line.Directive == "":
+			count += repeat
+			repeat = 1
+		}
+	}
+
+	return count
+}
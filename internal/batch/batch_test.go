@@ -0,0 +1,245 @@
+package batch_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/batch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []batch.Line
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "comments and blank lines",
+			input: "# comment\n\n  \t\nstatus\n  # indented comment\n",
+			want:  []batch.Line{{Number: 4, Command: "status"}},
+		},
+		{
+			name:  "trimmed lines",
+			input: "  say hello  \nplayers",
+			want:  []batch.Line{{Number: 1, Command: "say hello"}, {Number: 2, Command: "players"}},
+		},
+		{
+			name:  "byte order mark",
+			input: "\xEF\xBB\xBFstatus\n",
+			want:  []batch.Line{{Number: 1, Command: "status"}},
+		},
+		{
+			name:  "windows line endings",
+			input: "status\r\n\r\nsay hello\r\n",
+			want:  []batch.Line{{Number: 1, Command: "status"}, {Number: 3, Command: "say hello"}},
+		},
+		{
+			name:  "line continuation",
+			input: "say hello \\\n   world \\\n  !\nstatus\n",
+			want:  []batch.Line{{Number: 1, Command: "say hello world !"}, {Number: 4, Command: "status"}},
+		},
+		{
+			name:  "continuation with hash",
+			input: "say \\\n# not a comment\n",
+			want:  []batch.Line{{Number: 1, Command: "say # not a comment"}},
+		},
+		{
+			name:  "directives",
+			input: "@sleep 30s\n@repeat\t3\n@env   lobby\n",
+			want: []batch.Line{
+				{Number: 1, Directive: batch.DirectiveSleep, Duration: 30 * time.Second},
+				{Number: 2, Directive: batch.DirectiveRepeat, Count: 3},
+				{Number: 3, Directive: batch.DirectiveEnv, Env: "lobby"},
+			},
+		},
+		{
+			name:  "capture and variables",
+			input: "@capture /id=(?P<id>\\d+), (?P<name>\\w+)/\nlp\nkick ${id} ${name}\n",
+			want: []batch.Line{
+				{Number: 1, Directive: batch.DirectiveCapture, Pattern: `id=(?P<id>\d+), (?P<name>\w+)`},
+				{Number: 2, Command: "lp"},
+				{Number: 3, Command: "kick ${id} ${name}"},
+			},
+		},
+		{
+			name:  "conditions",
+			input: "players\n@if /^0 players/\nquit\n@else\n@if ! /admin/\nsay hi\n@end\n@end\n",
+			want: []batch.Line{
+				{Number: 1, Command: "players"},
+				{Number: 2, Directive: batch.DirectiveIf, Pattern: "^0 players"},
+				{Number: 3, Command: "quit"},
+				{Number: 4, Directive: batch.DirectiveElse},
+				{Number: 5, Directive: batch.DirectiveIf, Pattern: "admin", Not: true},
+				{Number: 6, Command: "say hi"},
+				{Number: 7, Directive: batch.DirectiveEnd},
+				{Number: 8, Directive: batch.DirectiveEnd},
+			},
+		},
+		{
+			name:  "expect",
+			input: "@expect /Saved \\d+ ents/\n@expect /a/b/ 1m\n",
+			want: []batch.Line{
+				{Number: 1, Directive: batch.DirectiveExpect, Pattern: `Saved \d+ ents`},
+				{Number: 2, Directive: batch.DirectiveExpect, Pattern: "a/b", Duration: time.Minute},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines, err := batch.Parse(strings.NewReader(test.input))
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, lines)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+		msg   string
+	}{
+		{
+			name:  "unknown directive",
+			input: "status\n@unknown 1\n",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 2: @unknown",
+		},
+		{
+			name:  "invalid sleep",
+			input: "@sleep soon",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 1: sleep duration must be like 30s",
+		},
+		{
+			name:  "negative sleep",
+			input: "@sleep -1s",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 1: sleep duration must be like 30s",
+		},
+		{
+			name:  "invalid repeat",
+			input: "\n\n@repeat 0",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 3: repeat count must be positive number",
+		},
+		{
+			name:  "empty env",
+			input: "@env",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 1: env name is required",
+		},
+		{
+			name:  "expect without delimiters",
+			input: "@expect Saved",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 1: expect pattern must be like /regex/",
+		},
+		{
+			name:  "invalid expect pattern",
+			input: "@expect /(/",
+			err:   batch.ErrInvalidDirective,
+			msg:   `invalid directive on line 1: invalid expect pattern "("`,
+		},
+		{
+			name:  "invalid expect timeout",
+			input: "@expect /Saved/ soon",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 1: expect timeout must be like 1m",
+		},
+		{
+			name:  "capture without named groups",
+			input: "@capture /id=(\\d+)/",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 1: capture pattern must be like /(?P<name>regex)/",
+		},
+		{
+			name:  "variable is not captured",
+			input: "kick ${id}\n@capture /(?P<id>\\d+)/\nlp\n",
+			err:   batch.ErrUnknownVariable,
+			msg:   "unknown variable on line 1: ${id} is not captured",
+		},
+		{
+			name:  "if without pattern",
+			input: "players\n@if 0 players\n@end\n",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 2: if pattern must be like /regex/",
+		},
+		{
+			name:  "else with argument",
+			input: "@if /0/\n@else /1/\n@end\n",
+			err:   batch.ErrInvalidDirective,
+			msg:   "invalid directive on line 2: @else has no arguments",
+		},
+		{
+			name:  "end without if",
+			input: "players\n@end\n",
+			err:   batch.ErrUnbalancedBlock,
+			msg:   "unbalanced block on line 2: @end without @if",
+		},
+		{
+			name:  "second else",
+			input: "@if /0/\n@else\n@else\n@end\n",
+			err:   batch.ErrUnbalancedBlock,
+			msg:   "unbalanced block on line 3: @else without @if",
+		},
+		{
+			name:  "if without end",
+			input: "@if /0/\nquit\n@if /1/\n@end\n",
+			err:   batch.ErrUnbalancedBlock,
+			msg:   "unbalanced block on line 1: @if without @end",
+		},
+		{
+			name:  "unterminated continuation",
+			input: "status\nsay hello \\\n",
+			err:   batch.ErrUnterminatedLine,
+			msg:   "unterminated line continuation on line 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := batch.Parse(strings.NewReader(test.input))
+			assert.ErrorIs(t, err, test.err)
+			assert.EqualError(t, err, test.msg)
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	vars := map[string]string{"id": "1"}
+
+	err := batch.Capture(`id=(?P<id>\d+), (?P<name>\w+)`, "1. id=171, Alice, pos=(0, 0, 0)\n2. id=172, Bob", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "171", "name": "Alice"}, vars)
+
+	err = batch.Capture(`steamid=(?P<steamid>\d+)`, "no players", vars)
+	assert.ErrorIs(t, err, batch.ErrNotCaptured)
+	assert.Equal(t, map[string]string{"id": "171", "name": "Alice"}, vars)
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"id": "171", "name": "Alice"}
+
+	command, err := batch.Expand("kick ${id} \"${name} $id\"", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, `kick 171 "Alice $id"`, command)
+
+	_, err = batch.Expand("banid ${steamid}", vars)
+	assert.ErrorIs(t, err, batch.ErrUnknownVariable)
+}
+
+func TestCount(t *testing.T) {
+	lines, err := batch.Parse(strings.NewReader("status\n@repeat 3\n@sleep 1s\nsave\n@env lobby\nplayers\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, batch.Count(lines))
+}
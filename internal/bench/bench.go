@@ -0,0 +1,105 @@
+// Package bench calculates latency statistics of repeated requests.
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRate is returned when rate of requests can not be parsed.
+var ErrInvalidRate = errors.New("invalid rate, expected number of requests per s, m or h, e.g. 100/s")
+
+// Result contains latency statistics of successful requests and the number
+// of failed ones.
+type Result struct {
+	Count  int
+	Errors int
+	Min    time.Duration
+	Avg    time.Duration
+	P95    time.Duration
+	Max    time.Duration
+}
+
+// Summarize calculates statistics of latencies of successful requests.
+func Summarize(latencies []time.Duration, failed int) Result {
+	result := Result{Count: len(latencies) + failed, Errors: failed}
+	if len(latencies) == 0 {
+		return result
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, latency := range sorted {
+		sum += latency
+	}
+
+	const p95 = 0.95
+
+	result.Min = sorted[0]
+	result.Avg = sum / time.Duration(len(sorted))
+	result.P95 = Percentile(sorted, p95)
+	result.Max = sorted[len(sorted)-1]
+
+	return result
+}
+
+// Percentile returns p-th percentile of sorted latencies with nearest-rank
+// method. p must be in range (0, 1].
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[min(rank, len(sorted))-1]
+}
+
+// String returns statistics as text.
+func (r Result) String() string {
+	return fmt.Sprintf("%d requests, %d errors\nmin %s, avg %s, p95 %s, max %s",
+		r.Count, r.Errors, round(r.Min), round(r.Avg), round(r.P95), round(r.Max))
+}
+
+// round rounds d to microseconds for readable output.
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
+
+// ParseRate parses rate of requests like "100/s", "600/m" or "100" which
+// means per second. Returns interval between requests.
+func ParseRate(rate string) (time.Duration, error) {
+	number, unit, found := strings.Cut(rate, "/")
+
+	per := time.Second
+
+	if found {
+		switch unit {
+		case "s":
+		case "m":
+			per = time.Minute
+		case "h":
+			per = time.Hour
+		default:
+			return 0, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+		}
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+
+	return per / time.Duration(n), nil
+}
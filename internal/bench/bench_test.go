@@ -0,0 +1,70 @@
+package bench_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/bench"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Run("latencies", func(t *testing.T) {
+		latencies := make([]time.Duration, 0, 20)
+		for i := 20; i > 0; i-- {
+			latencies = append(latencies, time.Duration(i)*time.Millisecond)
+		}
+
+		result := bench.Summarize(latencies, 2)
+		assert.Equal(t, bench.Result{
+			Count:  22,
+			Errors: 2,
+			Min:    time.Millisecond,
+			Avg:    10500 * time.Microsecond,
+			P95:    19 * time.Millisecond,
+			Max:    20 * time.Millisecond,
+		}, result)
+		assert.Equal(t, "22 requests, 2 errors\nmin 1ms, avg 10.5ms, p95 19ms, max 20ms", result.String())
+		assert.Equal(t, 20*time.Millisecond, latencies[0], "input must not be sorted")
+	})
+
+	t.Run("only errors", func(t *testing.T) {
+		assert.Equal(t, bench.Result{Count: 3, Errors: 3}, bench.Summarize(nil, 3))
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4}
+
+	assert.Equal(t, time.Duration(0), bench.Percentile(nil, 0.95))
+	assert.Equal(t, time.Duration(4), bench.Percentile(sorted, 0.95))
+	assert.Equal(t, time.Duration(2), bench.Percentile(sorted, 0.5))
+	assert.Equal(t, time.Duration(1), bench.Percentile(sorted, 0.01))
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		rate     string
+		interval time.Duration
+	}{
+		{"100/s", 10 * time.Millisecond},
+		{"100", 10 * time.Millisecond},
+		{"600/m", 100 * time.Millisecond},
+		{"2/h", 30 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			interval, err := bench.ParseRate(tt.rate)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.interval, interval)
+		})
+	}
+
+	for _, rate := range []string{"", "0/s", "-1", "10/d", "fast"} {
+		t.Run("invalid "+rate, func(t *testing.T) {
+			_, err := bench.ParseRate(rate)
+			assert.ErrorIs(t, err, bench.ErrInvalidRate)
+		})
+	}
+}
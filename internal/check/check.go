@@ -0,0 +1,119 @@
+// Package check evaluates metrics of the server against thresholds and
+// formats them as Nagios plugin output with performance data, which is
+// understood by Icinga, Naemon and other classic monitoring systems.
+package check
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status is the state of the check. Values are exit codes of Nagios plugins.
+type Status int
+
+// Statuses of the check.
+const (
+	OK Status = iota
+	Warning
+	Critical
+	Unknown
+)
+
+// String returns the name of the status in Nagios plugin output.
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Metric is the measured value with thresholds. Zero threshold is not
+// checked. Status is warning or critical if the value reaches the threshold.
+type Metric struct {
+	Label string
+	Value float64
+	// Unit is the unit of measurement of performance data, e.g. s.
+	Unit string
+	Warn float64
+	Crit float64
+}
+
+// Status returns the status of the value.
+func (m Metric) Status() Status {
+	switch {
+	case m.Crit != 0 && m.Value >= m.Crit:
+		return Critical
+	case m.Warn != 0 && m.Value >= m.Warn:
+		return Warning
+	}
+
+	return OK
+}
+
+// Perfdata returns the metric as performance data: label=value;warn;crit;min.
+func (m Metric) Perfdata() string {
+	return fmt.Sprintf("%s=%s%s;%s;%s;0", m.Label, number(m.Value), m.Unit, threshold(m.Warn), threshold(m.Crit))
+}
+
+// Result is the outcome of the check.
+type Result struct {
+	// Service is the name of the check in the output, e.g. RCON.
+	Service string
+	// Text is the human-readable message after the status.
+	Text    string
+	Metrics []Metric
+}
+
+// Status returns the worst status of metrics.
+func (r Result) Status() Status {
+	status := OK
+
+	for _, m := range r.Metrics {
+		status = max(status, m.Status())
+	}
+
+	return status
+}
+
+// String returns the result as the line of Nagios plugin output with
+// performance data after the pipe.
+func (r Result) String() string {
+	line := fmt.Sprintf("%s %s - %s", r.Service, r.Status(), r.Text)
+	if len(r.Metrics) == 0 {
+		return line
+	}
+
+	perfdata := make([]string, 0, len(r.Metrics))
+	for _, m := range r.Metrics {
+		perfdata = append(perfdata, m.Perfdata())
+	}
+
+	return line + " | " + strings.Join(perfdata, " ")
+}
+
+// Fail returns the line of Nagios plugin output with unknown status for the
+// error which prevents the check.
+func Fail(service string, err error) string {
+	return fmt.Sprintf("%s %s - %s", service, Unknown, err)
+}
+
+// number formats the value without trailing zeros.
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// threshold formats the threshold which is empty if it is not set.
+func threshold(v float64) string {
+	if v == 0 {
+		return ""
+	}
+
+	return number(v)
+}
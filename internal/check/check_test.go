@@ -0,0 +1,51 @@
+package check_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetric_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric check.Metric
+		want   check.Status
+	}{
+		{name: "no thresholds", metric: check.Metric{Value: 100}, want: check.OK},
+		{name: "below warning", metric: check.Metric{Value: 89, Warn: 90, Crit: 100}, want: check.OK},
+		{name: "warning", metric: check.Metric{Value: 90, Warn: 90, Crit: 100}, want: check.Warning},
+		{name: "critical", metric: check.Metric{Value: 100, Warn: 90, Crit: 100}, want: check.Critical},
+		{name: "critical only", metric: check.Metric{Value: 2.5, Crit: 2}, want: check.Critical},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.metric.Status())
+		})
+	}
+}
+
+func TestResult_String(t *testing.T) {
+	result := check.Result{
+		Service: "RCON",
+		Text:    "92 players, response time 0.021s",
+		Metrics: []check.Metric{
+			{Label: "players", Value: 92, Warn: 90, Crit: 100},
+			{Label: "time", Value: 0.021, Unit: "s"},
+		},
+	}
+
+	assert.Equal(t, check.Warning, result.Status())
+	assert.Equal(t, "RCON WARNING - 92 players, response time 0.021s | players=92;90;100;0 time=0.021s;;;0",
+		result.String())
+
+	assert.Equal(t, "RCON OK - up", check.Result{Service: "RCON", Text: "up"}.String())
+}
+
+func TestFail(t *testing.T) {
+	assert.Equal(t, "RCON UNKNOWN - connection refused", check.Fail("RCON", errors.New("connection refused")))
+	assert.Equal(t, 3, int(check.Unknown))
+}
@@ -1,14 +1,20 @@
 package config
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path"
-	"path/filepath"
-
-	"gopkg.in/yaml.v3"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gorcon/rcon-cli/internal/game"
+	"github.com/gorcon/rcon-cli/internal/highlight"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/gorcon/rcon-cli/internal/policy"
+	"github.com/gorcon/rcon-cli/internal/tlspin"
 )
 
 // DefaultConfigName sets the default config file name.
@@ -55,24 +61,15 @@ func NewConfig(name string) (*Config, error) {
 	return cfg, nil
 }
 
-// ParseFromFile reads a configuration file from disk and loads its contents into
-// the application's config structure. YAML and JSON files are supported.
+// ParseFromFile reads a configuration file from disk and loads its environments
+// into the application's config structure. YAML and JSON files are supported.
 func (cfg *Config) ParseFromFile(name string) error {
-	if name != "" {
-		return cfg.parse(name)
-	}
-
-	home, err := filepath.Abs(filepath.Dir(os.Args[0]))
-	if err != nil {
-		return fmt.Errorf("get abs path: %w", err)
-	}
-
-	name = home + "/" + DefaultConfigName
-	if err = cfg.parse(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+	file := new(File)
+	if err := file.ParseFromFile(name); err != nil {
 		return err
 	}
 
-	*cfg = Config{DefaultConfigEnv: {}}
+	*cfg = file.Environments
 
 	return nil
 }
@@ -85,29 +82,98 @@ func (cfg *Config) Validate() error {
 
 	for key, ses := range *cfg {
 		switch ses.Type {
-		case "", ProtocolRCON, ProtocolTELNET, ProtocolWebRCON:
+		case "", ProtocolRCON, ProtocolTELNET, ProtocolWebRCON, ProtocolAuto:
 		default:
 			return fmt.Errorf("%w: unsupported type in %s environment", ErrConfigValidation, key)
 		}
+
+		if _, ok := game.Lookup(ses.Game); ses.Game != "" && !ok {
+			return fmt.Errorf("%w: unsupported game in %s environment", ErrConfigValidation, key)
+		}
+
+		if ses.BindAddr != "" && net.ParseIP(ses.BindAddr) == nil {
+			return fmt.Errorf("%w: invalid bind address in %s environment", ErrConfigValidation, key)
+		}
+
+		switch ses.PacketID {
+		case "", PacketIDRandom, PacketIDSequential:
+		default:
+			return fmt.Errorf("%w: unsupported packet id in %s environment", ErrConfigValidation, key)
+		}
+
+		if !output.IsSupported(ses.Format) {
+			return fmt.Errorf("%w: unsupported format in %s environment", ErrConfigValidation, key)
+		}
+
+		if _, err := policy.New(ses.AllowedCommands, ses.DeniedCommands); err != nil {
+			return fmt.Errorf("%w: invalid commands policy in %s environment: %s", ErrConfigValidation, key, err)
+		}
+
+		if _, err := policy.Match(ses.ConfirmCommands, ""); err != nil {
+			return fmt.Errorf("%w: invalid confirm commands in %s environment: %s", ErrConfigValidation, key, err)
+		}
+
+		if _, err := highlight.New(ses.Highlight); err != nil {
+			return fmt.Errorf("%w: invalid highlight in %s environment: %s", ErrConfigValidation, key, err)
+		}
+
+		if _, err := tlspin.Parse(ses.TLSFingerprint); ses.TLSFingerprint != "" && err != nil {
+			return fmt.Errorf("%w: invalid tls fingerprint in %s environment: %s", ErrConfigValidation, key, err)
+		}
+
+		if (ses.TLSCert == "") != (ses.TLSKey == "") {
+			return fmt.Errorf("%w: tls_cert and tls_key must be set together in %s environment", ErrConfigValidation, key)
+		}
+
+		if ses.HTTPProxy != "" && !validProxy(ses.HTTPProxy) {
+			return fmt.Errorf("%w: invalid http proxy in %s environment: must be like http://host:port", ErrConfigValidation, key)
+		}
+
+		if _, err := regexp.Compile(ses.Prompt); err != nil {
+			return fmt.Errorf("%w: invalid prompt in %s environment: %s", ErrConfigValidation, key, err)
+		}
+
+		if ses.PlayersPattern != "" {
+			if _, err := player.Compile(ses.PlayersPattern); err != nil {
+				return fmt.Errorf("%w: invalid players pattern in %s environment: %s", ErrConfigValidation, key, err)
+			}
+		}
+
+		for i, step := range ses.Login {
+			if _, err := regexp.Compile(step.Expect); step.Expect == "" || err != nil {
+				return fmt.Errorf("%w: invalid expect of login step %d in %s environment", ErrConfigValidation, i+1, key)
+			}
+		}
+
+		if ses.PingInterval < 0 || ses.PongTimeout < 0 {
+			return fmt.Errorf("%w: negative ping interval or pong timeout in %s environment", ErrConfigValidation, key)
+		}
+
+		for name := range ses.Headers {
+			if !validHeader(name) {
+				return fmt.Errorf("%w: invalid header %q in %s environment", ErrConfigValidation, name, key)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (cfg *Config) parse(name string) error {
-	file, err := os.ReadFile(name)
-	if err != nil {
-		return fmt.Errorf("read file: %w", err)
+// validProxy returns true if proxy is URL of HTTP or SOCKS5 proxy with host.
+func validProxy(proxy string) bool {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return false
 	}
 
-	switch ext := path.Ext(name); ext {
-	case ".yml", ".yaml":
-		err = yaml.Unmarshal(file, cfg)
-	case ".json":
-		err = json.Unmarshal(file, cfg)
-	default:
-		err = fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
+	return u.Scheme == "http" || u.Scheme == "socks5"
+}
+
+// validHeader returns true if name is valid HTTP header name.
+func validHeader(name string) bool {
+	invalid := func(r rune) bool {
+		return r <= ' ' || r >= unicode.MaxASCII || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r)
 	}
 
-	return err
+	return name != "" && strings.IndexFunc(name, invalid) == -1
 }
@@ -4,9 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorcon/rcon-cli/internal/alert"
+	"github.com/gorcon/rcon-cli/internal/announce"
 	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/highlight"
+	"github.com/gorcon/rcon-cli/internal/maintenance"
+	"github.com/gorcon/rcon-cli/internal/parser"
+	"github.com/gorcon/rcon-cli/internal/webhook"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -91,6 +100,309 @@ func TestNewConfig(t *testing.T) {
 		assert.Nil(t, cfg)
 	})
 
+	t.Run("extends", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := "prod:\n  address: 10.0.0.1:16260\n  password: secret\n  log: prod.log\n" +
+			"staging:\n  extends: prod\n  address: 10.0.0.2:16260\n" +
+			"dev:\n  extends: staging\n  log: dev.log\n"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		expected := config.Config{
+			"prod":    {Address: "10.0.0.1:16260", Password: "secret", Log: "prod.log"},
+			"staging": {Extends: "prod", Address: "10.0.0.2:16260", Password: "secret", Log: "prod.log"},
+			"dev":     {Extends: "staging", Address: "10.0.0.2:16260", Password: "secret", Log: "dev.log"},
+		}
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, cfg)
+	})
+
+	t.Run("extends overrides", func(t *testing.T) {
+		expected := config.Session{
+			Extends:    "prod",
+			Address:    "10.0.0.2:16260",
+			Password:   "secret",
+			SkipErrors: false,
+			Timeout:    0,
+			Restart:    config.Restart{Template: "restart", SaveCommand: "save"},
+		}
+
+		configFileName := "rcon-test-local.yaml"
+		stringBody := "prod:\n  address: 10.0.0.1:16260\n  password: secret\n  skip_errors: true\n  timeout: 5s\n" +
+			"  restart:\n    template: restart\n    save_command: saveworld\n" +
+			"staging:\n  extends: prod\n  address: 10.0.0.2:16260\n  skip_errors: false\n  timeout: 0s\n" +
+			"  restart:\n    save_command: save\n"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, (*cfg)["staging"])
+		assert.True(t, (*cfg)["prod"].SkipErrors)
+		assert.Equal(t, "saveworld", (*cfg)["prod"].Restart.SaveCommand)
+
+		configFileName = "rcon-test-local.json"
+		stringBody = `{"prod": {"address": "10.0.0.1:16260", "password": "secret", "skip_errors": true, "timeout": 5000000000,` +
+			` "restart": {"template": "restart", "save_command": "saveworld"}},` +
+			` "staging": {"extends": "prod", "address": "10.0.0.2:16260", "skip_errors": false, "timeout": 0,` +
+			` "restart": {"save_command": "save"}}}`
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		cfg, err = config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, (*cfg)["staging"])
+	})
+
+	t.Run("extends errors", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "staging:\n  extends: prod\n")
+
+		_, err := config.NewConfig(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+
+		createFile(configFileName, "a:\n  extends: b\nb:\n  extends: c\nc:\n  extends: a\n")
+
+		_, err = config.NewConfig(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "cyclic extends")
+	})
+
+	t.Run("webhooks", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"webhooks:\n  deploy:\n    env: prod\n    secret: s3cr3t\n    commands: [reload]\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, webhook.Action{Env: "prod", Secret: "s3cr3t", Commands: []string{"reload"}},
+			file.Webhooks["deploy"])
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"webhooks:\n  deploy:\n    env: staging\n    secret: s3cr3t\n    commands: [reload]\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "deploy webhook uses unknown staging environment")
+	})
+
+	t.Run("alerts", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"alerts:\n  crash:\n    pattern: crashed\n    notify: https://hooks.slack.com/services/T/B/X\n    cooldown: 5m\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, alert.Rule{
+			Pattern: "crashed", Notify: "https://hooks.slack.com/services/T/B/X", Cooldown: 5 * time.Minute,
+		}, file.Alerts["crash"])
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"alerts:\n  crash:\n    pattern: crashed\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "crash has no http or https notify URL")
+	})
+
+	t.Run("maintenance", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"maintenance:\n  prod:\n    - cron: 0 4 * * 1\n      duration: 1h\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, []maintenance.Window{{Cron: "0 4 * * 1", Duration: time.Hour}}, file.Maintenance["prod"])
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"maintenance:\n  staging:\n    - cron: 0 4 * * 1\n      duration: 1h\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "maintenance uses unknown staging environment")
+	})
+
+	t.Run("tls fingerprint", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  type: web\n"+
+			"  tls_fingerprint: sha256:"+strings.Repeat("ab", 32)+"\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "sha256:"+strings.Repeat("ab", 32), file.Environments["prod"].TLSFingerprint)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  tls_fingerprint: ab12\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid tls fingerprint in prod environment")
+	})
+
+	t.Run("http proxy", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  type: web\n"+
+			"  http_proxy: http://proxy.corp:3128\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://proxy.corp:3128", file.Environments["prod"].HTTPProxy)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  http_proxy: proxy.corp:3128\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid http proxy in prod environment")
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  type: web\n"+
+			"  headers:\n    Origin: https://example.com\n    CF-Access-Client-Id: id\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"Origin": "https://example.com", "CF-Access-Client-Id": "id"},
+			file.Environments["prod"].Headers)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  headers:\n    \"X Token\": id\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, `invalid header "X Token" in prod environment`)
+	})
+
+	t.Run("heartbeat", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  type: web\n"+
+			"  ping_interval: 30s\n  pong_timeout: 10s\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, file.Environments["prod"].PingInterval)
+		assert.Equal(t, 10*time.Second, file.Environments["prod"].PongTimeout)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:28016\n  password: secret\n  ping_interval: -1s\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "negative ping interval or pong timeout in prod environment")
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:8081\n  password: secret\n  type: telnet\n  prompt: '^> $'\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "^> $", file.Environments["prod"].Prompt)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:8081\n  password: secret\n  prompt: '[>'\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid prompt in prod environment")
+	})
+
+	t.Run("telnet login", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:8081\n  password: secret\n  type: telnet\n"+
+			"  login:\n    - expect: 'login:'\n      send: admin\n    - expect: 'Password:'\n      password: true\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, []config.LoginStep{{Expect: "login:", Send: "admin"}, {Expect: "Password:", Password: true}},
+			file.Environments["prod"].Login)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:8081\n  password: secret\n  login:\n    - send: admin\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid expect of login step 1 in prod environment")
+	})
+
+	t.Run("table parser", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "default:\n  address: 127.0.0.1:16260\n  password: password\nparsers:\n"+
+			"  players: '^-(?P<name>.+)$'\n  status:\n    header: '^# userid'\n    columns: ['-', userid, name]\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "^-(?P<name>.+)$", file.Parsers["players"].Pattern)
+		assert.Equal(t, &parser.Table{Header: "^# userid", Columns: []parser.Column{{Name: "-"}, {Name: "userid"}, {Name: "name"}}},
+			file.Parsers["status"].Table)
+
+		createFile(configFileName, "default:\n  address: 127.0.0.1:16260\n  password: password\nparsers:\n"+
+			"  status:\n    header: '^# userid'\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid status parser: table has no columns")
+	})
+
+	t.Run("players pattern", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "default:\n  address: 127.0.0.1:16260\n  password: password\n"+
+			"  players_command: who\n  players_pattern: '(?P<id>\\d+) (?P<name>\\w+)'\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "who", file.Environments["default"].PlayersCommand)
+
+		createFile(configFileName, "default:\n  address: 127.0.0.1:16260\n  password: password\n"+
+			"  players_pattern: '(?P<id>\\d+)'\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid players pattern in default environment")
+	})
+
+	t.Run("announcements", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"groups:\n  eu: [prod]\n"+
+			"announcements:\n  rules:\n    cron: 0 */2 * * *\n    envs: [\"@eu\"]\n    message: Be nice\n    jitter: 30s\n")
+
+		file, err := config.NewFile(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, announce.Schedule{Cron: "0 */2 * * *", Envs: []string{"@eu"}, Message: "Be nice", Jitter: 30 * time.Second},
+			file.Announcements["rules"])
+
+		createFile(configFileName, "prod:\n  address: 10.0.0.1:16260\n  password: secret\n"+
+			"announcements:\n  rules:\n    cron: 0 */2 * * *\n    envs: [\"@us\"]\n    message: Be nice\n")
+
+		_, err = config.NewFile(configFileName)
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "rules announcement uses unknown @us environment")
+	})
+
 	t.Run("validation failed", func(t *testing.T) {
 		configFileName := "rcon-test-local.json"
 		stringBody := fmt.Sprintf(ConfigLayoutJSON, config.DefaultConfigEnv, "", "", DefaultTestLogName, "pigeon post")
@@ -109,6 +421,39 @@ func TestNewConfig(t *testing.T) {
 }
 
 func TestConfig_Validate(t *testing.T) {
+	t.Run("unsupported format", func(t *testing.T) {
+		cfg := config.Config{config.DefaultConfigEnv: config.Session{Format: "xml"}}
+		err := cfg.Validate()
+		assert.EqualError(t, err, "config validation error: unsupported format in default environment")
+	})
+
+	t.Run("unsupported game", func(t *testing.T) {
+		cfg := config.Config{config.DefaultConfigEnv: config.Session{Game: "tetris"}}
+		err := cfg.Validate()
+		assert.EqualError(t, err, "config validation error: unsupported game in default environment")
+	})
+
+	t.Run("unsupported packet id", func(t *testing.T) {
+		cfg := config.Config{config.DefaultConfigEnv: config.Session{PacketID: "even"}}
+		err := cfg.Validate()
+		assert.EqualError(t, err, "config validation error: unsupported packet id in default environment")
+	})
+
+	t.Run("invalid commands policy", func(t *testing.T) {
+		cfg := config.Config{config.DefaultConfigEnv: config.Session{DeniedCommands: []string{"/(/"}}}
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+	})
+
+	t.Run("invalid highlight", func(t *testing.T) {
+		cfg := config.Config{config.DefaultConfigEnv: config.Session{
+			Highlight: []highlight.Rule{{Pattern: "error", Color: "purple"}},
+		}}
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, config.ErrConfigValidation)
+		assert.ErrorContains(t, err, "invalid highlight in default environment: unknown color: purple")
+	})
+
 	t.Run("initialized empty config", func(t *testing.T) {
 		cfg := new(config.Config)
 		err := cfg.Validate()
@@ -122,6 +467,39 @@ func TestConfig_Validate(t *testing.T) {
 	})
 }
 
+func TestFind(t *testing.T) {
+	xdg := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("HOME", home)
+
+	t.Run("not found", func(t *testing.T) {
+		assert.Equal(t, config.DefaultConfigName, config.Find(config.DefaultConfigName))
+	})
+
+	t.Run("custom name", func(t *testing.T) {
+		assert.Equal(t, "custom.yaml", config.Find("custom.yaml"))
+	})
+
+	t.Run("home", func(t *testing.T) {
+		name := filepath.Join(home, "."+config.DefaultConfigName)
+		assert.NoError(t, createFile(name, ""))
+		assert.Equal(t, name, config.Find(config.DefaultConfigName))
+	})
+
+	t.Run("xdg before home", func(t *testing.T) {
+		assert.NoError(t, os.Mkdir(filepath.Join(xdg, config.ConfigDir), 0o755))
+
+		name := filepath.Join(xdg, config.ConfigDir, config.DefaultConfigName)
+		assert.NoError(t, createFile(name, "default:\n  address: 127.0.0.1:16260\n"))
+		assert.Equal(t, name, config.Find(config.DefaultConfigName))
+
+		file, err := config.NewFile(config.DefaultConfigName)
+		assert.NoError(t, err)
+		assert.Equal(t, "127.0.0.1:16260", file.Environments[config.DefaultConfigEnv].Address)
+	})
+}
+
 func createFile(name, stringBody string) error {
 	file, err := os.Create(name)
 	if err != nil {
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resolveExtends decodes environments which extend another environment
+// over their parents, so the keys set in the child override the parent
+// values, including false and zero values and single keys of nested
+// sections. Parent can extend another environment too.
+func (cfg Config) resolveExtends(sections map[string]section) error {
+	for name := range cfg {
+		chain, err := cfg.chain(name)
+		if err != nil {
+			return err
+		}
+
+		if len(chain) == 1 {
+			continue
+		}
+
+		var ses Session
+
+		for i := len(chain) - 1; i >= 0; i-- {
+			if err = sections[chain[i]](&ses); err != nil {
+				return err
+			}
+		}
+
+		cfg[name] = ses
+	}
+
+	return nil
+}
+
+// chain returns the environment followed by the environments it extends.
+func (cfg Config) chain(name string) ([]string, error) {
+	chain := []string{name}
+
+	for ses := cfg[name]; ses.Extends != ""; ses = cfg[ses.Extends] {
+		for _, visited := range chain {
+			if visited == ses.Extends {
+				return nil, fmt.Errorf("%w: cyclic extends %s -> %s",
+					ErrConfigValidation, strings.Join(chain, " -> "), ses.Extends)
+			}
+		}
+
+		if _, ok := cfg[ses.Extends]; !ok {
+			return nil, fmt.Errorf("%w: %s environment extends unknown %s environment",
+				ErrConfigValidation, chain[len(chain)-1], ses.Extends)
+		}
+
+		chain = append(chain, ses.Extends)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,342 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/alert"
+	"github.com/gorcon/rcon-cli/internal/announce"
+	"github.com/gorcon/rcon-cli/internal/maintenance"
+	"github.com/gorcon/rcon-cli/internal/parser"
+	"github.com/gorcon/rcon-cli/internal/webhook"
+	"gopkg.in/yaml.v3"
+)
+
+// Reserved top-level keys of the config file which are not environments.
+const (
+	// SectionParsers contains response parsers.
+	SectionParsers = "parsers"
+
+	// SectionAliases contains command aliases.
+	SectionAliases = "aliases"
+
+	// SectionHooks contains hooks invoked on command events.
+	SectionHooks = "hooks"
+
+	// SectionTemplates contains announcement templates.
+	SectionTemplates = "templates"
+
+	// SectionGroups contains groups of environments.
+	SectionGroups = "groups"
+
+	// SectionWebhooks contains webhook actions.
+	SectionWebhooks = "webhooks"
+
+	// SectionAlerts contains alerts on patterns of responses.
+	SectionAlerts = "alerts"
+
+	// SectionMaintenance contains maintenance windows of environments.
+	SectionMaintenance = "maintenance"
+
+	// SectionAnnouncements contains recurring announcements.
+	SectionAnnouncements = "announcements"
+)
+
+// GroupPrefix marks the environment name as a group of environments from
+// the groups section.
+const GroupPrefix = "@"
+
+// File contains all sections of the configuration file. Top-level keys
+// which are not reserved section names are environments.
+//
+// Example:
+// ```yaml
+// default:
+//
+//	address: "127.0.0.1:16260"
+//	password: "password"
+//
+// parsers:
+//
+//	players: "(?m)^-(?P<name>.+)$"
+//	status: {header: "^# userid", columns: ["-", "userid", "name", "uniqueid"]}
+//
+// aliases:
+//
+//	warn: "say Restart in $1 minutes"
+//
+// hooks:
+//
+//	js: ["hooks/discord.js"]
+//	post: ["./notify.sh"]
+//
+// templates:
+//
+//	restart: "Server restarts at {{.Time}}"
+//
+// groups:
+//
+//	eu: ["eu1", "eu2"]
+//
+// webhooks:
+//
+//	deploy: {env: "default", secret: "s3cr3t", commands: ["reload"]}
+//
+// alerts:
+//
+//	crash: {pattern: "(?i)server crashed", notify: "https://discord.com/api/webhooks/..."}
+//
+// maintenance:
+//
+//	default: [{cron: "0 4 * * 1", duration: "1h"}]
+//
+// announcements:
+//
+//	rules: {cron: "0 */2 * * *", envs: ["@eu"], template: "rules", jitter: "30s"}
+//
+// ```.
+type File struct {
+	Environments Config
+	// Parsers maps parser names to regular expressions or tables of
+	// column-aligned responses. See parser package.
+	Parsers map[string]parser.Definition
+	// Aliases maps alias names to command templates. See alias package.
+	Aliases map[string]string
+	// Hooks contains programs and scripts invoked on command events.
+	Hooks Hooks
+	// Templates contains announcement templates. See announce package.
+	Templates announce.Templates
+	// Groups maps group names to lists of environments.
+	Groups map[string][]string
+	// Webhooks maps webhook action names to commands. See webhook package.
+	Webhooks webhook.Actions
+	// Alerts maps alert names to patterns of responses and notified
+	// webhooks. See alert package.
+	Alerts alert.Rules
+	// Maintenance maps environment names to maintenance windows. See
+	// maintenance package.
+	Maintenance maintenance.Windows
+	// Announcements maps names of recurring announcements to schedules.
+	// See announce package.
+	Announcements announce.Schedules
+}
+
+// Hooks contains programs and scripts invoked on command events.
+type Hooks struct {
+	// JS contains paths to JavaScript files which define beforeSend,
+	// afterResponse and onError functions. See hook package.
+	JS []string `json:"js" yaml:"js"`
+	// Pre and Post contain local programs which are run before the command
+	// is sent and after the response is received. See hook package.
+	Pre  []string `json:"pre" yaml:"pre"`
+	Post []string `json:"post" yaml:"post"`
+}
+
+// section decodes a raw top-level entry of the config file to v.
+type section func(v interface{}) error
+
+// NewFile finds and parses config file with all its sections. The default
+// config name is searched in SearchPaths.
+func NewFile(name string) (*File, error) {
+	file := new(File)
+	if err := file.ParseFromFile(Find(name)); err != nil {
+		return nil, fmt.Errorf("parse file: %w", err)
+	}
+
+	if err := file.Validate(); err != nil {
+		return file, err
+	}
+
+	return file, nil
+}
+
+// ParseFromFile reads a configuration file from disk and loads its contents.
+// If name is empty the file is searched next to the executable.
+func (file *File) ParseFromFile(name string) error {
+	if name != "" {
+		return file.parse(name)
+	}
+
+	home, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	if err != nil {
+		return fmt.Errorf("get abs path: %w", err)
+	}
+
+	name = home + "/" + DefaultConfigName
+	if err = file.parse(name); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		*file = File{Environments: Config{DefaultConfigEnv: {}}}
+	}
+
+	return nil
+}
+
+// Validate validates environments and sections.
+func (file *File) Validate() error {
+	if file == nil {
+		return fmt.Errorf("%w: config is not set", ErrConfigValidation)
+	}
+
+	if err := file.Environments.Validate(); err != nil {
+		return err
+	}
+
+	for name, definition := range file.Parsers {
+		if _, err := definition.Compile(); err != nil {
+			return fmt.Errorf("%w: invalid %s parser: %s", ErrConfigValidation, name, err)
+		}
+	}
+
+	if err := file.Templates.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigValidation, err)
+	}
+
+	for name, envs := range file.Groups {
+		for _, env := range envs {
+			if _, ok := file.Environments[env]; !ok {
+				return fmt.Errorf("%w: %s group contains unknown %s environment", ErrConfigValidation, name, env)
+			}
+		}
+	}
+
+	if err := file.Webhooks.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigValidation, err)
+	}
+
+	for name, action := range file.Webhooks {
+		if _, ok := file.Environments[action.Env]; !ok {
+			return fmt.Errorf("%w: %s webhook uses unknown %s environment", ErrConfigValidation, name, action.Env)
+		}
+	}
+
+	if err := file.Alerts.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigValidation, err)
+	}
+
+	if err := file.Maintenance.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigValidation, err)
+	}
+
+	for env := range file.Maintenance {
+		if _, ok := file.Environments[env]; !ok {
+			return fmt.Errorf("%w: maintenance uses unknown %s environment", ErrConfigValidation, env)
+		}
+	}
+
+	if err := file.Announcements.Validate(file.Templates); err != nil {
+		return fmt.Errorf("%w: %s", ErrConfigValidation, err)
+	}
+
+	for name, schedule := range file.Announcements {
+		for _, env := range schedule.Envs {
+			if !file.known(env) {
+				return fmt.Errorf("%w: %s announcement uses unknown %s environment", ErrConfigValidation, name, env)
+			}
+		}
+	}
+
+	return nil
+}
+
+// known reports whether env is the environment or the group with
+// GroupPrefix.
+func (file *File) known(env string) bool {
+	if group, ok := strings.CutPrefix(env, GroupPrefix); ok {
+		_, ok = file.Groups[group]
+
+		return ok
+	}
+
+	_, ok := file.Environments[env]
+
+	return ok
+}
+
+func (file *File) parse(name string) error {
+	sections, err := readSections(name)
+	if err != nil {
+		return err
+	}
+
+	file.Environments = make(Config, len(sections))
+
+	for key, decode := range sections {
+		switch key {
+		case SectionParsers:
+			err = decode(&file.Parsers)
+		case SectionAliases:
+			err = decode(&file.Aliases)
+		case SectionHooks:
+			err = decode(&file.Hooks)
+		case SectionTemplates:
+			err = decode(&file.Templates)
+		case SectionGroups:
+			err = decode(&file.Groups)
+		case SectionWebhooks:
+			err = decode(&file.Webhooks)
+		case SectionAlerts:
+			err = decode(&file.Alerts)
+		case SectionMaintenance:
+			err = decode(&file.Maintenance)
+		case SectionAnnouncements:
+			err = decode(&file.Announcements)
+		default:
+			var ses Session
+			err = decode(&ses)
+			file.Environments[key] = ses
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Environments.resolveExtends(sections)
+}
+
+// readSections reads config file and splits it to top-level entries.
+func readSections(name string) (map[string]section, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	sections := make(map[string]section)
+
+	switch ext := path.Ext(name); ext {
+	case ".yml", ".yaml":
+		nodes := make(map[string]yaml.Node)
+		if err = yaml.Unmarshal(data, &nodes); err != nil {
+			return nil, err
+		}
+
+		for key := range nodes {
+			node := nodes[key]
+			sections[key] = node.Decode
+		}
+	case ".json":
+		raws := make(map[string]json.RawMessage)
+		if err = json.Unmarshal(data, &raws); err != nil {
+			return nil, err
+		}
+
+		for key := range raws {
+			raw := raws[key]
+			sections[key] = func(v interface{}) error {
+				return json.Unmarshal(raw, v)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// DefaultShutdownCommand is the server command which stops the server if
+// shutdown command is not set for environment.
+const DefaultShutdownCommand = "quit"
+
+// Restart contains settings of server restart sequence.
+type Restart struct {
+	// Warnings contains times before shutdown when warnings are sent.
+	Warnings []time.Duration `json:"warnings" yaml:"warnings"`
+	// Template is the name of announcement template for warnings. Variables
+	// Left and Time are set to time left and time of the shutdown.
+	Template string `json:"template" yaml:"template"`
+	// SaveCommand saves the world before shutdown. Save is skipped if it is
+	// not set.
+	SaveCommand     string `json:"save_command" yaml:"save_command"`
+	ShutdownCommand string `json:"shutdown_command" yaml:"shutdown_command"`
+}
+
+// WarningTimes returns times before shutdown when warnings are sent. Default
+// times are 15m, 10m, 5m, 1m and 30s.
+func (r *Restart) WarningTimes() []time.Duration {
+	if len(r.Warnings) != 0 {
+		return r.Warnings
+	}
+
+	return []time.Duration{15 * time.Minute, 10 * time.Minute, 5 * time.Minute, time.Minute, 30 * time.Second}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ConfigDir is the name of the directory with config file in user and system
+// config directories.
+const ConfigDir = "rcon"
+
+// SearchPaths returns locations of the config file in the order they are
+// searched: the working directory, $XDG_CONFIG_HOME/rcon (~/.config/rcon if
+// not set), %APPDATA%\rcon on Windows, ~/.rcon.yaml and /etc/rcon.
+func SearchPaths() []string {
+	paths := []string{DefaultConfigName}
+	home, _ := os.UserHomeDir()
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		paths = append(paths, filepath.Join(dir, ConfigDir, DefaultConfigName))
+	} else if home != "" {
+		paths = append(paths, filepath.Join(home, ".config", ConfigDir, DefaultConfigName))
+	}
+
+	if dir := os.Getenv("APPDATA"); dir != "" && runtime.GOOS == "windows" {
+		paths = append(paths, filepath.Join(dir, ConfigDir, DefaultConfigName))
+	}
+
+	if home != "" {
+		paths = append(paths, filepath.Join(home, "."+DefaultConfigName))
+	}
+
+	if runtime.GOOS != "windows" {
+		paths = append(paths, filepath.Join("/etc", ConfigDir, DefaultConfigName))
+	}
+
+	return paths
+}
+
+// Find returns the first existing config file of SearchPaths if name is
+// DefaultConfigName. Other names are returned as is, as well as the default
+// name if none of the files exists.
+func Find(name string) string {
+	if name != DefaultConfigName {
+		return name
+	}
+
+	for _, path := range SearchPaths() {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+
+	return name
+}
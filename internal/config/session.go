@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	"github.com/gorcon/rcon-cli/internal/highlight"
 )
 
 // Allowed protocols.
@@ -12,6 +14,13 @@ const (
 	ProtocolRCON    = "rcon"
 	ProtocolTELNET  = "telnet"
 	ProtocolWebRCON = "web"
+	ProtocolAuto    = "auto"
+)
+
+// Allowed packet ID strategies.
+const (
+	PacketIDRandom     = "random"
+	PacketIDSequential = "sequential"
 )
 
 // DefaultProtocol contains the default protocol for connecting to a
@@ -21,17 +30,141 @@ const DefaultProtocol = ProtocolRCON
 // DefaultTimeout contains the default dial and execute timeout.
 const DefaultTimeout = 10 * time.Second
 
+// LoginStep is the step of telnet login. Output of the server is read until
+// it matches Expect regular expression, then Send or the password is sent.
+type LoginStep struct {
+	Expect   string `json:"expect" yaml:"expect"`
+	Send     string `json:"send" yaml:"send"`
+	Password bool   `json:"password" yaml:"password"`
+}
+
 // Session contains details for making a request on a remote server.
 type Session struct {
+	// Extends is the name of environment which fields are used if they are
+	// not set in this environment.
+	Extends  string `json:"extends" yaml:"extends"`
 	Address  string `json:"address" yaml:"address"`
 	Password string `json:"password" yaml:"password"`
 	// Log is the name of the file to which requests will be logged.
 	// If not specified, no logging will be performed.
-	Log        string        `json:"log" yaml:"log"`
-	Type       string        `json:"type" yaml:"type"`
+	Log  string `json:"log" yaml:"log"`
+	Type string `json:"type" yaml:"type"`
+	// Game sets protocol, default port and quirks of the game. See game
+	// package for supported games.
+	Game       string        `json:"game" yaml:"game"`
 	SkipErrors bool          `json:"skip_errors" yaml:"skip_errors"`
 	Timeout    time.Duration `json:"timeout" yaml:"timeout"`
+	Pager      bool          `json:"pager" yaml:"pager"`
+	Format     string        `json:"format" yaml:"format"`
+	Parse      string        `json:"parse" yaml:"parse"`
+	Extract    string        `json:"-" yaml:"-"`
 	Variables  bool          `json:"-" yaml:"-"`
+	// Expect and ExpectNot are regular expressions which responses must
+	// match and must not match, e.g. in health checks of deployments.
+	Expect    string `json:"-" yaml:"-"`
+	ExpectNot string `json:"-" yaml:"-"`
+	// AllowedCommands and DeniedCommands restrict commands which can be
+	// sent to the remote server. See policy package for pattern syntax.
+	AllowedCommands []string `json:"allowed_commands" yaml:"allowed_commands"`
+	DeniedCommands  []string `json:"denied_commands" yaml:"denied_commands"`
+	// ConfirmCommands contains patterns of destructive commands which must
+	// be confirmed before sending unless Yes is set.
+	ConfirmCommands []string `json:"confirm_commands" yaml:"confirm_commands"`
+	Yes             bool     `json:"-" yaml:"-"`
+	// Locale chooses text of announcement templates. SayCommand is the
+	// server command which broadcasts messages, "say" if not set.
+	Locale     string `json:"locale" yaml:"locale"`
+	SayCommand string `json:"say_command" yaml:"say_command"`
+	// Restart contains settings of restart command.
+	Restart Restart `json:"restart" yaml:"restart"`
+	// Tags are used to select environments with --tag flag.
+	Tags []string `json:"tags" yaml:"tags"`
+	// LogStripColors removes ANSI escape sequences and game color codes from
+	// logged responses. Responses are printed unchanged.
+	LogStripColors bool `json:"log_strip_colors" yaml:"log_strip_colors"`
+	// LogRetention is the age after which records of SQLite log are
+	// deleted. Records are kept forever if it is not set.
+	LogRetention time.Duration `json:"log_retention" yaml:"log_retention"`
+	// Timestamp is the time layout in Go format, e.g. "15:04:05". If set
+	// each response line is prefixed with the time it was received.
+	Timestamp string `json:"timestamp" yaml:"timestamp"`
+	// OnConnect contains commands which are executed when interactive
+	// session to the environment opens.
+	OnConnect []string `json:"on_connect" yaml:"on_connect"`
+	// BindAddr is the local IP address which connections to the server
+	// originate from, e.g. on multi-homed hosts.
+	BindAddr string `json:"bind_addr" yaml:"bind_addr"`
+	// PacketID is the ID strategy of Source RCON requests, "random" or
+	// "sequential". Responses must have the ID of the request. AcceptZeroID
+	// tolerates servers which respond with ID 0.
+	PacketID     string `json:"packet_id" yaml:"packet_id"`
+	AcceptZeroID bool   `json:"accept_zero_id" yaml:"accept_zero_id"`
+	// CommandsFromHelp runs HelpCommand when interactive session opens and
+	// adds commands of its response to the dictionary of :commands. Help
+	// command of the game is used if HelpCommand is not set.
+	CommandsFromHelp bool   `json:"commands_from_help" yaml:"commands_from_help"`
+	HelpCommand      string `json:"help_command" yaml:"help_command"`
+	// ValidateCommands rejects commands which are not found in the command
+	// dictionary of the game before they are sent to the server.
+	ValidateCommands bool `json:"validate_commands" yaml:"validate_commands"`
+	// PlayersCommand lists online players for find-player command.
+	// PlayersPattern is the regular expression of the player in its
+	// response with named groups id and name. They are taken from the game
+	// if they are not set.
+	PlayersCommand string `json:"players_command" yaml:"players_command"`
+	PlayersPattern string `json:"players_pattern" yaml:"players_pattern"`
+	// Highlight contains rules which color matches of regular expressions
+	// in responses printed to the terminal.
+	Highlight []highlight.Rule `json:"highlight" yaml:"highlight"`
+	// ReauthEveryCommand sends Source RCON auth request before each command
+	// for servers which invalidate auth after each command.
+	ReauthEveryCommand bool `json:"reauth_every_command" yaml:"reauth_every_command"`
+	// Prompt is the regular expression of the telnet server prompt. If it is
+	// set, the response ends before the prompt instead of waiting for the
+	// fixed time, echo of servers in character mode is removed.
+	Prompt string `json:"prompt" yaml:"prompt"`
+	// Login contains steps of telnet login of servers which differ from 7
+	// Days to Die, e.g. with username and password prompts.
+	Login []LoginStep `json:"login" yaml:"login"`
+	// AllowPlaintext allows to send the password of RCON and telnet
+	// protocols without encryption to the public address.
+	AllowPlaintext bool `json:"allow_plaintext" yaml:"allow_plaintext"`
+	// TLS connects to Web RCON server over TLS (wss). TLSFingerprint pins
+	// SHA-256 fingerprint of the server certificate instead of verification
+	// by certificate authorities, e.g. of self-signed certificate, and
+	// enables TLS. See tlspin package.
+	TLS            bool   `json:"tls" yaml:"tls"`
+	TLSFingerprint string `json:"tls_fingerprint" yaml:"tls_fingerprint"`
+	// TLSCert and TLSKey are paths to PEM files of the client certificate
+	// and its key for servers behind reverse proxies which require client
+	// certificates. They enable TLS.
+	TLSCert string `json:"tls_cert" yaml:"tls_cert"`
+	TLSKey  string `json:"tls_key" yaml:"tls_key"`
+	// HTTPProxy is URL of HTTP or SOCKS5 proxy of Web RCON connections, e.g.
+	// http://proxy.corp:3128. HTTP_PROXY and HTTPS_PROXY environment
+	// variables are used if it is not set.
+	HTTPProxy string `json:"http_proxy" yaml:"http_proxy"`
+	// Headers are extra HTTP headers of Web RCON handshake, e.g. Origin or
+	// Authorization of authenticating reverse proxies. Values can be
+	// references to password manager secrets like password.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// Compression negotiates permessage-deflate compression of Web RCON
+	// messages, e.g. for verbose console streaming over metered links.
+	Compression bool `json:"compression" yaml:"compression"`
+	// PingInterval enables ping of Web RCON connections every interval, so
+	// long-lived console streams survive idle timeouts of NAT and proxies.
+	// Streams are closed if pong is not received within PongTimeout, the
+	// timeout is used if it is not set.
+	PingInterval time.Duration `json:"ping_interval" yaml:"ping_interval"`
+	PongTimeout  time.Duration `json:"pong_timeout" yaml:"pong_timeout"`
+	// ZabbixHost is the host name of trapper items in Zabbix. The name of
+	// the environment or the host of the address is used if it is not set.
+	ZabbixHost string `json:"zabbix_host" yaml:"zabbix_host"`
+	// Time enables report of dial, auth and round-trip durations.
+	Time bool `json:"-" yaml:"-"`
+	// Env is the name of the config environment of the session. It is empty
+	// if credentials are received from flags.
+	Env string `json:"-" yaml:"-"`
 }
 
 func (s *Session) Print(w io.Writer) error {
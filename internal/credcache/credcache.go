@@ -0,0 +1,91 @@
+// Package credcache keeps passwords entered interactively in memory for the
+// process lifetime, so they are not asked again.
+//
+// Passwords are encrypted with AES-GCM by the random key which is generated
+// on start and is never written anywhere, so they are not kept as plain text
+// in memory dumps and swap.
+package credcache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"sync"
+)
+
+// keySize is the size of AES-256 key.
+const keySize = 32
+
+// Cache maps server addresses to encrypted passwords. Methods of nil Cache
+// do nothing, so callers do not check if caching is enabled. It is safe for
+// concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	aead    cipher.AEAD
+	entries map[string][]byte
+}
+
+// New creates a new Cache with the random key.
+func New() (*Cache, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("credcache: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("credcache: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("credcache: %w", err)
+	}
+
+	return &Cache{aead: aead, entries: make(map[string][]byte)}, nil
+}
+
+// Get returns the password of the address. The second returned value is
+// false if the password is not cached.
+func (c *Cache) Get(address string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[address]
+	if !ok {
+		return "", false
+	}
+
+	size := c.aead.NonceSize()
+
+	password, err := c.aead.Open(nil, entry[:size], entry[size:], []byte(address))
+	if err != nil {
+		return "", false
+	}
+
+	return string(password), true
+}
+
+// Set caches the password of the address.
+func (c *Cache) Set(address, password string) {
+	if c == nil {
+		return
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Address is authenticated data, so the entry can not be moved to
+	// another address.
+	c.entries[address] = c.aead.Seal(nonce, nonce, []byte(password), []byte(address))
+}
@@ -0,0 +1,40 @@
+package credcache_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/credcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("get and set", func(t *testing.T) {
+		cache, err := credcache.New()
+		assert.NoError(t, err)
+
+		_, ok := cache.Get("127.0.0.1:16260")
+		assert.False(t, ok)
+
+		cache.Set("127.0.0.1:16260", "password")
+		cache.Set("127.0.0.1:27015", "secret")
+
+		password, ok := cache.Get("127.0.0.1:16260")
+		assert.True(t, ok)
+		assert.Equal(t, "password", password)
+
+		password, ok = cache.Get("127.0.0.1:27015")
+		assert.True(t, ok)
+		assert.Equal(t, "secret", password)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var cache *credcache.Cache
+
+		assert.NotPanics(t, func() {
+			cache.Set("127.0.0.1:16260", "password")
+		})
+
+		_, ok := cache.Get("127.0.0.1:16260")
+		assert.False(t, ok)
+	})
+}
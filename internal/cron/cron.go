@@ -0,0 +1,183 @@
+// Package cron parses crontab schedules with five fields: minute, hour, day
+// of month, month and day of week.
+//
+// Fields contain numbers, ranges (1-5), steps (*/15, 0-30/10) and lists of
+// them (1,15,30). Day of week is 0-7 where both 0 and 7 are Sunday. If both
+// day of month and day of week are restricted, the time matches either of
+// them like in crontab. Macros @yearly, @monthly, @weekly, @daily and
+// @hourly are supported too.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned when the schedule can not be parsed.
+var ErrInvalidSchedule = errors.New("invalid cron schedule")
+
+// macros contains schedules of macros.
+//
+//nolint:gochecknoglobals // Read only dictionary of macros.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxSearch limits search of the next time, schedules like `0 0 30 2 *`
+// never match.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// field is the set of allowed values of the schedule field.
+type field struct {
+	values uint64
+	star   bool
+}
+
+// has returns true if v is allowed.
+func (f field) has(v int) bool {
+	return f.values&(1<<uint(v)) != 0
+}
+
+// Schedule is the parsed crontab schedule.
+type Schedule struct {
+	minute, hour, dom, month, dow field
+}
+
+// Parse parses the crontab schedule.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := macros[spec]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 { //nolint:gomnd // Number of crontab fields.
+		return nil, fmt.Errorf("%w: %q must have 5 fields", ErrInvalidSchedule, spec)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	parsed := make([]field, len(fields))
+
+	for i, text := range fields {
+		f, err := parseField(text, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %s", ErrInvalidSchedule, spec, err)
+		}
+
+		parsed[i] = f
+	}
+
+	s := &Schedule{minute: parsed[0], hour: parsed[1], dom: parsed[2], month: parsed[3], dow: parsed[4]}
+
+	// Sunday is both 0 and 7.
+	if s.dow.has(7) {
+		s.dow.values |= 1
+	}
+
+	return s, nil
+}
+
+// parseField parses comma separated list of values, ranges and steps.
+func parseField(text string, minimum, maximum int) (field, error) {
+	var f field
+
+	for _, part := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return f, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		low, high := minimum, maximum
+
+		switch {
+		case rangeText == "*":
+			f.star = f.star || !hasStep
+		case strings.Contains(rangeText, "-"):
+			lowText, highText, _ := strings.Cut(rangeText, "-")
+
+			var err error
+			if low, err = strconv.Atoi(lowText); err != nil {
+				return f, fmt.Errorf("invalid value %q", lowText)
+			}
+
+			if high, err = strconv.Atoi(highText); err != nil {
+				return f, fmt.Errorf("invalid value %q", highText)
+			}
+		default:
+			var err error
+			if low, err = strconv.Atoi(rangeText); err != nil {
+				return f, fmt.Errorf("invalid value %q", rangeText)
+			}
+
+			high = low
+			if hasStep {
+				high = maximum
+			}
+		}
+
+		if low < minimum || high > maximum || low > high {
+			return f, fmt.Errorf("value %q is out of range %d-%d", part, minimum, maximum)
+		}
+
+		for v := low; v <= high; v += step {
+			f.values |= 1 << uint(v)
+		}
+	}
+
+	return f, nil
+}
+
+// Match returns true if the minute of t matches the schedule.
+func (s *Schedule) Match(t time.Time) bool {
+	return s.minute.has(t.Minute()) && s.hour.has(t.Hour()) && s.month.has(int(t.Month())) && s.day(t)
+}
+
+// day returns true if the day of t matches day of month or day of week.
+func (s *Schedule) day(t time.Time) bool {
+	dom, dow := s.dom.has(t.Day()), s.dow.has(int(t.Weekday()))
+
+	if s.dom.star || s.dow.star {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// Next returns the first minute after t which matches the schedule. Returns
+// zero time if the schedule never matches, e.g. February 30.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		switch {
+		case !s.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.day(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !s.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !s.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,89 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/cron"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"* * * * *", "*/15 0-6 1,15 * 1-5", "0 4 * * 7", "5-55/10 * * 1-12/2 *", "@daily"} {
+		_, err := cron.Parse(spec)
+		assert.NoError(t, err, spec)
+	}
+
+	tests := []struct {
+		spec string
+		msg  string
+	}{
+		{"* * * *", `invalid cron schedule: "* * * *" must have 5 fields`},
+		{"60 * * * *", `invalid cron schedule: "60 * * * *": value "60" is out of range 0-59`},
+		{"* * 0 * *", `invalid cron schedule: "* * 0 * *": value "0" is out of range 1-31`},
+		{"*/0 * * * *", `invalid cron schedule: "*/0 * * * *": invalid step "0"`},
+		{"5-1 * * * *", `invalid cron schedule: "5-1 * * * *": value "5-1" is out of range 0-59`},
+		{"a * * * *", `invalid cron schedule: "a * * * *": invalid value "a"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			_, err := cron.Parse(test.spec)
+			assert.ErrorIs(t, err, cron.ErrInvalidSchedule)
+			assert.EqualError(t, err, test.msg)
+		})
+	}
+}
+
+func TestSchedule_Match(t *testing.T) {
+	// 2024-01-07 is Sunday.
+	sunday := time.Date(2024, 1, 7, 4, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{"* * * * *", true},
+		{"30 4 * * *", true},
+		{"*/15 4 * * *", true},
+		{"*/20 4 * * *", false},
+		{"30 4 * * 0", true},
+		{"30 4 * * 7", true},
+		{"30 4 * * 1-5", false},
+		{"30 4 1 * 0", true},
+		{"30 4 1 * 1", false},
+		{"30 4 * 2 *", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			schedule, err := cron.Parse(test.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, schedule.Match(sunday))
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	now := time.Date(2024, 1, 31, 23, 59, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 */2 * * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 4 * * 1", time.Date(2024, 2, 5, 4, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			schedule, err := cron.Parse(test.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, schedule.Next(now))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package dictionary contains helpers for command dictionaries which are used
+// to list and complete commands of game servers.
+package dictionary
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// commandName matches names of commands in help responses.
+var commandName = regexp.MustCompile(`^[A-Za-z][\w.\-]*$`)
+
+// ParseHelp returns sorted unique names of commands from the response of
+// help command of the server. The first word of each line is the command,
+// e.g. `kick <player> - kicks the player` and `kick => kicks the player`.
+// Minecraft sends help without line breaks, so lines started with slash are
+// split at slashes. Headers and decorations are skipped.
+func ParseHelp(response string) []string {
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+
+		parts := []string{line}
+		if strings.HasPrefix(line, "/") {
+			parts = strings.Split(line, "/")
+		}
+
+		for _, part := range parts {
+			fields := strings.Fields(part)
+			if len(fields) == 0 || !commandName.MatchString(fields[0]) {
+				continue
+			}
+
+			seen[fields[0]] = true
+		}
+	}
+
+	commands := make([]string, 0, len(seen))
+	for command := range seen {
+		commands = append(commands, command)
+	}
+
+	sort.Strings(commands)
+
+	return commands
+}
+
+// Merge appends commands of next dictionaries which are not in the first
+// one. Order of commands is kept.
+func Merge(dictionaries ...[]string) []string {
+	seen := make(map[string]bool)
+
+	var merged []string
+
+	for _, commands := range dictionaries {
+		for _, command := range commands {
+			if !seen[command] {
+				seen[command] = true
+				merged = append(merged, command)
+			}
+		}
+	}
+
+	return merged
+}
+
+// Complete returns commands which start with prefix, case is ignored.
+func Complete(commands []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+
+	var completed []string
+
+	for _, command := range commands {
+		if strings.HasPrefix(strings.ToLower(command), prefix) {
+			completed = append(completed, command)
+		}
+	}
+
+	return completed
+}
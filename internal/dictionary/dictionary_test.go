@@ -0,0 +1,108 @@
+package dictionary_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHelp(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []string
+	}{
+		{
+			name:     "minecraft",
+			response: "/advancement (grant|revoke)/ban <targets> [<reason>]/ban-ip <target>/list/say <message>",
+			want:     []string{"advancement", "ban", "ban-ip", "list", "say"},
+		},
+		{
+			name: "7 days to die",
+			response: "*** Generic Console Help ***\n" +
+				"admin => Manage user permission levels\n" +
+				"listplayers lp => Lists all players\n" +
+				"version => Get the currently running version of the game\n",
+			want: []string{"admin", "listplayers", "version"},
+		},
+		{
+			name:     "source",
+			response: "kick <name>\r\n  say <message>\r\n--------\r\nkick <userid>\r\n",
+			want:     []string{"kick", "say"},
+		},
+		{
+			name:     "empty",
+			response: "",
+			want:     []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, dictionary.ParseHelp(test.response))
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged := dictionary.Merge([]string{"say", "kick"}, []string{"ban", "kick"})
+	assert.Equal(t, []string{"say", "kick", "ban"}, merged)
+}
+
+func TestComplete(t *testing.T) {
+	commands := []string{"ListPlayers", "ServerChat", "SaveWorld", "say"}
+
+	assert.Equal(t, []string{"ServerChat", "SaveWorld", "say"}, dictionary.Complete(commands, "s"))
+	assert.Equal(t, []string{"SaveWorld"}, dictionary.Complete(commands, "savew"))
+	assert.Nil(t, dictionary.Complete(commands, "kick"))
+}
+
+func TestGame(t *testing.T) {
+	for _, name := range []string{"minecraft", "rust", "csgo", "ark", "7dtd"} {
+		t.Run(name, func(t *testing.T) {
+			entries, ok := dictionary.Game(name)
+			assert.True(t, ok)
+			assert.NotEmpty(t, entries)
+
+			for _, entry := range entries {
+				assert.NotContains(t, entry.Name, "#")
+				assert.True(t, len(entry.Usage) >= len(entry.Name))
+			}
+		})
+	}
+
+	entries, _ := dictionary.Game("minecraft")
+
+	usage, ok := dictionary.Usage(entries, "KICK")
+	assert.True(t, ok)
+	assert.Equal(t, "kick <targets> [<reason>]", usage)
+	assert.True(t, dictionary.Contains(dictionary.Names(entries), "Whitelist"))
+	assert.False(t, dictionary.Contains(dictionary.Names(entries), "status"))
+
+	_, ok = dictionary.Game("tetris")
+	assert.False(t, ok)
+}
+
+func TestSuggest(t *testing.T) {
+	commands := []string{"ListPlayers", "KickPlayer", "BanPlayer", "say", "save", "kick"}
+
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"kickplayr", []string{"KickPlayer"}},
+		{"sya", []string{"say"}},
+		{"sav", []string{"say", "save"}},
+		{"bna", nil},
+		{"kick", []string{"kick"}},
+		{"restart", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			assert.Equal(t, test.want, dictionary.Suggest(commands, test.command))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package dictionary
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+)
+
+// games contains bundled dictionaries of games, one command usage per line.
+//
+//go:embed games/*.txt
+var games embed.FS
+
+// Entry is the command of the dictionary.
+type Entry struct {
+	// Name is the name of the command.
+	Name string
+	// Usage is the syntax hint, e.g. `kick <player> [<reason>]`.
+	Usage string
+}
+
+// Game returns the bundled dictionary of the game. Dictionaries are named
+// after game presets.
+func Game(name string) ([]Entry, bool) {
+	data, err := games.ReadFile("games/" + name + ".txt")
+	if err != nil {
+		return nil, false
+	}
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(line, " ")
+		entries = append(entries, Entry{Name: name, Usage: line})
+	}
+
+	return entries, true
+}
+
+// Names returns names of commands of the entries.
+func Names(entries []Entry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+
+	return names
+}
+
+// Usage returns the syntax hint of the command, case is ignored.
+func Usage(entries []Entry, command string) (string, bool) {
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name, command) {
+			return entry.Usage, true
+		}
+	}
+
+	return "", false
+}
+
+// Contains returns true if commands contain the command, case is ignored.
+func Contains(commands []string, command string) bool {
+	for _, name := range commands {
+		if strings.EqualFold(name, command) {
+			return true
+		}
+	}
+
+	return false
+}
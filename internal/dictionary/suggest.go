@@ -0,0 +1,73 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Suggest returns commands which are closest to the mistyped command by edit
+// distance, case is ignored. Commands which differ in more than a third of
+// characters are not suggested, so unrelated commands are not proposed.
+func Suggest(commands []string, command string) []string {
+	if command == "" {
+		return nil
+	}
+
+	command = strings.ToLower(command)
+	limit := max(1, utf8.RuneCountInString(command)/3)
+
+	var (
+		suggested []string
+		best      = limit + 1
+	)
+
+	for _, candidate := range commands {
+		d := distance(strings.ToLower(candidate), command)
+
+		switch {
+		case d > limit:
+			continue
+		case d < best:
+			best = d
+			suggested = []string{candidate}
+		case d == best:
+			suggested = append(suggested, candidate)
+		}
+	}
+
+	return suggested
+}
+
+// distance returns edit distance between a and b. Insertion, deletion,
+// substitution and transposition of adjacent characters cost 1, so typos
+// like "sya" are close to "say".
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,124 @@
+// Package discover probes well-known RCON ports on a host.
+package discover
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidPorts is returned when ports list can not be parsed.
+var ErrInvalidPorts = errors.New("invalid ports")
+
+// Port is a port to probe with protocol and games which use it by default.
+type Port struct {
+	Number   int
+	Protocol string
+	Games    string
+}
+
+// Result is the result of probing a port.
+type Result struct {
+	Port
+	Open bool
+}
+
+// KnownPorts returns well-known RCON, Web RCON and telnet ports.
+func KnownPorts() []Port {
+	ports := []Port{
+		{Number: 25575, Protocol: "rcon", Games: "Minecraft, Palworld, Conan Exiles"},
+		{Number: 27015, Protocol: "rcon", Games: "Source servers, Project Zomboid"},
+		{Number: 27020, Protocol: "rcon", Games: "ARK: Survival Evolved"},
+		{Number: 28016, Protocol: "web", Games: "Rust"},
+		{Number: 8081, Protocol: "telnet", Games: "7 Days to Die"},
+	}
+
+	for number := 24570; number <= 24579; number++ {
+		ports = append(ports, Port{Number: number, Protocol: "rcon", Games: "alternative RCON ports"})
+	}
+
+	return ports
+}
+
+// ParsePorts parses comma separated list of ports and port ranges, for
+// example "25575,27015-27020". Protocol of known ports is kept.
+func ParsePorts(list string) ([]Port, error) {
+	known := make(map[int]Port)
+	for _, port := range KnownPorts() {
+		known[port.Number] = port
+	}
+
+	var ports []Port
+
+	for _, item := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(item), "-")
+
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPorts, item)
+		}
+
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(last); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidPorts, item)
+			}
+		}
+
+		if from < 1 || to > 65535 || from > to {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPorts, item)
+		}
+
+		for number := from; number <= to; number++ {
+			port, ok := known[number]
+			if !ok {
+				port = Port{Number: number}
+			}
+
+			ports = append(ports, port)
+		}
+	}
+
+	return ports, nil
+}
+
+// Scan concurrently connects to the ports on the host and returns results
+// sorted by port number.
+func Scan(host string, ports []Port, timeout time.Duration) []Result {
+	results := make([]Result, len(ports))
+
+	var wg sync.WaitGroup
+
+	for i, port := range ports {
+		wg.Add(1)
+
+		go func(i int, port Port) {
+			defer wg.Done()
+
+			results[i] = Result{Port: port, Open: probe(host, port.Number, timeout)}
+		}(i, port)
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Number < results[j].Number })
+
+	return results
+}
+
+// probe returns true if TCP connection to the port can be established.
+func probe(host string, port int, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
@@ -0,0 +1,56 @@
+package discover_test
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePorts(t *testing.T) {
+	ports, err := discover.ParsePorts("28016, 30000-30001")
+	assert.NoError(t, err)
+	assert.Equal(t, []discover.Port{
+		{Number: 28016, Protocol: "web", Games: "Rust"},
+		{Number: 30000},
+		{Number: 30001},
+	}, ports)
+
+	for _, list := range []string{"", "port", "1-x", "0", "70000", "20-10"} {
+		_, err = discover.ParsePorts(list)
+		assert.ErrorIs(t, err, discover.ErrInvalidPorts, list)
+	}
+}
+
+func TestScan(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	open := listener.Addr().(*net.TCPAddr).Port
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	ports, err := discover.ParsePorts(strconv.Itoa(closedPort) + "," + strconv.Itoa(open))
+	assert.NoError(t, err)
+
+	results := discover.Scan("127.0.0.1", ports, time.Second)
+	if assert.Len(t, results, 2) {
+		for _, result := range results {
+			assert.Equal(t, result.Number == open, result.Open)
+		}
+
+		assert.Less(t, results[0].Number, results[1].Number)
+	}
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/gorcon/rcon-cli/internal/agent"
+	"github.com/gorcon/rcon-cli/internal/credcache"
+	"github.com/urfave/cli/v2"
+)
+
+// agentCommand returns subcommand which manages credential agent.
+func (executor *Executor) agentCommand() *cli.Command {
+	socket := &cli.StringFlag{
+		Name:  "socket",
+		Usage: "Path to the agent socket, " + agent.EnvSocket + " variable is used by other commands",
+		Value: agent.Socket(),
+	}
+
+	return &cli.Command{
+		Name:  "agent",
+		Usage: "Keep entered passwords in memory for other invocations like ssh-agent",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "start",
+				Usage:  "Run the agent until interrupted or stopped",
+				Flags:  []cli.Flag{socket},
+				Action: executor.agentStart,
+			},
+			{
+				Name:  "stop",
+				Usage: "Stop the running agent, kept passwords are forgotten",
+				Flags: []cli.Flag{socket},
+				Action: func(c *cli.Context) error {
+					return agent.NewClient(c.String("socket")).Stop()
+				},
+			},
+		},
+	}
+}
+
+// agentStart serves the agent on the socket.
+func (executor *Executor) agentStart(c *cli.Context) error {
+	cache, err := credcache.New()
+	if err != nil {
+		return err
+	}
+
+	socket := c.String("socket")
+
+	listener, err := agent.Listen(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	_, _ = fmt.Fprintf(executor.w, "%s=%s; export %s\n", agent.EnvSocket, socket, agent.EnvSocket)
+
+	return agent.Serve(ctx, listener, cache)
+}
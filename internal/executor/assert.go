@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// ErrAssertionFailed is returned when the response does not match --expect
+// or matches --expect-not, so the exit code of health checks is not zero.
+var ErrAssertionFailed = errors.New("assertion failed")
+
+// assert returns ErrAssertionFailed if the response does not match the
+// expected pattern of the session or matches the unexpected one.
+func assert(ses *config.Session, response string) error {
+	if ses.Expect != "" {
+		matched, err := regexp.MatchString(ses.Expect, response)
+		if err != nil {
+			return fmt.Errorf("expect: %w", err)
+		}
+
+		if !matched {
+			return fmt.Errorf("%w: response does not match %q", ErrAssertionFailed, ses.Expect)
+		}
+	}
+
+	if ses.ExpectNot != "" {
+		matched, err := regexp.MatchString(ses.ExpectNot, response)
+		if err != nil {
+			return fmt.Errorf("expect not: %w", err)
+		}
+
+		if matched {
+			return fmt.Errorf("%w: response matches %q", ErrAssertionFailed, ses.ExpectNot)
+		}
+	}
+
+	return nil
+}
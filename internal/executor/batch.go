@@ -0,0 +1,234 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/batch"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/urfave/cli/v2"
+)
+
+// Batch file directives.
+const (
+	// DirectiveSleep pauses execution. Example: `@sleep 30s`.
+	DirectiveSleep = batch.DirectiveSleep
+
+	// DirectiveRepeat executes the next command several times.
+	// Example: `@repeat 3`.
+	DirectiveRepeat = batch.DirectiveRepeat
+
+	// DirectiveEnv switches the next commands to another config environment.
+	// Example: `@env other`.
+	DirectiveEnv = batch.DirectiveEnv
+
+	// DirectiveExpect waits for the output of the next command.
+	// Example: `@expect /Saved/ 1m`.
+	DirectiveExpect = batch.DirectiveExpect
+
+	// DirectiveCapture stores named groups of the response of the next
+	// command to variables. Example: `@capture /id=(?P<id>\d+)/`.
+	DirectiveCapture = batch.DirectiveCapture
+
+	// DirectiveIf executes the block if the response of the previous command
+	// matches the pattern. Example: `@if /0 players/`.
+	DirectiveIf = batch.DirectiveIf
+
+	// DirectiveElse starts the block which is executed if the condition of
+	// DirectiveIf is false.
+	DirectiveElse = batch.DirectiveElse
+
+	// DirectiveEnd ends the block of DirectiveIf.
+	DirectiveEnd = batch.DirectiveEnd
+)
+
+var (
+	// ErrFileWithCommands is returned when batch file and commands are set
+	// at the same time.
+	ErrFileWithCommands = errors.New("commands can not be used together with batch file")
+
+	// ErrInvalidDirective is returned when batch file contains unknown or
+	// malformed directive.
+	ErrInvalidDirective = batch.ErrInvalidDirective
+
+	// ErrUnknownVariable is returned when the command uses the variable
+	// which is not captured.
+	ErrUnknownVariable = batch.ErrUnknownVariable
+
+	// ErrNotCaptured is returned when the response does not match the
+	// pattern of DirectiveCapture.
+	ErrNotCaptured = batch.ErrNotCaptured
+)
+
+// block is the block of DirectiveIf.
+type block struct {
+	// parent is true if lines around the block are executed.
+	parent bool
+	// active is true if lines of the current branch of the block are
+	// executed.
+	active bool
+}
+
+// executing returns true if lines inside blocks are executed.
+func executing(blocks []block) bool {
+	return len(blocks) == 0 || blocks[len(blocks)-1].active
+}
+
+// batch executes commands and directives from the batch file. The whole
+// file is parsed before the first command is sent, see batch package for
+// the format. Conditions of DirectiveIf are checked with the printed
+// response of the last executed command.
+func (executor *Executor) batch(c *cli.Context, ses *config.Session, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	defer file.Close()
+
+	lines, err := batch.Parse(file)
+	if err != nil {
+		return err //nolint:wrapcheck // Errors of batch package contain line numbers.
+	}
+
+	done, finish := executor.startProgress(c, batch.Count(lines))
+	defer finish()
+
+	repeat, executed := 1, 0
+
+	var (
+		expect, capture batch.Line
+		blocks          []block
+		response        string
+		errs            []error
+	)
+
+	vars := make(map[string]string)
+
+	for _, line := range lines {
+		// Parse checks that blocks are balanced.
+		switch line.Directive {
+		case DirectiveIf:
+			parent := executing(blocks)
+			matched, _ := regexp.MatchString(line.Pattern, response)
+			blocks = append(blocks, block{parent: parent, active: parent && matched != line.Not})
+
+			continue
+		case DirectiveElse:
+			last := &blocks[len(blocks)-1]
+			last.active = last.parent && !last.active
+
+			continue
+		case DirectiveEnd:
+			blocks = blocks[:len(blocks)-1]
+
+			continue
+		}
+
+		if !executing(blocks) {
+			continue
+		}
+
+		switch line.Directive {
+		case "":
+			for ; repeat > 0; repeat-- {
+				if executed != 0 && !output.IsStructured(ses.Format) && executor.header == "" {
+					_, _ = fmt.Fprintln(executor.w, CommandsResponseSeparator)
+				}
+
+				if response, err = executor.batchExecute(ses, line.Command, expect, capture, vars); err != nil {
+					if !executor.keepGoing {
+						return err
+					}
+
+					errs = append(errs, fmt.Errorf("line %d: %w", line.Number, err))
+				}
+
+				done()
+
+				executed++
+			}
+
+			repeat, expect, capture = 1, batch.Line{}, batch.Line{}
+		case DirectiveSleep:
+			time.Sleep(line.Duration)
+		case DirectiveRepeat:
+			repeat = line.Count
+		case DirectiveExpect:
+			expect = line
+		case DirectiveCapture:
+			capture = line
+		case DirectiveEnv:
+			if ses, err = executor.switchEnv(c, line.Env); err != nil {
+				return err
+			}
+		}
+	}
+
+	return failures(errs, executed, "commands")
+}
+
+// batchExecute executes the command of the batch file with expanded
+// variables and returns its printed response. The command after
+// DirectiveExpect waits until its response or the console output has the
+// expected line. Variables are captured from the response of the command
+// after DirectiveCapture.
+func (executor *Executor) batchExecute(
+	ses *config.Session, command string, expect, capture batch.Line, vars map[string]string,
+) (string, error) {
+	command, err := batch.Expand(command, vars)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Error of batch package contains the variable.
+	}
+
+	var response bytes.Buffer
+
+	send := func() (string, error) {
+		err := executor.Execute(io.MultiWriter(executor.w, &response), ses, command)
+
+		return response.String(), err
+	}
+
+	if expect.Directive == DirectiveExpect {
+		var pattern *regexp.Regexp
+		if pattern, err = regexp.Compile(expect.Pattern); err != nil {
+			return "", fmt.Errorf("expect: %w", err)
+		}
+
+		_, err = executor.expect(ses, pattern, expect.Duration, send)
+	} else {
+		_, err = send()
+	}
+
+	if err != nil || capture.Directive != DirectiveCapture {
+		return response.String(), err
+	}
+
+	return response.String(), batch.Capture(capture.Pattern, response.String(), vars) //nolint:wrapcheck // Error of batch package contains the pattern.
+}
+
+// switchEnv closes current connection and creates session for another
+// config environment.
+func (executor *Executor) switchEnv(c *cli.Context, env string) (*config.Session, error) {
+	ses, err := executor.newSession(c, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if ses.Address == "" {
+		return nil, ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return nil, ErrEmptyPassword
+	}
+
+	_ = executor.Close()
+
+	return ses, nil
+}
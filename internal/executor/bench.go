@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/bench"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// Default settings of bench command.
+const (
+	DefaultBenchCommand = "status"
+	DefaultBenchCount   = 100
+)
+
+// ErrInvalidCount is returned when number of bench requests is not positive.
+var ErrInvalidCount = errors.New("number of requests must be positive")
+
+// benchCommand returns subcommand which measures latency of the server.
+func (executor *Executor) benchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: "Send a command repeatedly and report latency statistics",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.IntFlag{
+				Name:    "count",
+				Aliases: []string{"n"},
+				Usage:   "Number of requests",
+				Value:   DefaultBenchCount,
+			},
+			&cli.StringFlag{
+				Name:  "command",
+				Usage: "Command to send",
+				Value: DefaultBenchCommand,
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "Pause between requests",
+			},
+		},
+		Action: executor.bench,
+	}
+}
+
+// bench sends the command count times and prints min, avg, p95 and max
+// latency of successful requests and the number of errors.
+func (executor *Executor) bench(c *cli.Context) error {
+	count := c.Int("count")
+	if count <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidCount, count)
+	}
+
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	command := c.String("command")
+	if err = executor.check(ses, command); err != nil {
+		return err
+	}
+
+	latencies := make([]time.Duration, 0, count)
+	failed := 0
+
+	for i := 0; i < count; i++ {
+		if i != 0 {
+			time.Sleep(c.Duration("interval"))
+		}
+
+		var latency time.Duration
+		if latency, err = executor.measure(ses, command); err != nil {
+			failed++
+
+			continue
+		}
+
+		latencies = append(latencies, latency)
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "%s %q: %s\n", ses.Address, command, bench.Summarize(latencies, failed))
+
+	return executor.Close()
+}
+
+// measure sends the command and returns its round-trip time. Connection is
+// reestablished on the next request after an error.
+func (executor *Executor) measure(ses *config.Session, command string) (time.Duration, error) {
+	if err := executor.Dial(ses); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+
+	if _, err := executor.client.Execute(command); err != nil {
+		_ = executor.Close()
+
+		return 0, fmt.Errorf("execute: %w", client.Classify(err))
+	}
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,30 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// ErrInvalidBindAddr is returned when bind address is not an IP address.
+var ErrInvalidBindAddr = errors.New("invalid bind address")
+
+// dialer returns dialer of connections to the server which originate from
+// the bind address of the session.
+func dialer(ses *config.Session) (*net.Dialer, error) {
+	d := net.Dialer{Timeout: ses.Timeout}
+	if ses.BindAddr == "" {
+		return &d, nil
+	}
+
+	ip := net.ParseIP(ses.BindAddr)
+	if ip == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBindAddr, ses.BindAddr)
+	}
+
+	d.LocalAddr = &net.TCPAddr{IP: ip}
+
+	return &d, nil
+}
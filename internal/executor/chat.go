@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/urfave/cli/v2"
+)
+
+// ChatMessageType is the type of Web RCON broadcasts with in-game chat
+// messages of Rust.
+const ChatMessageType = "Chat"
+
+// ChatChannelTeam is the channel of Rust team chat messages.
+const ChatChannelTeam = 1
+
+// ErrChatUnsupported is returned when chat is opened for the protocol which
+// does not broadcast chat messages.
+var ErrChatUnsupported = errors.New("chat is supported for web protocol only")
+
+// chatMessage is the message of Rust chat broadcast.
+type chatMessage struct {
+	Channel  int    `json:"Channel"`
+	Message  string `json:"Message"`
+	Username string `json:"Username"`
+	Time     int64  `json:"Time"`
+}
+
+// chatCommand returns subcommand which streams in-game chat of the server
+// and sends typed messages with say command.
+func (executor *Executor) chatCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "chat",
+		Usage: "Follow in-game chat of web server and send typed messages with say command",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+		},
+		Action: executor.chat,
+	}
+}
+
+// chat prints chat messages of the server and broadcasts lines of the input
+// until interrupted, quit command is typed or the connection is lost. Chat
+// is followed after the end of the input.
+func (executor *Executor) chat(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	if err = checkPlaintext(ses); err != nil {
+		return err
+	}
+
+	if err = executor.detect(ses); err != nil {
+		return err
+	}
+
+	if ses.Type != config.ProtocolWebRCON {
+		return ErrChatUnsupported
+	}
+
+	d, err := dialer(ses)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts, err := webOptions(ses)
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.OpenStream(ctx, ses.Type, ses.Address, ses.Password,
+		append(opts, client.WithTimeout(ses.Timeout), client.WithDialer(d), client.WithMessageTypes(ChatMessageType))...)
+	if err != nil {
+		return fmt.Errorf("chat: %w", err)
+	}
+	defer stream.Close()
+
+	_, _ = fmt.Fprintf(executor.w, "Chat of %s (type message to send or %s to exit)\n", ses.Address, CommandQuit)
+
+	command := ses.SayCommand
+	if command == "" {
+		command = DefaultSayCommand
+	}
+
+	lines, input := stream.Lines(), chatInput(ctx, executor.r)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+
+			executor.tailLine(ses, nil, formatChat(line))
+		case message, ok := <-input:
+			if !ok {
+				input = nil
+
+				continue
+			}
+
+			if message == CommandQuit {
+				return nil
+			}
+
+			// Sent message is printed when the server broadcasts it.
+			if err = executor.Execute(io.Discard, ses, command+" "+message); err != nil {
+				_, _ = fmt.Fprintln(executor.w, redact.Error(err))
+			}
+		}
+	}
+}
+
+// chatInput returns channel of non-empty lines of r. The channel is closed
+// when r ends, it is nil if r is not set. Lines are not sent after ctx is
+// done.
+func chatInput(ctx context.Context, r io.Reader) <-chan string {
+	if r == nil {
+		return nil
+	}
+
+	input := make(chan string)
+
+	go func() {
+		defer close(input)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			message := strings.TrimSpace(scanner.Text())
+			if message == "" {
+				continue
+			}
+
+			select {
+			case input <- message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return input
+}
+
+// formatChat returns the chat message as the line with time, channel and
+// the name of the player. Messages which are not JSON are returned as is.
+func formatChat(line string) string {
+	var message chatMessage
+	if err := json.Unmarshal([]byte(line), &message); err != nil {
+		return line
+	}
+
+	prefix := time.Unix(message.Time, 0).Format(time.TimeOnly) + " "
+	if message.Channel == ChatChannelTeam {
+		prefix += "[team] "
+	}
+
+	return prefix + message.Username + ": " + message.Message
+}
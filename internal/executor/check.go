@@ -0,0 +1,177 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/check"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/urfave/cli/v2"
+)
+
+// CheckService is the name of the service in the output of check command.
+const CheckService = "RCON"
+
+// ErrEmptyCheckCommand is returned when check command has no command to
+// execute.
+var ErrEmptyCheckCommand = errors.New("command is not set: add --command or game or players_command " +
+	"and players_pattern to config environment")
+
+// ExitError is returned by commands which print their result themselves and
+// report it with the exit code, e.g. check.
+type ExitError struct {
+	Code int
+}
+
+// Error implements error interface.
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit code %d", e.Code)
+}
+
+// ExitCode returns the exit code of err. The second value is true if the
+// error is already reported by the command and should not be printed.
+func ExitCode(err error) (int, bool) {
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit.Code, true
+	}
+
+	return 1, false
+}
+
+// checkCommand returns subcommand which checks the server for monitoring
+// systems compatible with Nagios plugins.
+func (executor *Executor) checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Check the server and print the result in Nagios plugin format with OK, WARNING or CRITICAL exit code",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:  "command",
+				Usage: "Command to execute. Player list command of the environment by default",
+			},
+			&cli.IntFlag{
+				Name:  "warn-players",
+				Usage: "Number of online players for WARNING status",
+			},
+			&cli.IntFlag{
+				Name:  "crit-players",
+				Usage: "Number of online players for CRITICAL status",
+			},
+			&cli.DurationFlag{
+				Name:  "warn-time",
+				Usage: "Response time for WARNING status",
+			},
+			&cli.DurationFlag{
+				Name:  "crit-time",
+				Usage: "Response time for CRITICAL status",
+			},
+		},
+		Action: executor.checkServer,
+	}
+}
+
+// checkServer executes the command, counts online players if the command is
+// the player list command of the environment and prints the result with
+// performance data. Errors are printed with UNKNOWN status. The exit code
+// is the status of the check.
+func (executor *Executor) checkServer(c *cli.Context) error {
+	result, err := executor.checkResult(c)
+	if err != nil {
+		_, _ = fmt.Fprintln(executor.w, redact.String(check.Fail(CheckService, err)))
+
+		return &ExitError{Code: int(check.Unknown)}
+	}
+
+	_, _ = fmt.Fprintln(executor.w, result)
+
+	if status := result.Status(); status != check.OK {
+		return &ExitError{Code: int(status)}
+	}
+
+	return nil
+}
+
+// checkResult returns metrics of the server with thresholds from flags.
+func (executor *Executor) checkResult(c *cli.Context) (check.Result, error) {
+	result := check.Result{Service: CheckService}
+
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return result, err
+	}
+
+	if ses.Address == "" {
+		return result, ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return result, ErrEmptyPassword
+	}
+
+	command := c.String("command")
+	if command == "" {
+		command = ses.PlayersCommand
+	}
+
+	if command == "" {
+		return result, ErrEmptyCheckCommand
+	}
+
+	counted := command == ses.PlayersCommand && ses.PlayersPattern != ""
+	if !counted && (c.IsSet("warn-players") || c.IsSet("crit-players")) {
+		return result, ErrEmptyPlayers
+	}
+
+	start := time.Now()
+
+	response, err := executor.request(ses, command)
+	if err != nil {
+		return result, err
+	}
+
+	elapsed := time.Since(start).Round(time.Microsecond).Seconds()
+
+	if counted {
+		var re *regexp.Regexp
+		if re, err = player.Compile(ses.PlayersPattern); err != nil {
+			return result, fmt.Errorf("check: %w", err)
+		}
+
+		players := len(player.Parse(re, response))
+		result.Text = fmt.Sprintf("%d players, ", players)
+		result.Metrics = append(result.Metrics, check.Metric{
+			Label: "players",
+			Value: float64(players),
+			Warn:  float64(c.Int("warn-players")),
+			Crit:  float64(c.Int("crit-players")),
+		})
+	}
+
+	result.Text += fmt.Sprintf("response time %.3fs", elapsed)
+	result.Metrics = append(result.Metrics, check.Metric{
+		Label: "time",
+		Value: elapsed,
+		Unit:  "s",
+		Warn:  c.Duration("warn-time").Seconds(),
+		Crit:  c.Duration("crit-time").Seconds(),
+	})
+
+	return result, nil
+}
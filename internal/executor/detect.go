@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// detect replaces auto type of the session with the protocol detected on the
+// server, so the protocol is detected once per session.
+func (executor *Executor) detect(ses *config.Session) error {
+	if ses.Type != config.ProtocolAuto {
+		return nil
+	}
+
+	d, err := dialer(ses)
+	if err != nil {
+		return err
+	}
+
+	opts, err := webOptions(ses)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, client.WithTimeout(ses.Timeout))
+	if ses.BindAddr != "" {
+		opts = append(opts, client.WithDialer(d))
+	}
+
+	protocol, err := client.Detect(context.Background(), ses.Address, ses.Password, opts...)
+	if protocol == "" {
+		return fmt.Errorf("detect: %w", err)
+	}
+
+	// Authentication error is returned by dial.
+	ses.Type = protocol
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/discover"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrEmptyHost is returned when discover is called without host.
+var ErrEmptyHost = errors.New("host is not set: to discover ports type discover host")
+
+// discoverCommand returns subcommand which probes well-known RCON ports.
+func (executor *Executor) discoverCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "discover",
+		Usage:     "Probe well-known RCON ports on the host and report which respond",
+		ArgsUsage: "host",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "ports",
+				Usage: "Probe custom ports instead of well-known ones. Example 25575,27015-27020",
+			},
+			&cli.DurationFlag{
+				Name:  "probe-timeout",
+				Usage: "Set connect timeout for each port",
+				Value: 2 * time.Second,
+			},
+		},
+		Action: executor.discover,
+	}
+}
+
+// discover prints open ports of the host with protocols to use.
+func (executor *Executor) discover(c *cli.Context) error {
+	host := c.Args().First()
+	if host == "" {
+		return ErrEmptyHost
+	}
+
+	ports := discover.KnownPorts()
+
+	if list := c.String("ports"); list != "" {
+		var err error
+		if ports, err = discover.ParsePorts(list); err != nil {
+			return err
+		}
+	}
+
+	found := 0
+
+	for _, result := range discover.Scan(host, ports, c.Duration("probe-timeout")) {
+		if !result.Open {
+			continue
+		}
+
+		found++
+
+		address := net.JoinHostPort(host, strconv.Itoa(result.Number))
+
+		switch {
+		case result.Protocol == "":
+			_, _ = fmt.Fprintf(executor.w, "%s open\n", address)
+		case result.Games == "":
+			_, _ = fmt.Fprintf(executor.w, "%s open, try -t %s\n", address, result.Protocol)
+		default:
+			_, _ = fmt.Fprintf(executor.w, "%s open, try -t %s (%s)\n", address, result.Protocol, result.Games)
+		}
+	}
+
+	if found == 0 {
+		_, _ = fmt.Fprintf(executor.w, "No open ports found on %s\n", host)
+	}
+
+	return nil
+}
@@ -2,19 +2,44 @@ package executor
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/agent"
+	"github.com/gorcon/rcon-cli/internal/alert"
+	"github.com/gorcon/rcon-cli/internal/alias"
+	"github.com/gorcon/rcon-cli/internal/announce"
 	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/credcache"
+	"github.com/gorcon/rcon-cli/internal/extract"
+	"github.com/gorcon/rcon-cli/internal/hook"
 	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon-cli/internal/maintenance"
+	"github.com/gorcon/rcon-cli/internal/otlp"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/gorcon/rcon-cli/internal/pager"
+	"github.com/gorcon/rcon-cli/internal/parser"
+	"github.com/gorcon/rcon-cli/internal/policy"
+	"github.com/gorcon/rcon-cli/internal/progress"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/secret"
+	"github.com/gorcon/rcon-cli/internal/statsd"
+	"github.com/gorcon/rcon-cli/internal/summary"
+	"github.com/gorcon/rcon-cli/internal/terminal"
+	"github.com/gorcon/rcon-cli/internal/text"
+	"github.com/gorcon/rcon-cli/internal/trace"
+	"github.com/gorcon/rcon-cli/internal/zabbix"
 	"github.com/gorcon/telnet"
-	"github.com/gorcon/websocket"
 	"github.com/urfave/cli/v2"
 )
 
@@ -25,6 +50,10 @@ const CommandQuit = ":q"
 // several commands if more than one command was called.
 const CommandsResponseSeparator = "--------"
 
+// EnvPassword is the environment variable with the password to remote
+// server. It takes precedence over the password flag and the config.
+const EnvPassword = "RCON_PASSWORD"
+
 // Errors.
 var (
 	// ErrEmptyAddress is returned when executed command without setting address
@@ -37,13 +66,18 @@ var (
 
 	// ErrCommandEmpty is returned when executed command length equal 0.
 	ErrCommandEmpty = errors.New("command is not set")
-)
 
-// ExecuteCloser is the interface that groups Execute and Close methods.
-type ExecuteCloser interface {
-	Execute(command string) (string, error)
-	Close() error
-}
+	// ErrUnknownParser is returned when parser is not defined in the config.
+	ErrUnknownParser = errors.New("unknown parser")
+
+	// ErrCommandCancelled is returned when user did not confirm execution
+	// of the destructive command.
+	ErrCommandCancelled = errors.New("command is cancelled")
+
+	// ErrNotConnected is returned when command is sent by hook without
+	// connection to the remote server.
+	ErrNotConnected = errors.New("not connected")
+)
 
 // Executor is a cli commands execute wrapper.
 type Executor struct {
@@ -52,22 +86,122 @@ type Executor struct {
 	w       io.Writer
 	app     *cli.App
 
-	client ExecuteCloser
+	client  client.Conn
+	outputs map[outputKey]output.Writer
+	parsers map[string]parser.Definition
+	aliases alias.Aliases
+	hooks   *hook.JS
+	exec    *hook.Exec
+	scanner *bufio.Scanner
+
+	templates announce.Templates
+	groups    map[string][]string
+	envs      config.Config
+
+	// mu guards the client, timing and compiled commands policies, as
+	// upstream handlers send commands of pipelined connections concurrently.
+	// Policies are keyed by their patterns, as sessions are replaced in
+	// place when the environment changes.
+	mu       sync.Mutex
+	policies map[string]*policy.Policy
+
+	// sessions loads session for environment in interactive mode and env
+	// is the environment chosen with :use meta-command.
+	sessions func(env string) (*config.Session, error)
+	env      string
+
+	// last is the last received response, it is used by :copy meta-command.
+	last string
+
+	// timing contains dial and auth durations of the new connection which
+	// are reported with the next response.
+	timing *output.Timing
+
+	// tracer records packets of connections if --trace flag is set.
+	tracer *trace.Tracer
+
+	// summary collects outcomes of commands if --summary flag is set.
+	summary *summary.Summary
+
+	// statsd receives metrics of commands if --statsd flag is set.
+	statsd *statsd.Client
+
+	// otel collects spans of the run if --otlp-endpoint flag is set.
+	otel *otlp.Tracer
+
+	// zabbix collects values of trapper items if --zabbix flag is set.
+	zabbix *zabbix.Batch
+
+	// header is --header template printed before each response instead of
+	// the separator, headers is the number of printed headers.
+	header  string
+	headers int
+
+	// keepGoing continues batch and fan-out execution after failures if
+	// --keep-going flag is set, errors are reported at the end.
+	keepGoing bool
+
+	// progress is the progress line of the run if --progress flag is set.
+	progress *progress.Bar
+
+	// learned contains commands from the help response of the server in
+	// interactive mode.
+	learned []string
+
+	// color is true if responses are highlighted, i.e. the output is
+	// a terminal with colors.
+	color bool
+
+	// alerts notifies webhooks about responses which match alerts of the
+	// config file.
+	alerts *alert.Alerter
+
+	// maintenance contains maintenance windows of environments from the
+	// config file.
+	maintenance maintenance.Windows
+
+	// databases keeps SQLite logs open until the run ends. They are shared
+	// with executors of workers and upstreams.
+	databases *logger.Databases
+
+	// credentials caches passwords entered interactively unless --no-cache
+	// flag is set.
+	credentials *credcache.Cache
+
+	// agent shares entered passwords with other invocations through the
+	// credential agent of RCON_AGENT_SOCK variable unless --no-cache flag is
+	// set.
+	agent *agent.Client
 }
 
 // NewExecutor creates a new Executor.
 func NewExecutor(r io.Reader, w io.Writer, version string) *Executor {
 	return &Executor{
-		version: version,
-		r:       r,
-		w:       w,
+		version:   version,
+		r:         r,
+		w:         w,
+		aliases:   alias.Aliases{},
+		color:     terminal.UseColor(w),
+		databases: &logger.Databases{},
 	}
 }
 
+// child creates executor which runs commands for the executor on its own
+// connection, e.g. of a group worker or a serve mode upstream. SQLite logs
+// are shared with the child.
+func (executor *Executor) child(r io.Reader, w io.Writer) *Executor {
+	child := NewExecutor(r, w, executor.version)
+	child.databases = executor.databases
+
+	return child
+}
+
 // Run is the entry point to the cli app.
 func (executor *Executor) Run(arguments []string) error {
 	executor.init()
 
+	defer executor.databases.Close()
+
 	if err := executor.app.Run(arguments); err != nil && !errors.Is(err, flag.ErrHelp) {
 		return fmt.Errorf("cli: %w", err)
 	}
@@ -79,6 +213,84 @@ func (executor *Executor) Run(arguments []string) error {
 // a remote server. If the address and password flags were received the
 // configuration file is ignored.
 func (executor *Executor) NewSession(c *cli.Context) (*config.Session, error) {
+	return executor.newSession(c, c.String("env"))
+}
+
+// newSession creates session for the config environment env.
+func (executor *Executor) newSession(c *cli.Context, env string) (*config.Session, error) {
+	ses := flagSession(c)
+
+	// Passwords are removed from errors and debug output.
+	defer func() { redact.Add(ses.Password) }()
+
+	file, err := config.NewFile(c.String("config"))
+	if err != nil {
+		// Config file is optional if credentials are received from flags.
+		if ses.Address != "" && ses.Password != "" && ses.Parse == "" {
+			return &ses, nil
+		}
+
+		return &ses, fmt.Errorf("config: %w", err)
+	}
+
+	// Parsers, aliases and templates are taken from the config file even if
+	// credentials are received from flags.
+	executor.parsers = file.Parsers
+	executor.templates = file.Templates
+	executor.groups = file.Groups
+	executor.envs = file.Environments
+	executor.maintenance = file.Maintenance
+
+	for name, template := range file.Aliases {
+		executor.aliases[name] = template
+	}
+
+	if executor.hooks == nil && len(file.Hooks.JS) != 0 {
+		if executor.hooks, err = hook.NewJS(file.Hooks.JS, executor.w, executor.send); err != nil {
+			return &ses, err
+		}
+	}
+
+	if executor.exec == nil && (len(file.Hooks.Pre) != 0 || len(file.Hooks.Post) != 0) {
+		if executor.exec, err = hook.NewExec(file.Hooks.Pre, file.Hooks.Post, executor.w); err != nil {
+			return &ses, err
+		}
+	}
+
+	if executor.alerts == nil {
+		if executor.alerts, err = alert.New(file.Alerts); err != nil {
+			return &ses, err
+		}
+	}
+
+	if ses.Address != "" && ses.Password != "" {
+		return &ses, nil
+	}
+
+	if env == "" {
+		env = config.DefaultConfigEnv
+	}
+
+	mergeSession(c, &ses, file.Environments, env)
+
+	if ses.Password, err = secret.Resolve(ses.Password); err != nil {
+		return &ses, fmt.Errorf("%s: %w", env, err)
+	}
+
+	if ses.Password == "" && !c.Bool("no-cache") {
+		// Password entered in another invocation is kept by the agent.
+		ses.Password, _ = agent.FromEnv().Get(ses.Address)
+	}
+
+	if ses.Headers, err = resolveHeaders(ses.Headers); err != nil {
+		return &ses, fmt.Errorf("%s: %w", env, err)
+	}
+
+	return &ses, nil
+}
+
+// flagSession creates session from flags and environment variables.
+func flagSession(c *cli.Context) config.Session {
 	ses := config.Session{
 		Address:    c.String("address"),
 		Password:   c.String("password"),
@@ -87,63 +299,225 @@ func (executor *Executor) NewSession(c *cli.Context) (*config.Session, error) {
 		SkipErrors: c.Bool("skip"),
 		Timeout:    c.Duration("timeout"),
 		Variables:  c.Bool("variables"),
-	}
+		Pager:      c.Bool("pager") && !c.Bool("no-pager"),
+		Format:     c.String("format"),
+		Parse:      c.String("parse"),
+		Extract:    c.String("extract"),
+		Expect:     c.String("expect"),
+		ExpectNot:  c.String("expect-not"),
+		ZabbixHost: c.String("zabbix-host"),
+		Yes:        c.Bool("yes"),
+		Locale:     c.String("locale"),
+		Timestamp:  c.String("timestamp"),
+		Time:       c.Bool("time"),
+		BindAddr:   c.String("bind-addr"),
 
-	if ses.Address != "" && ses.Password != "" {
-		return &ses, nil
+		AllowPlaintext: c.Bool("insecure"),
 	}
 
-	cfg, err := config.NewConfig(c.String("config"))
-	if err != nil {
-		return &ses, fmt.Errorf("config: %w", err)
+	// Password of the environment variable takes precedence over the flag,
+	// so it is not required to be typed in the command line.
+	if password := os.Getenv(EnvPassword); password != "" {
+		ses.Password = password
 	}
 
-	env := c.String("env")
-	if env == "" {
-		env = config.DefaultConfigEnv
-	}
+	return ses
+}
+
+// mergeSession fills the session with fields of the config environment env
+// which are not set by flags, then applies the game preset and the default
+// protocol. Secrets are not resolved.
+func mergeSession(c *cli.Context, ses *config.Session, envs config.Config, env string) {
+	src := envs[env]
+	ses.Env = env
 
 	// Get variables from config environment if flags are not defined.
 	if ses.Address == "" {
-		ses.Address = (*cfg)[env].Address
+		ses.Address = src.Address
 	}
 
 	if ses.Password == "" {
-		ses.Password = (*cfg)[env].Password
+		ses.Password = src.Password
 	}
 
 	if ses.Log == "" {
-		ses.Log = (*cfg)[env].Log
+		ses.Log = src.Log
 	}
 
-	if ses.Type == "" {
-		ses.Type = (*cfg)[env].Type
+	if !c.IsSet("type") {
+		// Protocol of the environment or its game takes precedence over the
+		// default value of the flag.
+		ses.Type = src.Type
 	}
 
-	return &ses, nil
+	if !ses.Pager && !c.Bool("no-pager") {
+		ses.Pager = src.Pager
+	}
+
+	if ses.Format == "" {
+		ses.Format = src.Format
+	}
+
+	if ses.Parse == "" {
+		ses.Parse = src.Parse
+	}
+
+	if ses.Locale == "" {
+		ses.Locale = src.Locale
+	}
+
+	if ses.Timestamp == "" {
+		ses.Timestamp = src.Timestamp
+	}
+
+	if ses.BindAddr == "" {
+		ses.BindAddr = src.BindAddr
+	}
+
+	ses.SayCommand = src.SayCommand
+	ses.LogStripColors = src.LogStripColors
+	ses.Restart = src.Restart
+	ses.AllowedCommands = src.AllowedCommands
+	ses.DeniedCommands = src.DeniedCommands
+	ses.ConfirmCommands = src.ConfirmCommands
+	ses.OnConnect = src.OnConnect
+	ses.Game = src.Game
+	ses.PacketID = src.PacketID
+	ses.AcceptZeroID = src.AcceptZeroID
+	ses.ReauthEveryCommand = src.ReauthEveryCommand
+	ses.Prompt = src.Prompt
+	ses.Login = src.Login
+	ses.TLS = src.TLS
+	ses.TLSFingerprint = src.TLSFingerprint
+	ses.TLSCert = src.TLSCert
+	ses.TLSKey = src.TLSKey
+	ses.HTTPProxy = src.HTTPProxy
+	ses.Compression = src.Compression
+	ses.PingInterval = src.PingInterval
+	ses.PongTimeout = src.PongTimeout
+	ses.CommandsFromHelp = src.CommandsFromHelp
+	ses.HelpCommand = src.HelpCommand
+	ses.ValidateCommands = src.ValidateCommands
+	ses.PlayersCommand = src.PlayersCommand
+	ses.PlayersPattern = src.PlayersPattern
+	ses.Highlight = src.Highlight
+
+	if ses.ZabbixHost == "" {
+		ses.ZabbixHost = src.ZabbixHost
+	}
+
+	if !ses.AllowPlaintext {
+		ses.AllowPlaintext = src.AllowPlaintext
+	}
+
+	ses.Headers = src.Headers
+
+	applyGame(ses)
+
+	if ses.Type == "" {
+		ses.Type = config.DefaultProtocol
+	}
 }
 
 // Dial sends auth request for remote server. Returns en error if
 // address or password is incorrect.
 func (executor *Executor) Dial(ses *config.Session) error {
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
 	var err error
 
 	if executor.client == nil {
-		switch ses.Type {
-		case config.ProtocolTELNET:
-			executor.client, err = telnet.Dial(ses.Address, ses.Password, telnet.SetDialTimeout(ses.Timeout))
-		case config.ProtocolWebRCON:
-			executor.client, err = websocket.Dial(
-				ses.Address, ses.Password, websocket.SetDialTimeout(ses.Timeout), websocket.SetDeadline(ses.Timeout))
-		default:
-			executor.client, err = rcon.Dial(
-				ses.Address, ses.Password, rcon.SetDialTimeout(ses.Timeout), rcon.SetDeadline(ses.Timeout))
+		if err = checkPlaintext(ses); err != nil {
+			return err
+		}
+
+		if err = executor.detect(ses); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+
+		var d *net.Dialer
+		if d, err = dialer(ses); err != nil {
+			return err
+		}
+
+		var address string
+		if address, err = executor.address(ses, d); err != nil {
+			return err
+		}
+
+		var custom client.Dialer
+		if ses.BindAddr != "" && executor.tracer == nil {
+			// Trace relay connects to the server with the dialer itself.
+			custom = d
+		}
+
+		var timed *timedDialer
+		if ses.Time || executor.otel != nil {
+			if custom == nil {
+				custom = &net.Dialer{Timeout: ses.Timeout}
+			}
+
+			timed = &timedDialer{Dialer: custom}
+			custom = timed
+		}
+
+		opts := []client.Option{client.WithTimeout(ses.Timeout)}
+		if custom != nil {
+			opts = append(opts, client.WithDialer(custom))
+		}
+
+		if fragmented(ses) {
+			opts = append(opts, client.WithCollect(client.DefaultCollectWait))
+		}
+
+		if ses.PacketID != "" {
+			opts = append(opts, client.WithPacketID(ses.PacketID))
+		}
+
+		if ses.AcceptZeroID {
+			opts = append(opts, client.WithAcceptZeroID())
+		}
+
+		if ses.ReauthEveryCommand {
+			opts = append(opts, client.WithReauth())
+		}
+
+		var protocolOpts []client.Option
+		if protocolOpts, err = telnetOptions(ses); err != nil {
+			return err
 		}
+
+		opts = append(opts, protocolOpts...)
+
+		if protocolOpts, err = webOptions(ses); err != nil {
+			return err
+		}
+
+		opts = append(opts, protocolOpts...)
+
+		var conn client.Conn
+		if conn, err = client.New(ses.Type, address, opts...); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+
+		start := time.Now()
+
+		if err = conn.Auth(context.Background(), ses.Password); err == nil {
+			executor.client = conn
+		}
+
+		dial := timed.duration()
+
+		if ses.Time && err == nil {
+			auth := max(time.Since(start)-dial, 0)
+			executor.timing = &output.Timing{Dial: output.Milliseconds(dial), Auth: output.Milliseconds(auth)}
+		}
+
+		executor.traceDial(ses, start, dial, err)
 	}
 
 	if err != nil {
-		executor.client = nil
-
 		return fmt.Errorf("auth: %w", err)
 	}
 
@@ -156,31 +530,42 @@ func (executor *Executor) Execute(w io.Writer, ses *config.Session, commands ...
 		return ErrCommandEmpty
 	}
 
-	// TODO: Check keep alive connection to web rcon.
-	if ses.Type == config.ProtocolWebRCON {
-		defer func() {
-			if executor.client != nil {
-				_ = executor.client.Close()
-				executor.client = nil
-			}
-		}()
+	if output.IsStructured(ses.Format) {
+		if _, err := executor.writer(w, ses); err != nil {
+			return err
+		}
 	}
 
 	if err := executor.Dial(ses); err != nil {
+		executor.summarize(ses, commands[0], nil, 0, err)
+
 		return fmt.Errorf("execute: %w", err)
 	}
 
+	var errs []error
+
 	for i, command := range commands {
-		if err := executor.execute(w, ses, command); err != nil {
+		executor.printHeader(w, ses, command)
+		executor.progress.Current(ses.Address + ": " + command)
+
+		start := time.Now()
+		rec, err := executor.execute(w, ses, command)
+		executor.summarize(ses, command, rec, time.Since(start), err)
+
+		if err != nil && !executor.keepGoing {
 			return err
 		}
 
-		if i+1 != len(commands) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", command, err))
+		}
+
+		if i+1 != len(commands) && !output.IsStructured(ses.Format) && executor.header == "" {
 			_, _ = fmt.Fprintln(w, CommandsResponseSeparator)
 		}
 	}
 
-	return nil
+	return failures(errs, len(commands), "commands")
 }
 
 // Interactive reads stdin, parses commands, executes them on remote server
@@ -192,8 +577,12 @@ func (executor *Executor) Interactive(r io.Reader, w io.Writer, ses *config.Sess
 	}
 
 	if ses.Password == "" {
-		_, _ = fmt.Fprint(w, "Enter password: ")
-		_, _ = fmt.Fscanln(r, &ses.Password)
+		ses.Password = executor.askPassword(w, ses.Address, func() string {
+			var password string
+			_, _ = fmt.Fscanln(r, &password)
+
+			return password
+		})
 	}
 
 	if ses.Type == "" {
@@ -201,30 +590,74 @@ func (executor *Executor) Interactive(r io.Reader, w io.Writer, ses *config.Sess
 		_, _ = fmt.Fscanln(r, &ses.Type)
 	}
 
+	if err := checkPlaintext(ses); err != nil {
+		return err
+	}
+
+	if err := executor.detect(ses); err != nil {
+		return err
+	}
+
 	switch ses.Type {
 	case config.ProtocolTELNET:
-		return telnet.DialInteractive(r, w, ses.Address, ses.Password)
+		// Telnet interactive mode sends input to the server directly, so it
+		// is used only if commands are not restricted and do not require
+		// confirmation. It can not bind the local address, wait for the prompt
+		// and pass custom login either.
+		if len(ses.AllowedCommands) == 0 && len(ses.DeniedCommands) == 0 && len(ses.ConfirmCommands) == 0 &&
+			ses.BindAddr == "" && ses.Prompt == "" && len(ses.Login) == 0 {
+			address, err := executor.address(ses, &net.Dialer{Timeout: ses.Timeout})
+			if err != nil {
+				return err
+			}
+
+			if len(ses.OnConnect) != 0 {
+				r = io.MultiReader(strings.NewReader(strings.Join(ses.OnConnect, "\n")+"\n"), r)
+			}
+
+			return telnet.DialInteractive(r, w, address, ses.Password)
+		}
+
+		fallthrough
 	case "", config.ProtocolRCON, config.ProtocolWebRCON:
 		if err := executor.Dial(ses); err != nil {
 			return err
 		}
 
-		_, _ = fmt.Fprintf(w, "Waiting commands for %s (or type %s to exit)\n> ", ses.Address, CommandQuit)
+		_, _ = fmt.Fprintf(w, "Waiting commands for %s (or type %s to exit)\n", ses.Address, CommandQuit)
+		executor.learnCommands(ses)
+		executor.onConnect(w, ses)
+		_, _ = fmt.Fprint(w, executor.prompt())
 
-		scanner := bufio.NewScanner(r)
-		for scanner.Scan() {
-			command := scanner.Text()
+		executor.scanner = bufio.NewScanner(r)
+		for executor.scanner.Scan() {
+			command := executor.scanner.Text()
 			if command != "" {
 				if command == CommandQuit {
 					break
 				}
 
+				if ok, err := executor.meta(w, ses, command); ok {
+					if err != nil {
+						_, _ = fmt.Fprintln(w, redact.Error(err))
+					}
+
+					_, _ = fmt.Fprint(w, executor.prompt())
+
+					continue
+				}
+
 				if err := executor.Execute(w, ses, command); err != nil {
-					return err
+					if !errors.Is(err, ErrCommandCancelled) && !errors.Is(err, alias.ErrUnknownAlias) &&
+						!errors.Is(err, alias.ErrMissingArgument) && !errors.Is(err, ErrUnknownCommand) {
+						return err
+					}
+
+					_, _ = fmt.Fprintln(w, redact.Error(err))
 				}
 			}
 
-			_, _ = fmt.Fprint(w, "> ")
+			_, _ = fmt.Fprint(w, executor.prompt())
 		}
 	default:
 		_, _ = fmt.Fprintf(w, "Unsupported protocol type (%q). Allowed %q, %q and %q protocols\n",
@@ -234,10 +667,28 @@ func (executor *Executor) Interactive(r io.Reader, w io.Writer, ses *config.Sess
 	return nil
 }
 
+// onConnect executes startup commands of the environment. Errors are printed
+// and do not end the interactive session.
+func (executor *Executor) onConnect(w io.Writer, ses *config.Session) {
+	for _, command := range ses.OnConnect {
+		_, _ = fmt.Fprintln(w, executor.prompt()+command)
+
+		if err := executor.Execute(w, ses, command); err != nil {
+			_, _ = fmt.Fprintln(w, redact.Error(err))
+		}
+	}
+}
+
 // Close closes connection to remote server.
 func (executor *Executor) Close() error {
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
 	if executor.client != nil {
-		return executor.client.Close()
+		err := executor.client.Close()
+		executor.client = nil
+
+		return err
 	}
 
 	return nil
@@ -256,6 +707,28 @@ func (executor *Executor) init() {
 	app.Copyright = "Copyright (c) 2022 Pavel Korotkiy (outdead)"
 	app.HideHelpCommand = true
 	app.Flags = executor.getFlags()
+	app.Commands = []*cli.Command{
+		executor.historyCommand(),
+		executor.tailCommand(),
+		executor.chatCommand(),
+		executor.findPlayerCommand(),
+		executor.scriptCommand(),
+		executor.sayCommand(),
+		executor.restartCommand(),
+		executor.discoverCommand(),
+		executor.benchCommand(),
+		executor.stressCommand(),
+		executor.fuzzCommand(),
+		executor.testCommand(),
+		executor.checkCommand(),
+		executor.proxyCommand(),
+		executor.mqttCommand(),
+		executor.natsCommand(),
+		executor.webhookCommand(),
+		executor.agentCommand(),
+		executor.schedulerCommand(),
+		executor.configCommand(),
+	}
 	app.Action = executor.action
 
 	executor.app = app
@@ -272,7 +745,7 @@ func (executor *Executor) getFlags() []cli.Flag {
 		&cli.StringFlag{
 			Name:    "password",
 			Aliases: []string{"p"},
-			Usage:   "Set password to remote server",
+			Usage:   "Set password to remote server. " + EnvPassword + " variable takes precedence over the flag",
 		},
 		&cli.StringFlag{
 			Name:    "type",
@@ -308,17 +781,168 @@ func (executor *Executor) getFlags() []cli.Flag {
 			Usage:   "Set dial and execute timeout",
 			Value:   config.DefaultTimeout,
 		},
+		&cli.StringFlag{
+			Name:  "bind-addr",
+			Usage: "Local IP address which connections to the server originate from",
+		},
+		&cli.BoolFlag{
+			Name:  "wait",
+			Usage: "Wait until the server becomes reachable before executing commands",
+		},
+		&cli.DurationFlag{
+			Name:  "wait-timeout",
+			Usage: "Maximum time to wait for the server",
+			Value: DefaultWaitTimeout,
+		},
 		&cli.BoolFlag{
 			Name:    "variables",
 			Aliases: []string{"V"},
 			Usage:   "Print stored variables and exit",
 			Value:   false,
 		},
+		&cli.BoolFlag{
+			Name:  "which-config",
+			Usage: "Print path of the config file which is loaded and exit",
+		},
+		&cli.BoolFlag{
+			Name:  "pager",
+			Usage: "Show responses which do not fit the terminal with $PAGER program",
+		},
+		&cli.BoolFlag{
+			Name:  "no-pager",
+			Usage: "Do not use pager even if it is enabled in the config",
+		},
+		&cli.BoolFlag{
+			Name:  "no-cache",
+			Usage: "Do not keep passwords entered in interactive mode in memory for switching environments",
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Aliases:     []string{"f"},
+			Usage:       "Set output format: text, csv, json or yaml",
+			DefaultText: output.FormatText,
+		},
+		&cli.StringFlag{
+			Name:  "parse",
+			Usage: "Parse responses with named parser from the config for csv, json and yaml formats",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tag",
+			Usage: "Execute commands on each environment which has the tag. Can be set several times",
+		},
+		&cli.BoolFlag{
+			Name:  "parallel",
+			Usage: "Execute commands on group or tagged environments concurrently",
+		},
+		&cli.BoolFlag{
+			Name:  "ordered",
+			Usage: "Buffer output of each server in parallel mode and print it in the order of environments",
+		},
+		&cli.StringFlag{
+			Name:  "delimiter",
+			Usage: "Split each command argument by the delimiter to separate commands. Example ';'",
+		},
+		&cli.StringFlag{
+			Name:  "header",
+			Usage: "Print header before each response instead of separator. Example '== command {n}: {command} =='",
+		},
+		&cli.BoolFlag{
+			Name:  "progress",
+			Usage: "Show progress of batch and fan-out runs on stderr if it is a terminal",
+		},
+		&cli.BoolFlag{
+			Name:  "keep-going",
+			Usage: "Continue batch and fan-out execution after failed commands and servers, report errors at the end",
+		},
+		&cli.StringFlag{
+			Name:    "file",
+			Aliases: []string{"F"},
+			Usage:   "Execute commands and directives from the batch file",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Do not ask for confirmation of destructive commands",
+		},
+		&cli.BoolFlag{
+			Name:  "insecure",
+			Usage: "Allow to send password of rcon and telnet protocols in plaintext to public address",
+		},
+		&cli.StringFlag{
+			Name:  "extract",
+			Usage: "Extract a field from JSON response by path. Example .Hostname or .Players[0].Name",
+		},
+		&cli.StringFlag{
+			Name:  "expect",
+			Usage: "Fail if the response does not match the regular expression. Example 'map loaded'",
+		},
+		&cli.StringFlag{
+			Name:  "expect-not",
+			Usage: "Fail if the response matches the regular expression. Example '[1-9]\\d* errors'",
+		},
+		&cli.StringFlag{
+			Name:  "tee",
+			Usage: "Write output to the file in addition to stdout",
+		},
+		&cli.StringFlag{
+			Name:  "timestamp",
+			Usage: "Prefix responses with the time in Go layout. Example 15:04:05",
+		},
+		&cli.BoolFlag{
+			Name:  "time",
+			Usage: "Report dial, auth and round-trip durations of requests",
+		},
+		&cli.StringFlag{
+			Name:  "trace",
+			Usage: "Record sent and received packets with hex dumps to the file",
+		},
+		&cli.StringFlag{
+			Name:  "summary",
+			Usage: "Write JSON report of executed commands to the file at the end of the run",
+		},
+		&cli.BoolFlag{
+			Name:  "print-summary",
+			Usage: "Print total, succeeded and failed commands, duration and the slowest server at the end of the run",
+		},
+		&cli.StringFlag{
+			Name:  "push-metrics",
+			Usage: "Push success and duration metrics of the run to Prometheus Pushgateway URL",
+		},
+		&cli.StringFlag{
+			Name:  "statsd",
+			Usage: "Send timing and error counters of commands to StatsD address. Example 127.0.0.1:8125",
+		},
+		&cli.StringFlag{
+			Name:  "statsd-prefix",
+			Usage: "Prefix of StatsD metric names",
+			Value: statsd.DefaultPrefix,
+		},
+		&cli.BoolFlag{
+			Name:  "statsd-tags",
+			Usage: "Send address and command tags in DogStatsD format",
+		},
+		&cli.StringFlag{
+			Name:  "zabbix",
+			Usage: "Send latency, players and parsed numbers to Zabbix server after the run. Example 127.0.0.1:10051",
+		},
+		&cli.StringFlag{
+			Name:  "zabbix-host",
+			Usage: "Host name of trapper items in Zabbix. Name of the environment or host of the server by default",
+		},
+		&cli.StringFlag{
+			Name:    "otlp-endpoint",
+			Usage:   "Export spans of dial, auth and commands to OpenTelemetry collector. Example http://127.0.0.1:4318",
+			EnvVars: []string{"OTEL_EXPORTER_OTLP_ENDPOINT"},
+		},
 	}
 }
 
 // action executes when no subcommands are specified.
-func (executor *Executor) action(c *cli.Context) error {
+func (executor *Executor) action(c *cli.Context) (err error) {
+	if c.Bool("which-config") {
+		return executor.printConfigPath(c.String("config"))
+	}
+
 	ses, err := executor.NewSession(c)
 	if err != nil {
 		return err
@@ -330,8 +954,45 @@ func (executor *Executor) action(c *cli.Context) error {
 		return nil
 	}
 
-	commands := c.Args().Slice()
-	if len(commands) == 0 {
+	restore, err := executor.setup(c)
+	if err != nil {
+		return err
+	}
+	defer func() { restore(err) }()
+
+	executor.header, executor.headers = c.String("header"), 0
+	executor.keepGoing = c.Bool("keep-going")
+	commands := splitCommands(c.Args().Slice(), c.String("delimiter"))
+	file := c.String("file")
+
+	envs, ok, err := executor.targets(c)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if len(commands) == 0 && file == "" {
+			return ErrGroupInteractive
+		}
+
+		return executor.fanOut(c, envs, func(worker *Executor, ses *config.Session) error {
+			return worker.run(c, ses, commands, file)
+		})
+	}
+
+	if len(commands) == 0 && file == "" {
+		if !c.Bool("no-cache") {
+			if executor.credentials, err = credcache.New(); err != nil {
+				return err
+			}
+
+			executor.agent = agent.FromEnv()
+		}
+
+		executor.sessions = func(env string) (*config.Session, error) {
+			return executor.newSession(c, env)
+		}
+
 		return executor.Interactive(executor.r, executor.w, ses)
 	}
 
@@ -343,44 +1004,463 @@ func (executor *Executor) action(c *cli.Context) error {
 		return ErrEmptyPassword
 	}
 
+	return executor.run(c, ses, commands, file)
+}
+
+// setup enables output to --tee file, packet recording to --trace file,
+// metrics to --statsd server, spans to --otlp-endpoint collector and report
+// to --summary file, --push-metrics Pushgateway, --print-summary output and
+// values of items to --zabbix server.
+// Returned function receives the result of the run and restores the
+// executor.
+func (executor *Executor) setup(c *cli.Context) (func(err error), error) {
+	restoreTee, err := executor.tee(c.String("tee"))
+	if err != nil {
+		return nil, err
+	}
+
+	restoreTrace, err := executor.traceTo(c.String("trace"))
+	if err != nil {
+		restoreTee()
+
+		return nil, err
+	}
+
+	restoreStatsd, err := executor.statsdTo(c.String("statsd"), c.String("statsd-prefix"), c.Bool("statsd-tags"))
+	if err != nil {
+		restoreTrace()
+		restoreTee()
+
+		return nil, err
+	}
+
+	restoreSummary := executor.summaryTo(c)
+	restoreSpans := executor.spansTo(c.String("otlp-endpoint"), c.Duration("timeout"))
+	restoreZabbix := executor.zabbixTo(c.String("zabbix"), c.Duration("timeout"))
+
+	return func(err error) {
+		restoreZabbix()
+		restoreSpans(err)
+		restoreSummary(err)
+		restoreStatsd()
+		restoreTrace()
+		restoreTee()
+	}, nil
+}
+
+// run executes commands or batch file in single mode.
+func (executor *Executor) run(c *cli.Context, ses *config.Session, commands []string, file string) error {
+	if c.Bool("wait") {
+		if err := executor.waitUp(ses, c.Duration("wait-timeout")); err != nil {
+			return err
+		}
+	}
+
+	if file != "" {
+		if len(commands) != 0 {
+			return ErrFileWithCommands
+		}
+
+		return executor.batch(c, ses, file)
+	}
+
 	return executor.Execute(executor.w, ses, commands...)
 }
 
 // execute sends command to Execute to the remote server and prints the response.
-func (executor *Executor) execute(w io.Writer, ses *config.Session, command string) error {
+func (executor *Executor) execute(w io.Writer, ses *config.Session, command string) (*output.Record, error) {
+	rec, err := executor.roundTrip(w, ses, command)
+	if rec == nil {
+		return nil, err
+	}
+
+	if rec.Error != "" {
+		if !ses.SkipErrors {
+			return nil, err
+		}
+
+		executor.print(w, ses, rec)
+
+		return rec, nil
+	}
+
+	if err != nil {
+		if !ses.SkipErrors {
+			if rec.Response != "" {
+				executor.print(w, ses, rec)
+			}
+
+			return rec, fmt.Errorf("execute: %w", client.Classify(err))
+		}
+
+		rec.Error = fmt.Errorf("execute: %w", err).Error()
+	}
+
+	if ses.Extract != "" && rec.Error == "" {
+		var value string
+		if value, err = extract.Extract(rec.Response, ses.Extract); err != nil {
+			return rec, fmt.Errorf("extract: %w", err)
+		}
+
+		rec.Response = value
+	}
+
+	if ses.Parse != "" {
+		if err = executor.parse(ses.Parse, rec); err != nil {
+			return rec, err
+		}
+	}
+
+	executor.print(w, ses, rec)
+	executor.last = rec.Response
+
+	if rec.Error == "" && !output.IsStructured(ses.Format) {
+		executor.suggest(w, ses, rec.Command, rec.Response)
+	}
+
+	executor.complete(w, ses, rec)
+
+	return rec, assert(ses, rec.Response)
+}
+
+// roundTrip sends command to the remote server of the session. It is the
+// pipeline shared by printed commands and requests: aliases are expanded,
+// then before send hooks, commands policy, confirmation and pre-send programs
+// are applied. The response is sanitized and passed to after response hooks.
+// Record is nil if the command is not sent, the command rejected by policy
+// is returned with the error in the record.
+func (executor *Executor) roundTrip(w io.Writer, ses *config.Session, command string) (*output.Record, error) {
 	if command == "" {
-		return ErrCommandEmpty
+		return nil, ErrCommandEmpty
 	}
 
-	var result string
-	var err error
+	command, err := executor.aliases.Expand(command)
+	if err != nil {
+		return nil, err
+	}
+
+	if command, err = executor.hooks.BeforeSend(command); err != nil {
+		if errors.Is(err, hook.ErrCancelled) {
+			return nil, fmt.Errorf("%w: %s", ErrCommandCancelled, command)
+		}
+
+		return nil, err
+	}
+
+	if err = executor.check(ses, command); err != nil {
+		return &output.Record{Address: ses.Address, Command: command, Error: err.Error()}, err
+	}
+
+	if !executor.confirm(w, ses, command) {
+		return nil, fmt.Errorf("%w: %s", ErrCommandCancelled, command)
+	}
+
+	if err = executor.exec.Pre(ses.Address, command); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCommandCancelled, err)
+	}
+
+	if err = executor.Dial(ses); err != nil {
+		return nil, fmt.Errorf("execute: %w", err)
+	}
+
+	start := time.Now()
+	result, err := executor.sendParts(ses, command)
+	rec := output.Record{Address: ses.Address, Command: command, Response: text.Sanitize(strings.TrimSpace(result))}
+
+	if ses.Time {
+		rec.Timing = executor.takeTiming(time.Since(start))
+	}
+
+	if err == nil {
+		rec.Response, err = executor.hooks.AfterResponse(command, rec.Response)
+	} else if hookErr := executor.hooks.OnError(command, err); hookErr != nil {
+		_, _ = fmt.Fprintln(w, redact.Error(hookErr))
+	}
+
+	return &rec, err
+}
 
-	result, err = executor.client.Execute(command)
-	if result != "" {
-		result = strings.TrimSpace(result)
-		_, _ = fmt.Fprintln(w, result)
+// complete logs the response of the executed command, alerts on it and runs
+// post-send programs.
+func (executor *Executor) complete(w io.Writer, ses *config.Session, rec *output.Record) {
+	opts := []logger.Option{
+		logger.StripColors(ses.LogStripColors),
+		logger.WithDatabases(executor.databases),
+		logger.Retention(ses.LogRetention),
 	}
 
+	if err := logger.Write(ses.Log, ses.Address, rec.Command, rec.Response, opts...); err != nil {
+		_, _ = fmt.Fprintln(w, redact.Error(fmt.Errorf("log: %w", err)))
+	}
+
+	executor.alert(w, ses, rec.Response)
+
+	if err := executor.exec.Post(ses.Address, rec.Command, rec.Response, rec.Error); err != nil {
+		_, _ = fmt.Fprintln(w, redact.Error(err))
+	}
+}
+
+// send sends command to the remote server without printing, logging and
+// hooks. It is used by hooks to trigger extra commands.
+func (executor *Executor) send(command string) (string, error) {
+	conn, err := executor.connection()
 	if err != nil {
-		if ses.SkipErrors {
-			_, _ = fmt.Fprintln(w, fmt.Errorf("execute: %w", err))
-		} else {
-			return fmt.Errorf("execute: %w", err)
+		return "", err
+	}
+
+	result, err := conn.Execute(command)
+	if err != nil {
+		return "", fmt.Errorf("execute: %w", client.Classify(err))
+	}
+
+	return text.Sanitize(strings.TrimSpace(result)), nil
+}
+
+// print writes the command result to w in the session format. Long text
+// responses are shown with pager if it is enabled for the session.
+func (executor *Executor) print(w io.Writer, ses *config.Session, rec *output.Record) {
+	if ses.Timestamp != "" {
+		rec.Time = time.Now().Format(ses.Timestamp)
+	}
+
+	if output.IsStructured(ses.Format) {
+		writer, err := executor.writer(w, ses)
+		if err == nil {
+			err = writer.WriteRecord(rec)
 		}
+
+		if err != nil {
+			_, _ = fmt.Fprintln(w, redact.Error(fmt.Errorf("output: %w", err)))
+		}
+
+		return
+	}
+
+	prefix := ""
+	if rec.Time != "" {
+		prefix = rec.Time + " "
 	}
 
-	if err = logger.Write(ses.Log, ses.Address, command, result); err != nil {
-		_, _ = fmt.Fprintln(w, fmt.Errorf("log: %w", err))
+	if rec.Response != "" {
+		response := executor.highlight(ses, rec.Response)
+
+		if !ses.Pager {
+			_, _ = fmt.Fprintln(w, text.PrefixLines(response, prefix))
+		} else if err := pager.Print(w, text.PrefixLines(response, prefix)); err != nil {
+			_, _ = fmt.Fprintln(w, redact.Error(err))
+		}
+	}
+
+	if rec.Error != "" {
+		_, _ = fmt.Fprintln(w, text.PrefixLines(rec.Error, prefix))
+	}
+
+	if rec.Timing != nil {
+		_, _ = fmt.Fprintln(w, prefix+"time: "+rec.Timing.String())
+	}
+}
+
+// check returns an error if command is forbidden in the session. Each
+// statement of the command which is executed separately by the server is
+// checked.
+func (executor *Executor) check(ses *config.Session, command string) error {
+	if err := executor.validate(ses, command); err != nil {
+		return err
+	}
+
+	if len(ses.AllowedCommands) == 0 && len(ses.DeniedCommands) == 0 {
+		return nil
+	}
+
+	p, err := executor.policy(ses)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements(ses, command) {
+		if err = p.Check(statement); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// connection returns the client of the remote server. Returns
+// ErrNotConnected if the client is closed by the failed concurrent request.
+func (executor *Executor) connection() (client.Conn, error) {
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
+	if executor.client == nil {
+		return nil, ErrNotConnected
+	}
+
+	return executor.client, nil
+}
+
+// swap replaces the client of the remote server and returns the previous
+// one.
+func (executor *Executor) swap(conn client.Conn) client.Conn {
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
+	current := executor.client
+	executor.client = conn
+
+	return current
+}
+
+// policy returns the commands policy of the session which is compiled once
+// for its patterns.
+func (executor *Executor) policy(ses *config.Session) (*policy.Policy, error) {
+	key := fmt.Sprintf("%q %q", ses.AllowedCommands, ses.DeniedCommands)
+
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
+	if p, ok := executor.policies[key]; ok {
+		return p, nil
+	}
+
+	p, err := policy.New(ses.AllowedCommands, ses.DeniedCommands)
+	if err != nil {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+
+	if executor.policies == nil {
+		executor.policies = make(map[string]*policy.Policy)
+	}
+
+	executor.policies[key] = p
+
+	return p, nil
+}
+
+// confirm asks user to confirm sending of the command if any of its
+// statements matches the session confirm list. Returns true if command can
+// be sent.
+func (executor *Executor) confirm(w io.Writer, ses *config.Session, command string) bool {
+	if ses.Yes || len(ses.ConfirmCommands) == 0 || executor.inMaintenance(ses) {
+		return true
+	}
+
+	if !needsConfirm(ses, command) {
+		return true
+	}
+
+	_, _ = fmt.Fprintf(w, "Are you sure you want to send %q to %s? [y/N]: ", command, ses.Address)
+
+	if executor.scanner == nil {
+		if executor.r == nil {
+			return false
+		}
+
+		executor.scanner = bufio.NewScanner(executor.r)
+	}
+
+	if !executor.scanner.Scan() {
+		_, _ = fmt.Fprintln(w)
+
+		return false
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(executor.scanner.Text()))
+
+	return answer == "y" || answer == "yes"
+}
+
+// needsConfirm returns true if any statement of the command matches the
+// session confirm list. Invalid patterns require confirmation.
+func needsConfirm(ses *config.Session, command string) bool {
+	for _, statement := range statements(ses, command) {
+		if ok, err := policy.Match(ses.ConfirmCommands, statement); err != nil || ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parse extracts records from the response with named parser from config.
+func (executor *Executor) parse(name string, rec *output.Record) error {
+	definition, ok := executor.parsers[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownParser, name)
+	}
+
+	p, err := definition.Compile()
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	rec.Fields = p.Fields()
+	rec.Parsed = p.Parse(rec.Response)
+
+	return nil
+}
+
+// outputKey is the destination and the format of the structured writer.
+type outputKey struct {
+	w      io.Writer
+	format string
+}
+
+// writer returns the writer for structured output format. The writer is
+// created once per destination and format so header lines are not repeated
+// between commands.
+func (executor *Executor) writer(w io.Writer, ses *config.Session) (output.Writer, error) {
+	key := outputKey{w: w, format: ses.Format}
+	if writer, ok := executor.outputs[key]; ok {
+		return writer, nil
+	}
+
+	writer, err := output.NewWriter(w, ses.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if executor.outputs == nil {
+		executor.outputs = make(map[outputKey]output.Writer)
+	}
+
+	executor.outputs[key] = writer
+
+	return writer, nil
+}
+
+// printConfigPath prints absolute path of the config file which is found by
+// name.
+func (executor *Executor) printConfigPath(name string) error {
+	name = config.Find(name)
+	if _, err := os.Stat(name); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(executor.w, abs)
+
+	return nil
+}
+
 func (executor *Executor) printVariables(ses *config.Session, c *cli.Context) {
 	_, _ = fmt.Fprint(executor.w, "Got Print Variables param.\n")
-	_ = ses.Print(executor.w)
+
+	// Password is not printed with debug output.
+	masked := *ses
+	if masked.Password != "" {
+		masked.Password = redact.Mask
+	}
+
+	masked.Headers = maskHeaders(masked.Headers)
+
+	_ = masked.Print(executor.w)
 
 	_, _ = fmt.Fprint(executor.w, "\nPrint other variables:\n")
-	_, _ = fmt.Fprintf(executor.w, "Path to config file (if used): %s\n", c.String("config"))
+	_, _ = fmt.Fprintf(executor.w, "Path to config file (if used): %s\n", config.Find(c.String("config")))
 	_, _ = fmt.Fprintf(executor.w, "Cofig environment: %s\n", c.String("env"))
 }
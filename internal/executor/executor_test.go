@@ -1,20 +1,50 @@
 package executor_test
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/agent"
+	"github.com/gorcon/rcon-cli/internal/announce"
+	"github.com/gorcon/rcon-cli/internal/bench"
 	"github.com/gorcon/rcon-cli/internal/config"
 	"github.com/gorcon/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon-cli/internal/extract"
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon-cli/internal/nats"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/gorcon/rcon-cli/internal/policy"
+	"github.com/gorcon/rcon-cli/internal/proxy"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/secret"
+	"github.com/gorcon/rcon-cli/internal/summary"
+	"github.com/gorcon/rcon-cli/internal/tlspin"
+	"github.com/gorcon/rcon-cli/internal/zabbix"
 	"github.com/gorcon/rcon/rcontest"
 	"github.com/gorcon/telnet"
 	"github.com/gorcon/telnet/telnettest"
@@ -55,6 +85,49 @@ map     : Procedural Map
 players : 0 (500 max) (0 queued) (0 joining)
 id name ping connected addr owner violation kicks`
 
+const MockCommandServerInfoResponseTextWebRCON = `{
+  "Hostname": "Rust Server [DOCKER]",
+  "MaxPlayers": 500,
+  "Players": 0
+}`
+
+// createClientCert writes self-signed client certificate and its key to PEM
+// files and returns their paths.
+func createClientCert(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rcon-cli"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+
+	createFile(certFile, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	createFile(keyFile, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+
+	return certFile, keyFile
+}
+
 func handlersWebRCON() http.Handler {
 	server := http.NewServeMux()
 
@@ -70,56 +143,113 @@ func handlersWebRCON() http.Handler {
 
 		defer ws.Close()
 
-		var response websocket.Message
-
-		// Receive message.
-		_, p, err := ws.ReadMessage()
-		if err != nil {
-			if !strings.Contains(err.Error(), "websocket: close 1006 (abnormal closure): unexpected EOF") {
-				log.Printf("read message error: %v\n", err)
+		// Connection is kept open for the next commands.
+		for {
+			if !handleWebRCON(ws) {
+				return
 			}
-			return
 		}
+	})
 
-		var message websocket.Message
-		if err := json.Unmarshal(p, &message); err != nil {
-			// TODO: What Rust responses on read message fail?
-			fmt.Println(string(p))
-			log.Printf("unmarshal message error: %v\n", err)
-			return
+	return server
+}
+
+// handleWebRCON responds to the next message of Web RCON connection. It
+// returns false if the connection is broken.
+func handleWebRCON(ws *gorilla.Conn) bool {
+	var response websocket.Message
+
+	// Receive message.
+	_, p, err := ws.ReadMessage()
+	if err != nil {
+		if !strings.Contains(err.Error(), "websocket: close 1006 (abnormal closure): unexpected EOF") {
+			log.Printf("read message error: %v\n", err)
 		}
+		return false
+	}
 
-		switch message.Message {
-		case "status":
-			response = websocket.Message{
-				Message:    MockCommandStatusResponseTextWebRCON,
-				Identifier: message.Identifier,
-				Type:       "Generic",
-			}
-		case "deadline":
-			time.Sleep(websocket.DefaultDeadline + 1*time.Second)
-			response = websocket.Message{
-				Message:    fmt.Sprintf("sleep for %d secends", websocket.DefaultDeadline+1*time.Second),
-				Identifier: message.Identifier,
-				Type:       "Generic",
-			}
-		default:
-			response = websocket.Message{
-				Message:    fmt.Sprintf("Command '%s' not found", message.Message),
-				Identifier: message.Identifier,
-				Type:       "Warning",
-			}
+	var message websocket.Message
+	if err := json.Unmarshal(p, &message); err != nil {
+		// TODO: What Rust responses on read message fail?
+		fmt.Println(string(p))
+		log.Printf("unmarshal message error: %v\n", err)
+		return false
+	}
+
+	switch message.Message {
+	case "status":
+		response = websocket.Message{
+			Message:    MockCommandStatusResponseTextWebRCON,
+			Identifier: message.Identifier,
+			Type:       "Generic",
+		}
+	case "serverinfo":
+		response = websocket.Message{
+			Message:    MockCommandServerInfoResponseTextWebRCON,
+			Identifier: message.Identifier,
+			Type:       "Generic",
+		}
+	case "deadline":
+		time.Sleep(websocket.DefaultDeadline + 1*time.Second)
+		response = websocket.Message{
+			Message:    fmt.Sprintf("sleep for %d secends", websocket.DefaultDeadline+1*time.Second),
+			Identifier: message.Identifier,
+			Type:       "Generic",
+		}
+	default:
+		response = websocket.Message{
+			Message:    fmt.Sprintf("Command '%s' not found", message.Message),
+			Identifier: message.Identifier,
+			Type:       "Warning",
 		}
+	}
+
+	js, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("marshal response error: %v\n", err)
+		return false
+	}
+
+	if err := ws.WriteMessage(gorilla.TextMessage, js); err != nil {
+		log.Printf("write response error: %v\n", err)
+		return false
+	}
+
+	return true
+}
+
+// handlersWebRCONPipelined returns Web RCON mock which answers each command
+// with delay without waiting for the previous answers, so pipelined commands
+// are answered concurrently.
+func handlersWebRCONPipelined() http.Handler {
+	server := http.NewServeMux()
+
+	var upgrader = gorilla.Upgrader{}
 
-		js, err := json.Marshal(response)
+	server.HandleFunc("/password", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
-			log.Printf("marshal response error: %v\n", err)
 			return
 		}
 
-		if err := ws.WriteMessage(gorilla.TextMessage, js); err != nil {
-			log.Printf("write response error: %v\n", err)
-			return
+		defer ws.Close()
+
+		var write sync.Mutex
+
+		for {
+			var message websocket.Message
+			if err := ws.ReadJSON(&message); err != nil {
+				return
+			}
+
+			go func(identifier int) {
+				time.Sleep(300 * time.Millisecond)
+
+				write.Lock()
+				defer write.Unlock()
+
+				_ = ws.WriteJSON(websocket.Message{Message: "slept", Identifier: identifier, Type: "Generic"})
+			}(message.Identifier)
 		}
 	})
 
@@ -561,7 +691,7 @@ func TestInteractive(t *testing.T) {
 		defer app.Close()
 
 		err := app.Interactive(&r, &w, &config.Session{Address: serverRCON.Addr(), Password: "password"})
-		assert.EqualError(t, err, "execute: command too long")
+		assert.EqualError(t, err, "execute: command too long: 1001 bytes exceed limit of 1000 bytes, send it as several commands")
 	})
 
 	// Test get Interactive commands RCON.
@@ -750,6 +880,2819 @@ func TestNewExecutor(t *testing.T) {
 	})
 }
 
+func TestFormat(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Positive RCON test Execute func with csv output format.
+	t.Run("no error rcon csv", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password", Format: output.FormatCSV}, "help", "unknown")
+		assert.NoError(t, err)
+
+		expected := "address,command,response,error\n" +
+			serverRCON.Addr() + ",help,Can I help you?,\n" +
+			serverRCON.Addr() + ",unknown,unknown command,\n"
+		assert.Equal(t, expected, w.String())
+	})
+
+	// Test unsupported output format.
+	t.Run("unsupported format", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password", Format: "xml"}, "help")
+		assert.ErrorIs(t, err, output.ErrUnsupportedFormat)
+	})
+
+	t.Run("delimiter", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--delimiter=;", "help; unknown;; help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(w.String(), "Can I help you?"))
+		assert.Equal(t, 1, strings.Count(w.String(), "unknown command"))
+		assert.Equal(t, 2, strings.Count(w.String(), executor.CommandsResponseSeparator))
+	})
+
+	t.Run("header", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "help\nunknown\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--header=== command {n}: {command} ==")
+
+		err := app.Run(append(args, "help", "unknown"))
+		assert.NoError(t, err)
+		assert.Equal(t, "== command 1: help ==\nCan I help you?\n== command 2: unknown ==\nunknown command\n", w.String())
+
+		w.Reset()
+
+		err = app.Run(append(args, "-F="+batchFileName))
+		assert.NoError(t, err)
+		assert.Equal(t, "== command 1: help ==\nCan I help you?\n== command 2: unknown ==\nunknown command\n", w.String())
+	})
+}
+
+func TestDetect(t *testing.T) {
+	serverWebRCON := httptest.NewServer(handlersWebRCON())
+	defer serverWebRCON.Close()
+
+	t.Run("auto detect web", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := config.Session{
+			Address: serverWebRCON.Listener.Addr().String(), Password: "password", Type: config.ProtocolAuto,
+			Timeout: 500 * time.Millisecond,
+		}
+		err := app.Execute(&w, &ses, "status")
+		assert.NoError(t, err)
+		assert.Equal(t, config.ProtocolWebRCON, ses.Type)
+
+		result := strings.TrimSuffix(w.String(), "\n")
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON, result)
+	})
+}
+
+func TestSplit(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("split long command", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		command := strings.TrimSuffix(strings.Repeat("help;", 300), ";")
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password"}, command)
+		assert.NoError(t, err)
+		// Test server does not split commands, so each part is unknown.
+		assert.Equal(t, "unknown command\nunknown command\n", w.String())
+
+		err = app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password", Game: "minecraft"}, command)
+		assert.ErrorIs(t, err, client.ErrTooLong)
+		assert.ErrorContains(t, err, "1499 bytes exceed limit of 1000 bytes")
+	})
+}
+
+func TestExtract(t *testing.T) {
+	serverWebRCON := httptest.NewServer(handlersWebRCON())
+	defer serverWebRCON.Close()
+
+	// Positive WEB RCON test Execute func with extracting field from JSON response.
+	t.Run("no error web extract", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverWebRCON.Listener.Addr().String(), Password: "password", Type: config.ProtocolWebRCON, Extract: ".Hostname"}, "serverinfo")
+		assert.NoError(t, err)
+		assert.Equal(t, "Rust Server [DOCKER]\n", w.String())
+	})
+
+	// Test extracting field from not JSON response.
+	t.Run("extract not json", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverWebRCON.Listener.Addr().String(), Password: "password", Type: config.ProtocolWebRCON, Extract: ".Hostname"}, "status")
+		assert.ErrorIs(t, err, extract.ErrNotJSON)
+	})
+}
+
+func TestAssertion(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test assertions of responses.
+	t.Run("expect", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Expect: "help you"}
+		assert.NoError(t, app.Execute(&w, ses, "help"))
+
+		ses.Expect = "map loaded"
+		err := app.Execute(&w, ses, "help")
+		assert.ErrorIs(t, err, executor.ErrAssertionFailed)
+		assert.EqualError(t, err, `assertion failed: response does not match "map loaded"`)
+
+		ses.Expect, ses.ExpectNot = "", "help"
+		assert.ErrorIs(t, app.Execute(&w, ses, "help"), executor.ErrAssertionFailed)
+	})
+}
+
+func TestParser(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test parsing response with parser from config.
+	t.Run("parse response", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\nparsers:\n  words: '(?P<word>[A-Z]\\w*)'"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+		args = append(args, "-f=csv")
+		args = append(args, "--parse=words")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "address,command,word,error\n"+serverRCON.Addr()+",help,Can,\n"+serverRCON.Addr()+",help,I,\n", w.String())
+	})
+
+	// Test unknown parser.
+	t.Run("unknown parser", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+		args = append(args, "--parse=words")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrUnknownParser)
+	})
+}
+
+func TestBatch(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("batch file", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "# comment\n\nhelp\n@repeat 2\nhelp\n@sleep 10ms\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "-F="+batchFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, strings.Count(w.String(), "Can I help you?"))
+		assert.Equal(t, 2, strings.Count(w.String(), executor.CommandsResponseSeparator))
+	})
+
+	t.Run("batch file with captured variables", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "@capture /Can (?P<who>\\w+) (?P<verb>\\w+)/\nhelp\nsay ${who} ${verb}\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password")
+
+		err := app.Run(append(args, "-F="+batchFileName))
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n"+executor.CommandsResponseSeparator+"\nunknown command\n", w.String())
+
+		createFile(batchFileName, "@capture /(?P<steamid>STEAM_\\S+)/\nhelp\nbanid ${steamid}\n")
+
+		err = app.Run(append(args, "-F="+batchFileName))
+		assert.ErrorIs(t, err, executor.ErrNotCaptured)
+
+		createFile(batchFileName, "help\nbanid ${steamid}\n")
+
+		err = app.Run(append(args, "-F="+batchFileName))
+		assert.ErrorIs(t, err, executor.ErrUnknownVariable)
+		assert.EqualError(t, err, "cli: unknown variable on line 2: ${steamid} is not captured")
+	})
+
+	t.Run("batch file with conditions", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "help\n@if /help you/\n@if /nothing/\nquit\n@else\nhelp\n@end\n@else\nquit\n@end\n"+
+			"@if !/help/\nquit\n@end\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "-F="+batchFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n"+executor.CommandsResponseSeparator+"\nCan I help you?\n", w.String())
+	})
+
+	t.Run("progress without terminal", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--progress", "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	t.Run("batch file with invalid directive", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "help\n@unknown\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "-F="+batchFileName)
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrInvalidDirective)
+
+		err = app.Run(append(args, "help"))
+		assert.ErrorIs(t, err, executor.ErrFileWithCommands)
+	})
+}
+
+func TestWait(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("wait for server", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-p=password", "--wait", "--wait-timeout=10ms")
+
+		err := app.Run(append(args, "-a="+serverRCON.Addr(), "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		err = app.Run(append(args, "-a=127.0.0.1:1", "help"))
+		assert.ErrorIs(t, err, executor.ErrWaitTimeout)
+	})
+
+	t.Run("wait timeout", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password")
+		args = append(args, "restart", "--countdown=0s", "--wait", "--wait-timeout=10ms")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrWaitTimeout)
+	})
+}
+
+func TestDiscover(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("discover", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		host, port, _ := strings.Cut(serverRCON.Addr(), ":")
+
+		args := os.Args[0:1]
+		args = append(args, "discover", "--ports="+port, host)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, serverRCON.Addr()+" open\n", w.String())
+
+		err = app.Run(args[:len(args)-1])
+		assert.ErrorIs(t, err, executor.ErrEmptyHost)
+	})
+}
+
+func TestGroup(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("group", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "eu1", serverRCON.Addr(), "password", "", "") + "\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "eu2", serverRCON.Addr(), "password", "", "") +
+			"\n  tags: [minecraft]\ngroups:\n  eu: [eu1, eu2]"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(append(args, "-e=@eu", "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "==> eu1 <==\nCan I help you?\n\n==> eu2 <==\nCan I help you?\n", w.String())
+
+		err = app.Run(append(args, "-e=@us", "help"))
+		assert.ErrorIs(t, err, executor.ErrUnknownGroup)
+
+		err = app.Run(append(args, "-e=@eu"))
+		assert.ErrorIs(t, err, executor.ErrGroupInteractive)
+
+		w.Reset()
+
+		err = app.Run(append(args, "--tag=minecraft", "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "==> eu2 <==\nCan I help you?\n", w.String())
+
+		err = app.Run(append(args, "--tag=minecraft", "--tag=prod", "help"))
+		assert.ErrorIs(t, err, executor.ErrNoTargets)
+
+		w.Reset()
+
+		err = app.Run(append(args, "-e=@eu", "--parallel", "help", "unknown"))
+		assert.NoError(t, err)
+
+		for _, env := range []string{"eu1", "eu2"} {
+			assert.Contains(t, w.String(), env+" | Can I help you?\n"+env+" | "+executor.CommandsResponseSeparator+"\n")
+		}
+
+		w.Reset()
+
+		err = app.Run(append(args, "-e=@eu", "--parallel", "--ordered", "help", "unknown"))
+		assert.NoError(t, err)
+
+		response := "Can I help you?\n" + executor.CommandsResponseSeparator + "\nunknown command\n"
+		assert.Equal(t, "==> eu1 <==\n"+response+"\n==> eu2 <==\n"+response, w.String())
+
+		err = app.Run(append(args, "-e=@eu", "--parallel", "-f=csv", "help"))
+		assert.ErrorIs(t, err, executor.ErrParallelFormat)
+	})
+
+	t.Run("keep going", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "eu1", "127.0.0.1:1", "password", "", "") + "\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "eu2", serverRCON.Addr(), "password", "", "") +
+			"\n  denied_commands: [stop]\ngroups:\n  eu: [eu1, eu2]"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName, "-e=@eu", "-T=100ms")
+
+		err := app.Run(append(args, "stop", "help"))
+		assert.Error(t, err)
+		assert.NotContains(t, w.String(), "Can I help you?")
+
+		w.Reset()
+
+		err = app.Run(append(args, "--keep-going", "stop", "help"))
+		assert.ErrorIs(t, err, executor.ErrKeepGoing)
+		assert.Contains(t, err.Error(), "failed 2 of 2 servers")
+		assert.Contains(t, err.Error(), "eu2: failed 1 of 2 commands:\nstop: ")
+		assert.Contains(t, w.String(), "==> eu2 <==\n"+executor.CommandsResponseSeparator+"\nCan I help you?\n")
+	})
+
+	t.Run("group tracing", func(t *testing.T) {
+		exported := make(chan string, 1)
+
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &bytes.Buffer{}
+			_, _ = body.ReadFrom(r.Body)
+			exported <- body.String()
+		}))
+		defer collector.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "eu1", serverRCON.Addr(), "password", "", "") + "\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "eu2", serverRCON.Addr(), "password", "", "") +
+			"\ngroups:\n  eu: [eu1, eu2]"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		traceFileName := "rcon-test-trace.log"
+		defer os.Remove(traceFileName)
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName, "-e=@eu", "--parallel", "--trace="+traceFileName,
+			"--otlp-endpoint="+collector.URL, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		body, err := os.ReadFile(traceFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(body), serverRCON.Addr()+" closed\n"))
+		assert.Equal(t, 2, strings.Count(<-exported, `"name":"execute"`))
+	})
+}
+
+func TestMetaCommands(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test aliases in Interactive mode.
+	t.Run("aliases", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandAlias + " ask help $*\n")
+		r.WriteString(executor.CommandAlias + "\n")
+		r.WriteString("!ask\n")
+		r.WriteString("!unknown\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Interactive(&r, &w, &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> !ask = help $*\n")
+		assert.Contains(t, w.String(), "> Can I help you?\n")
+		assert.Contains(t, w.String(), "> unknown alias: unknown\n")
+	})
+
+	t.Run("use environment in interactive", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") + "\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "other", serverRCON.Addr(), "password", "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandUse + " other\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandUse + " missing\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> Waiting commands for "+serverRCON.Addr())
+		assert.Contains(t, w.String(), "other> Can I help you?\n")
+		assert.Contains(t, w.String(), "other> "+executor.ErrUnknownProfile.Error()+": missing\nother> ")
+	})
+
+	t.Run("cache password in interactive", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "", "", "") + "\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "other", serverRCON.Addr(), "password", "", "")
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		input := "password\n" + executor.CommandUse + " other\n" + executor.CommandUse + " default\n" +
+			"help\n" + executor.CommandQuit + "\n"
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(bytes.NewBufferString(input), w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(w.String(), "Enter password: "))
+		assert.Contains(t, w.String(), "default> Can I help you?\n")
+
+		w.Reset()
+
+		input = "password\n" + executor.CommandUse + " other\n" + executor.CommandUse + " default\n" +
+			"password\nhelp\n" + executor.CommandQuit + "\n"
+
+		app = executor.NewExecutor(bytes.NewBufferString(input), w, "")
+		defer app.Close()
+
+		err = app.Run(append(args, "--no-cache"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(w.String(), "Enter password: "))
+		assert.Contains(t, w.String(), "default> Can I help you?\n")
+	})
+
+	t.Run("copy in interactive", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandCopy + "\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandCopy + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		err := app.Interactive(r, w, &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> "+executor.ErrNothingToCopy.Error()+"\n")
+		assert.Equal(t, 1, strings.Count(w.String(), executor.ErrNothingToCopy.Error()))
+	})
+
+	t.Run("shell in interactive", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandShell + " echo local\n")
+		r.WriteString(executor.CommandShell + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		err := app.Interactive(r, w, &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> ==> :! echo local <==\nlocal\n==> :! <==\n> ")
+		assert.Contains(t, w.String(), executor.ErrEmptyShellCommand.Error())
+	})
+}
+
+func TestAgent(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("agent", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "agent.sock")
+		t.Setenv(agent.EnvSocket, socket)
+
+		configFileName := "rcon-test-local.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "", "", ""))
+		defer os.Remove(configFileName)
+
+		started := &bytes.Buffer{}
+		done := make(chan error)
+
+		go func() {
+			app := executor.NewExecutor(nil, started, "")
+			done <- app.Run([]string{os.Args[0], "agent", "start"})
+		}()
+
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(socket)
+
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(bytes.NewBufferString("password\n"+executor.CommandQuit+"\n"), w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Enter password: ")
+
+		w.Reset()
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{os.Args[0], "-c=" + configFileName, "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		err = app.Run([]string{os.Args[0], "agent", "stop"})
+		assert.NoError(t, err)
+		assert.NoError(t, <-done)
+		assert.Equal(t, agent.EnvSocket+"="+socket+"; export "+agent.EnvSocket+"\n", started.String())
+	})
+}
+
+func TestSuggestions(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("on connect in interactive", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  on_connect: [help, status]"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> help\nCan I help you?\n> status\nunknown command\n> ")
+	})
+
+	t.Run("game preset in interactive", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  game: ark"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandCommands + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> ListPlayers\nServerChat\n")
+	})
+
+	t.Run("bundled dictionary in interactive", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  game: ark\n  validate_commands: true"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandUsage + " kickplayer\n")
+		r.WriteString(executor.CommandUsage + " help\n")
+		r.WriteString("help\n")
+		r.WriteString("listplayers\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> KickPlayer <steamid>\n")
+		assert.Contains(t, w.String(), "> "+executor.ErrNoUsage.Error()+": help\n")
+		assert.Contains(t, w.String(), "> "+executor.ErrUnknownCommand.Error()+": help\n")
+		assert.Contains(t, w.String(), "> unknown command\n")
+	})
+
+	t.Run("did you mean in interactive", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  game: ark"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString("kickplayr 1\n")
+		r.WriteString("saveworl\n")
+		r.WriteString("restart\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		// Test server answers unknown commands with "unknown command".
+		assert.Contains(t, w.String(), "> unknown command\nDid you mean this?\n\tKickPlayer\n")
+		assert.Contains(t, w.String(), "> unknown command\nDid you mean this?\n\tSaveWorld\n")
+		assert.Contains(t, w.String(), "> unknown command\n> ")
+	})
+
+	t.Run("commands from help", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  commands_from_help: true"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString(executor.CommandCommands + " c\n")
+		r.WriteString(executor.CommandCommands + " kick\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		// Test server answers help with "Can I help you?".
+		assert.Contains(t, w.String(), "> Can\n> > ")
+	})
+}
+
+func TestOutputOptions(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("tee", func(t *testing.T) {
+		teeFileName := "rcon-test-tee.txt"
+		defer os.Remove(teeFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--tee="+teeFileName, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		body, err := os.ReadFile(teeFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", string(body))
+
+		err = app.Run(append(args[:len(args)-2], "--tee="+teeFileName+"/missing/file", "help"))
+		assert.Error(t, err)
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--timestamp=[2006]")
+		year := time.Now().Format("2006")
+
+		err := app.Run(append(args, "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "["+year+"] Can I help you?\n", w.String())
+
+		w.Reset()
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run(append(args, "-f=json", "help"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"time":"[`+year+`]"`)
+	})
+
+	t.Run("time", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--time")
+
+		err := app.Run(append(args, "help", "help"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?\ntime: dial ")
+		assert.Equal(t, 2, strings.Count(w.String(), "round-trip "))
+		assert.Equal(t, 1, strings.Count(w.String(), "dial "))
+
+		w.Reset()
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run(append(args, "-f=json", "help"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"round_trip_ms":`)
+	})
+}
+
+func TestConfigCommand(t *testing.T) {
+	t.Run("which config", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		name := filepath.Join(t.TempDir(), "rcon.yaml")
+		assert.NoError(t, os.WriteFile(name, []byte("default:\n  address: 127.0.0.1:16260\n"), 0o600))
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+name, "--which-config"))
+		assert.NoError(t, err)
+		assert.Equal(t, name+"\n", w.String())
+
+		err = app.Run(append(args, "-c="+name+".missing", "--which-config"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("config show", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		name := filepath.Join(t.TempDir(), "rcon.yaml")
+		body := "prod:\n  address: 10.0.0.1:16260\n  password: secret\nstaging:\n  extends: prod\n  address: 10.0.0.2:16260\n"
+		assert.NoError(t, os.WriteFile(name, []byte(body), 0o600))
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "config", "show", "-c="+name, "-e=staging"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "# "+name+"\nstaging:\n")
+		assert.Contains(t, w.String(), "address: 10.0.0.2:16260\n")
+		assert.Contains(t, w.String(), "password: '"+executor.MaskedPassword+"'\n")
+		assert.Contains(t, w.String(), "type: rcon\n")
+		assert.NotContains(t, w.String(), "secret")
+		assert.NotContains(t, w.String(), "prod:")
+
+		err = app.Run(append(args, "config", "show", "-c="+name, "-e=dev"))
+		assert.ErrorIs(t, err, executor.ErrUnknownEnv)
+
+		w.Reset()
+		assert.NoError(t, os.WriteFile(name, []byte(body+"rust:\n  address: 10.0.0.3:28016\n  game: rust\n"), 0o600))
+
+		err = app.Run(append(args, "config", "show", "-c="+name, "-e=rust"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "type: web\n")
+		assert.Contains(t, w.String(), "say_command: say\n")
+	})
+}
+
+func TestTLS(t *testing.T) {
+	t.Run("tls fingerprint", func(t *testing.T) {
+		serverWSS := httptest.NewTLSServer(handlersWebRCON())
+		defer serverWSS.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		layout := "default:\n  address: %s\n  password: password\n  type: web\n  tls_fingerprint: %s\n"
+		createFile(configFileName, fmt.Sprintf(layout, serverWSS.Listener.Addr(), tlspin.Fingerprint(serverWSS.Certificate().Raw)))
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+configFileName, "status"))
+		assert.NoError(t, err)
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON+"\n", w.String())
+		assert.NoError(t, app.Close())
+
+		createFile(configFileName, fmt.Sprintf(layout, serverWSS.Listener.Addr(), "sha256:"+strings.Repeat("00", 32)))
+
+		err = app.Run(append(args, "-c="+configFileName, "status"))
+		assert.ErrorIs(t, err, tlspin.ErrMismatch)
+	})
+
+	t.Run("tls client certificate", func(t *testing.T) {
+		serverWSS := httptest.NewUnstartedServer(handlersWebRCON())
+		serverWSS.TLS = &tls.Config{MinVersion: tls.VersionTLS12, ClientAuth: tls.RequireAnyClientCert}
+		serverWSS.StartTLS()
+		defer serverWSS.Close()
+
+		certFile, keyFile := createClientCert(t)
+
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		layout := "default:\n  address: %s\n  password: password\n  type: web\n  tls_fingerprint: %s\n"
+		body := fmt.Sprintf(layout, serverWSS.Listener.Addr(), tlspin.Fingerprint(serverWSS.Certificate().Raw))
+		createFile(configFileName, body+"  tls_cert: "+certFile+"\n  tls_key: "+keyFile+"\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+configFileName, "status"))
+		assert.NoError(t, err)
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON+"\n", w.String())
+
+		assert.NoError(t, app.Close())
+		createFile(configFileName, body)
+
+		err = app.Run(append(args, "-c="+configFileName, "status"))
+		assert.Error(t, err)
+	})
+}
+
+func TestWebOptions(t *testing.T) {
+	t.Run("http proxy", func(t *testing.T) {
+		serverWebRCON := httptest.NewServer(handlersWebRCON())
+		defer serverWebRCON.Close()
+
+		var tunneled atomic.Value
+
+		serverProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodConnect {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			tunneled.Store(r.Host)
+
+			upstream, err := net.Dial("tcp", r.Host)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+
+				return
+			}
+
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+			go func() {
+				_, _ = io.Copy(upstream, conn)
+				_ = upstream.Close()
+			}()
+
+			_, _ = io.Copy(conn, upstream)
+			_ = conn.Close()
+		}))
+		defer serverProxy.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		layout := "default:\n  address: %s\n  password: password\n  type: web\n  http_proxy: %s\n"
+		createFile(configFileName, fmt.Sprintf(layout, serverWebRCON.Listener.Addr(), serverProxy.URL))
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+configFileName, "status"))
+		assert.NoError(t, err)
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON+"\n", w.String())
+		assert.Equal(t, serverWebRCON.Listener.Addr().String(), tunneled.Load())
+	})
+
+	t.Run("handshake headers", func(t *testing.T) {
+		handler := handlersWebRCON()
+		serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		}))
+		defer serverWebRCON.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		layout := "default:\n  address: %s\n  password: password\n  type: web\n"
+		body := fmt.Sprintf(layout, serverWebRCON.Listener.Addr())
+		createFile(configFileName, body+"  headers:\n    Authorization: Bearer token\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+configFileName, "status"))
+		assert.NoError(t, err)
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON+"\n", w.String())
+
+		w.Reset()
+
+		err = app.Run(append(args, "config", "show", "-c="+configFileName))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Authorization: '"+executor.MaskedPassword+"'\n")
+		assert.NotContains(t, w.String(), "Bearer")
+
+		assert.NoError(t, app.Close())
+		createFile(configFileName, body)
+
+		err = app.Run(append(args, "-c="+configFileName, "status"))
+		assert.Error(t, err)
+	})
+
+	t.Run("compression", func(t *testing.T) {
+		handler := handlersWebRCON()
+		serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate") {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		}))
+		defer serverWebRCON.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		layout := "default:\n  address: %s\n  password: password\n  type: web\n"
+		body := fmt.Sprintf(layout, serverWebRCON.Listener.Addr())
+		createFile(configFileName, body+"  compression: true\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+configFileName, "status"))
+		assert.NoError(t, err)
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON+"\n", w.String())
+
+		assert.NoError(t, app.Close())
+		createFile(configFileName, body)
+
+		err = app.Run(append(args, "-c="+configFileName, "status"))
+		assert.Error(t, err)
+	})
+}
+
+func TestTelnetLogin(t *testing.T) {
+	t.Run("telnet login", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer listener.Close()
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			r := bufio.NewReader(conn)
+
+			_, _ = conn.Write([]byte("Username: "))
+			if username, _ := r.ReadString('\n'); username != "admin\r\n" {
+				return
+			}
+
+			_, _ = conn.Write([]byte("Password: "))
+			if password, _ := r.ReadString('\n'); password != "password\r\n" {
+				_, _ = conn.Write([]byte("Access denied\r\n"))
+
+				return
+			}
+
+			_, _ = conn.Write([]byte("Welcome, admin\r\n$ "))
+
+			for {
+				command, err := r.ReadString('\n')
+				if err != nil || strings.TrimSpace(command) == "exit" {
+					return
+				}
+
+				_, _ = conn.Write([]byte("2 players online\r\n$ "))
+			}
+		}()
+
+		configFileName := "rcon-test-local.yaml"
+		defer os.Remove(configFileName)
+
+		createFile(configFileName, "default:\n  address: "+listener.Addr().String()+"\n  password: password\n  type: telnet\n"+
+			"  prompt: '^\\$ $'\n  login:\n    - expect: 'Username:'\n      send: admin\n"+
+			"    - expect: 'Password:'\n      password: true\n    - expect: Welcome\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err = app.Run(append(args, "-c="+configFileName, "players"))
+		assert.NoError(t, err)
+		assert.Equal(t, "2 players online\n", w.String())
+	})
+}
+
+func TestCredentials(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("plaintext to public address", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a=203.0.113.1:16260", "-p=password", "-T=50ms")
+
+		err := app.Run(append(args, "help"))
+		assert.ErrorIs(t, err, executor.ErrPlaintextPassword)
+
+		err = app.Run(append(args, "-t=telnet", "help"))
+		assert.ErrorIs(t, err, executor.ErrPlaintextPassword)
+
+		err = app.Run(append(args, "-t=auto", "help"))
+		assert.ErrorIs(t, err, executor.ErrPlaintextPassword)
+
+		err = app.Run(append(args, "--insecure", "help"))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, executor.ErrPlaintextPassword)
+
+		err = app.Run(append(args, "-t=web", "help"))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, executor.ErrPlaintextPassword)
+
+		err = app.Run([]string{"", "-a=rcon.invalid:16260", "-p=password", "-T=50ms", "help"})
+		assert.ErrorIs(t, err, executor.ErrPlaintextPassword)
+	})
+
+	t.Run("password variable", func(t *testing.T) {
+		t.Setenv(executor.EnvPassword, "password")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-a="+serverRCON.Addr(), "-p=wrong", "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		w.Reset()
+
+		err = app.Run(append(args, "-a="+serverRCON.Addr(), "--variables", "help"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"password": "`+redact.Mask+`"`)
+		assert.Equal(t, "dial "+redact.Mask, redact.String("dial password"))
+	})
+
+	t.Run("secret reference", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+
+		w := &bytes.Buffer{}
+
+		name := filepath.Join(t.TempDir(), "rcon.yaml")
+		body := "default:\n  address: " + serverRCON.Addr() + "\n  password: bw://rcon\n"
+		assert.NoError(t, os.WriteFile(name, []byte(body), 0o600))
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+
+		err := app.Run(append(args, "-c="+name, "help"))
+		assert.ErrorIs(t, err, secret.ErrResolve)
+
+		err = app.Run(append(args, "config", "show", "-c="+name))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "password: bw://rcon\n")
+	})
+
+	t.Run("typed auth error", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=wrong", "help")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, client.ErrAuthFailed)
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+}
+
+func TestBindAddr(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("bind addr", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--bind-addr=127.0.0.1")
+
+		err := app.Run(append(args, "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err = app.Run(append(args[:3], "--bind-addr=localhost", "help"))
+		assert.ErrorIs(t, err, executor.ErrInvalidBindAddr)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("bench", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "bench", "-n=3")
+
+		err := app.Run(append(args, "--command=help"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), serverRCON.Addr()+` "help": 3 requests, 0 errors`+"\nmin ")
+
+		err = app.Run(append(args[:len(args)-1], "-n=0"))
+		assert.ErrorIs(t, err, executor.ErrInvalidCount)
+	})
+
+	t.Run("stress", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "stress", "--command=help")
+
+		err := app.Run(append(args, "--connections=3", "--rate=100/s", "--duration=200ms"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), serverRCON.Addr()+` "help": 3 connections, 0 failed`+"\n")
+		assert.Contains(t, w.String(), " requests, 0 errors\nmin ")
+
+		err = app.Run(append(args, "--rate=fast"))
+		assert.ErrorIs(t, err, bench.ErrInvalidRate)
+	})
+
+	t.Run("fuzz", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "fuzz", "--response-timeout=100ms")
+
+		err := app.Run(append(args, "--case=negative id"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "negative id ")
+
+		err = app.Run(append(args, "--case=unknown"))
+		assert.ErrorIs(t, err, executor.ErrUnknownCase)
+
+		err = app.Run(append(args[:1], "-a="+serverRCON.Addr(), "-p=password", "-t=web", "fuzz"))
+		assert.ErrorIs(t, err, executor.ErrFuzzProtocol)
+	})
+}
+
+func TestTrace(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("trace", func(t *testing.T) {
+		traceFileName := "rcon-test-trace.log"
+		defer os.Remove(traceFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--trace="+traceFileName, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		body, err := os.ReadFile(traceFileName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), serverRCON.Addr()+" closed\n")
+		assert.Contains(t, string(body), "> 22 bytes size=18 id=0 type=3\n")
+		assert.Contains(t, string(body), "> 18 bytes size=14 id=")
+
+		pcapngFileName := "rcon-test-trace" + executor.PcapngExt
+		defer os.Remove(pcapngFileName)
+
+		err = app.Run(append(args[:len(args)-2], "--trace="+pcapngFileName, "help"))
+		assert.NoError(t, err)
+
+		body, err = os.ReadFile(pcapngFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x0a, 0x0d, 0x0d, 0x0a}, body[:4])
+	})
+
+	t.Run("otlp", func(t *testing.T) {
+		exported := make(chan string, 1)
+
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &bytes.Buffer{}
+			_, _ = body.ReadFrom(r.Body)
+			exported <- body.String()
+		}))
+		defer collector.Close()
+
+		t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--otlp-endpoint="+collector.URL, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		spans := <-exported
+		assert.Contains(t, spans, `"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+		assert.Contains(t, spans, `"parentSpanId":"00f067aa0ba902b7"`)
+
+		for _, name := range []string{"rcon", "dial", "auth", "execute"} {
+			assert.Contains(t, spans, `"name":"`+name+`"`)
+		}
+	})
+}
+
+func TestUpstreams(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("proxy", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "proxy", "--listen=127.0.0.1:0")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, proxy.ErrEmptyPassword)
+
+		err = app.Run(append(args, "--proxy-password=secret", "--rate=fast"))
+		assert.ErrorIs(t, err, bench.ErrInvalidRate)
+
+		err = app.Run(append(args, "--proxy-password=secret", "--listen-type=ssh"))
+		assert.ErrorIs(t, err, executor.ErrUnsupportedListenType)
+	})
+
+	t.Run("mqtt broker is down", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		broker := "tcp://" + listener.Addr().String()
+		listener.Close()
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "mqtt", "--broker="+broker)
+
+		err = app.Run(args)
+		assert.Error(t, err)
+	})
+
+	t.Run("nats", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		published := make(chan string, 1)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			r := bufio.NewReader(conn)
+			_, _ = conn.Write([]byte("INFO {}\r\n"))
+			_, _ = r.ReadString('\n')
+			_, _ = r.ReadString('\n')
+			_, _ = conn.Write([]byte("PONG\r\n"))
+			_, _ = r.ReadString('\n')
+			_, _ = conn.Write([]byte("MSG rcon.default.exec 1 _INBOX.1 4\r\nhelp\r\n"))
+			_, _ = r.ReadString('\n')
+			response, _ := r.ReadString('\n')
+			published <- response
+			_, _ = conn.Write([]byte("-ERR 'Stale Connection'\r\n"))
+		}()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "nats", "--server=nats://"+listener.Addr().String())
+
+		err = app.Run(args)
+		assert.ErrorIs(t, err, nats.ErrServer)
+		assert.Equal(t, "Can I help you?\r\n", <-published)
+		assert.Contains(t, w.String(), "Executing commands from rcon.default.exec to "+serverRCON.Addr())
+	})
+
+	t.Run("nats concurrent web commands", func(t *testing.T) {
+		serverWebRCON := httptest.NewServer(handlersWebRCONPipelined())
+		defer serverWebRCON.Close()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		elapsed := make(chan time.Duration, 1)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			r := bufio.NewReader(conn)
+			_, _ = conn.Write([]byte("INFO {}\r\n"))
+			_, _ = r.ReadString('\n')
+			_, _ = r.ReadString('\n')
+			_, _ = conn.Write([]byte("PONG\r\n"))
+			_, _ = r.ReadString('\n')
+
+			start := time.Now()
+
+			_, _ = conn.Write([]byte("MSG rcon.default.exec 1 _INBOX.1 5\r\nsleep\r\n" +
+				"MSG rcon.default.exec 1 _INBOX.2 5\r\nsleep\r\n"))
+
+			for i := 0; i < 2; i++ {
+				_, _ = r.ReadString('\n')
+				_, _ = r.ReadString('\n')
+			}
+
+			elapsed <- time.Since(start)
+			_, _ = conn.Write([]byte("-ERR 'Stale Connection'\r\n"))
+		}()
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverWebRCON.Listener.Addr().String(), "-p=password", "-t=web",
+			"nats", "--server=nats://"+listener.Addr().String())
+
+		err = app.Run(args)
+		assert.ErrorIs(t, err, nats.ErrServer)
+		assert.Less(t, <-elapsed, 550*time.Millisecond)
+	})
+
+	t.Run("webhook without actions", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "prod", serverRCON.Addr(), "password", "", ""))
+		defer os.Remove(configFileName)
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "webhook", "-c="+configFileName, "--listen=127.0.0.1:0")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrNoWebhooks)
+	})
+
+	t.Run("scheduler without announcements", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "prod", serverRCON.Addr(), "password", "", ""))
+		defer os.Remove(configFileName)
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "scheduler", "-c="+configFileName)
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrNoAnnouncements)
+	})
+}
+
+func TestSummary(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("summary", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "summary.json")
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--summary="+name, "help", "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		var report summary.Summary
+
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, summary.StatusOK, report.Status)
+		assert.Equal(t, 2, report.Total)
+		assert.Equal(t, "help", report.Commands[1].Command)
+		assert.Equal(t, len("Can I help you?"), report.Commands[1].Bytes)
+
+		app = executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args = os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=wrong", "--summary="+name, "help")
+
+		err = app.Run(args)
+		assert.Error(t, err)
+
+		report = summary.Summary{}
+
+		data, err = os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, summary.StatusError, report.Status)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, summary.StatusError, report.Commands[0].Status)
+
+		w := &bytes.Buffer{}
+
+		app = executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args = os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--summary="+filepath.Join(name, "password"), "help")
+
+		err = app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), redact.Mask)
+		assert.NotContains(t, w.String(), "password")
+	})
+
+	t.Run("print summary", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--print-summary")
+
+		err := app.Run(append(args, "help", "help"))
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?\nSummary: 2 commands, 2 succeeded, 0 failed in ")
+		assert.Contains(t, w.String(), ", slowest server "+serverRCON.Addr()+" (")
+
+		w.Reset()
+
+		err = app.Run(append(args, "-f=json", "help"))
+		assert.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+
+		var overview struct {
+			Summary summary.Overview `json:"summary"`
+		}
+
+		assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &overview))
+		assert.Equal(t, summary.StatusOK, overview.Summary.Status)
+		assert.Equal(t, 1, overview.Summary.Succeeded)
+		assert.Equal(t, serverRCON.Addr(), overview.Summary.Slowest.Address)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("push metrics", func(t *testing.T) {
+		pushed := make(chan string, 1)
+
+		gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &bytes.Buffer{}
+			_, _ = body.ReadFrom(r.Body)
+			pushed <- r.URL.Path + "\n" + body.String()
+		}))
+		defer gateway.Close()
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--push-metrics="+gateway.URL, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		metrics := <-pushed
+		assert.True(t, strings.HasPrefix(metrics, "/metrics/job/rcon\n"))
+		assert.Contains(t, metrics, "rcon_run_success 1\n")
+		assert.Contains(t, metrics, `rcon_commands{address="`+serverRCON.Addr()+`"} 1`)
+	})
+
+	t.Run("statsd", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--statsd="+conn.LocalAddr().String(),
+			"--statsd-tags", "help")
+
+		err = app.Run(args)
+		assert.NoError(t, err)
+
+		buf := make([]byte, 512)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "rcon.command.count:1|c|#address:"+serverRCON.Addr()+",command:help", string(buf[:n]))
+	})
+
+	t.Run("alerts", func(t *testing.T) {
+		notified := make(chan string, 1)
+
+		chat := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &bytes.Buffer{}
+			_, _ = body.ReadFrom(r.Body)
+			notified <- body.String()
+		}))
+		defer chat.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\nalerts:\n  unknown:\n    pattern: unknown command\n    notify: " + chat.URL
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName, "help", "status")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, <-notified, `"content":"[unknown] `+serverRCON.Addr()+`: unknown command"`)
+		assert.Empty(t, notified)
+	})
+
+	t.Run("maintenance", func(t *testing.T) {
+		notified := make(chan string, 1)
+
+		chat := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			notified <- r.URL.Path
+		}))
+		defer chat.Close()
+
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  confirm_commands: [help]" +
+			"\nalerts:\n  help:\n    pattern: help\n    notify: " + chat.URL +
+			"\nmaintenance:\n  default:\n    - cron: \"* * * * *\"\n      duration: 1h"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+		assert.Empty(t, notified)
+	})
+}
+
+func TestHooks(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("js hooks", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		hookFileName := "rcon-test-hook.js"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\nhooks:\n  js: [" + hookFileName + "]"
+		createFile(configFileName, stringBody)
+		createFile(hookFileName, `
+			function beforeSend(command) { return command === "h" ? "help" : command; }
+			function afterResponse(command, response) { return command + ": " + response; }
+		`)
+
+		defer func() {
+			os.Remove(hookFileName)
+			os.Remove(configFileName)
+		}()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+		args = append(args, "h")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "help: Can I help you?\n", w.String())
+	})
+
+	t.Run("exec hooks", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\nhooks:\n  pre: ['sh -c \"test $RCON_COMMAND != kickall\"']\n  post: ['sh -c \"echo $RCON_RESPONSE\"']"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+
+		err := app.Run(append(args, "help"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nCan I help you?\n", w.String())
+
+		err = app.Run(append(args, "kickall"))
+		assert.ErrorIs(t, err, executor.ErrCommandCancelled)
+	})
+}
+
+func TestSay(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("say template", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test-say.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "") +
+			"\n  locale: ru\n  say_command: servermsg" +
+			"\ntemplates:\n  restart:\n    en: Restart at {{.Time}}\n    ru: Рестарт в {{.Time}}"
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "say", "-c="+configFileName)
+
+		err := app.Run(append(args, "--template=restart", "--var=Time=18:00"))
+		assert.NoError(t, err)
+
+		err = app.Run(append(args, "Hello", "world"))
+		assert.NoError(t, err)
+
+		entries, err := logger.Read(logFileName, time.Time{})
+		assert.NoError(t, err)
+
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "servermsg Рестарт в 18:00", entries[0].Request)
+			assert.Equal(t, "servermsg Hello world", entries[1].Request)
+		}
+
+		err = app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrEmptyMessage)
+
+		err = app.Run(append(args, "--template=unknown"))
+		assert.ErrorIs(t, err, announce.ErrUnknownTemplate)
+	})
+}
+
+func TestRestart(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("restart", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test-restart.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "") +
+			"\n  restart:\n    warnings: [10ms, 20ms, 1h]\n    save_command: save\n    shutdown_command: stop"
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "restart", "-c="+configFileName, "--countdown=30ms")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		entries, err := logger.Read(logFileName, time.Time{})
+		assert.NoError(t, err)
+
+		commands := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			commands = append(commands, entry.Request)
+		}
+
+		assert.Equal(t, []string{"say Server restarts in 20ms", "say Server restarts in 10ms", "save", "stop"}, commands)
+	})
+}
+
+func TestScript(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("script run", func(t *testing.T) {
+		scriptFileName := "rcon-test-script.lua"
+		createFile(scriptFileName, `
+			local response, err = execute("", "help")
+			print(response, err)
+			print(execute("", "unknown"))
+		`)
+		defer os.Remove(scriptFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "script", "run", scriptFileName)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\tnil\nunknown command\tnil\n", w.String())
+
+		err = app.Run(args[:len(args)-1])
+		assert.ErrorIs(t, err, executor.ErrEmptyScript)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	logFileName := "rcon-test.log"
+	stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "")
+	createFile(configFileName, stringBody)
+
+	defer func() {
+		os.Remove(logFileName)
+		os.Remove(configFileName)
+	}()
+
+	app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+	err := app.Run([]string{"", "-c=" + configFileName, "help", "unknown"})
+	assert.NoError(t, err)
+	app.Close()
+
+	// Test history with filters.
+	t.Run("grep", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "history", "-c=" + configFileName, "--grep=help", "--since=1d"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "] "+serverRCON.Addr()+": help\nCan I help you?\n\n")
+		assert.NotContains(t, w.String(), "unknown")
+	})
+
+	// Test invalid since duration.
+	t.Run("invalid since", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "history", "-c=" + configFileName, "--since=week"})
+		assert.Error(t, err)
+	})
+
+	// Test empty log.
+	t.Run("empty log", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "history", "-c=" + configFileName, "-e=unknown"})
+		assert.ErrorIs(t, err, executor.ErrEmptyLog)
+	})
+}
+
+func TestTail(t *testing.T) {
+	var connections atomic.Int32
+
+	upgrader := gorilla.Upgrader{}
+	serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		// The first connection requests console history, the next one streams.
+		if connections.Add(1) == 1 {
+			var message websocket.Message
+			if err = ws.ReadJSON(&message); err != nil {
+				return
+			}
+
+			now := time.Now().Unix()
+			history := fmt.Sprintf(`[{"Message":"Bob joined","Time":%d},{"Message":"Eve joined","Time":%d},`+
+				`{"Message":"Eve left","Time":%d}]`, now-3600, now-60, now-30)
+
+			_ = ws.WriteJSON(websocket.Message{Message: history, Identifier: message.Identifier, Type: "Generic"})
+
+			return
+		}
+
+		_ = ws.WriteJSON(websocket.Message{Message: "Alice joined", Identifier: 0, Type: "Generic"})
+		_ = ws.WriteJSON(websocket.Message{Message: "Saved 1234 ents", Identifier: -1, Type: "Generic"})
+		_ = ws.WriteJSON(websocket.Message{Message: "Alice left", Identifier: 0, Type: "Generic"})
+	}))
+	defer serverWebRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	address := strings.TrimPrefix(serverWebRCON.URL, "http://")
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, address, "password", "", "web")+"\n"+
+		fmt.Sprintf(ConfigLayoutYAML, "rcon", "127.0.0.1:16260", "password", "", "rcon"))
+	defer os.Remove(configFileName)
+
+	t.Run("grep and since", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		// The mock drops the stream after the last line.
+		err := app.Run([]string{"", "tail", "-c=" + configFileName, "--grep=joined|left", "--since=10m"})
+		assert.Error(t, err)
+		assert.Equal(t, "Eve joined\nEve left\nAlice joined\nAlice left\n", w.String())
+	})
+
+	t.Run("since unsupported", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "tail", "-c=" + configFileName, "-e=rcon", "--since=10m"})
+		assert.ErrorIs(t, err, executor.ErrSinceUnsupported)
+	})
+
+	t.Run("stream unsupported", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "tail", "-c=" + configFileName, "-e=rcon"})
+		assert.ErrorIs(t, err, client.ErrStreamUnsupported)
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		var streams atomic.Int32
+
+		serverStream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Two streams are lost, the next reconnects are refused.
+			n := streams.Add(1)
+			if n > 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			ws, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer ws.Close()
+
+			_ = ws.WriteJSON(websocket.Message{Message: fmt.Sprintf("Player %d joined", n), Identifier: 0, Type: "Generic"})
+		}))
+		defer serverStream.Close()
+
+		streamAddress := strings.TrimPrefix(serverStream.URL, "http://")
+		streamConfig := "rcon-test-stream.yaml"
+		createFile(streamConfig, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, streamAddress, "password", "", "web"))
+		defer os.Remove(streamConfig)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "tail", "-c=" + streamConfig, "--reconnect=2", "--backoff=1ms"})
+		assert.Error(t, err)
+
+		lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+		if !assert.Len(t, lines, 6) {
+			return
+		}
+
+		assert.Equal(t, "Player 1 joined", lines[0])
+		assert.True(t, strings.HasPrefix(lines[1], "--- connection lost: "))
+		assert.True(t, strings.HasSuffix(lines[1], ", reconnecting in 1ms (1/2)"))
+		assert.Equal(t, "--- reconnected to "+streamAddress, lines[2])
+		assert.Equal(t, "Player 2 joined", lines[3])
+		assert.True(t, strings.HasSuffix(lines[4], ", reconnecting in 1ms (1/2)"))
+		assert.True(t, strings.HasSuffix(lines[5], ", reconnecting in 2ms (2/2)"))
+		assert.Equal(t, int32(4), streams.Load())
+	})
+
+	t.Run("reconnect disabled", func(t *testing.T) {
+		serverStream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ws, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+
+			_ = ws.WriteJSON(websocket.Message{Message: "Player joined", Identifier: 0, Type: "Generic"})
+			_ = ws.Close()
+		}))
+		defer serverStream.Close()
+
+		streamConfig := "rcon-test-stream.yaml"
+		createFile(streamConfig, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv,
+			strings.TrimPrefix(serverStream.URL, "http://"), "password", "", "web"))
+		defer os.Remove(streamConfig)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "tail", "-c=" + streamConfig, "--reconnect=0"})
+		assert.Error(t, err)
+		assert.Equal(t, "Player joined\n", w.String())
+	})
+}
+
+func TestChat(t *testing.T) {
+	var connections atomic.Int32
+
+	said := make(chan string, 1)
+	now := time.Now().Unix()
+
+	upgrader := gorilla.Upgrader{}
+	serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		// The first connection streams chat, the next one sends commands.
+		if connections.Add(1) != 1 {
+			for {
+				var message websocket.Message
+				if err = ws.ReadJSON(&message); err != nil {
+					return
+				}
+
+				said <- message.Message
+
+				_ = ws.WriteJSON(websocket.Message{Identifier: message.Identifier, Type: "Generic"})
+			}
+		}
+
+		_ = ws.WriteJSON(websocket.Message{Message: "Saved 1234 ents", Identifier: -1, Type: "Generic"})
+		_ = ws.WriteJSON(websocket.Message{
+			Message: fmt.Sprintf(`{"Channel":0,"Message":"hi","Username":"Bob","Time":%d}`, now), Type: "Chat",
+		})
+		_ = ws.WriteJSON(websocket.Message{
+			Message: fmt.Sprintf(`{"Channel":1,"Message":"base","Username":"Eve","Time":%d}`, now), Type: "Chat",
+		})
+
+		command := <-said
+		_ = ws.WriteJSON(websocket.Message{
+			Message: fmt.Sprintf(`{"Channel":0,"Message":%q,"Username":"SERVER","Time":%d}`,
+				strings.TrimPrefix(command, "say "), now), Type: "Chat",
+		})
+	}))
+	defer serverWebRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	address := strings.TrimPrefix(serverWebRCON.URL, "http://")
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, address, "password", "", "web")+"\n"+
+		fmt.Sprintf(ConfigLayoutYAML, "rcon", "127.0.0.1:16260", "password", "", "rcon"))
+	defer os.Remove(configFileName)
+
+	t.Run("follow and send", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(strings.NewReader("\nhello everyone\n"), w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "chat", "-c=" + configFileName})
+		assert.NoError(t, err)
+
+		clock := time.Unix(now, 0).Format(time.TimeOnly)
+		assert.Equal(t, "Chat of "+address+" (type message to send or :q to exit)\n"+
+			clock+" Bob: hi\n"+clock+" [team] Eve: base\n"+clock+" SERVER: hello everyone\n", w.String())
+	})
+
+	t.Run("chat unsupported", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "chat", "-c=" + configFileName, "-e=rcon"})
+		assert.ErrorIs(t, err, executor.ErrChatUnsupported)
+	})
+}
+
+func TestFindPlayer(t *testing.T) {
+	upgrader := gorilla.Upgrader{}
+	serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+			var message websocket.Message
+			if err = ws.ReadJSON(&message); err != nil {
+				return
+			}
+
+			response := "Kicked: " + strings.TrimPrefix(message.Message, "kick ")
+			if message.Message == "playerlist" {
+				response = `[{"SteamID": "76561198000000001", "OwnerSteamID": "0", "DisplayName": "Alice"},` +
+					`{"SteamID": "76561198000000002", "OwnerSteamID": "0", "DisplayName": "Griefer"}]`
+			}
+
+			_ = ws.WriteJSON(websocket.Message{Message: response, Identifier: message.Identifier, Type: "Generic"})
+		}
+	}))
+	defer serverWebRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	address := strings.TrimPrefix(serverWebRCON.URL, "http://")
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, address, "password", "", "web")+
+		"\n  game: rust\n"+fmt.Sprintf(ConfigLayoutYAML, "custom", address, "password", "", "web"))
+	defer os.Remove(configFileName)
+
+	t.Run("print player", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "find-player", "-c=" + configFileName, "grefier"})
+		assert.NoError(t, err)
+		assert.Equal(t, "76561198000000002\tGriefer\n", w.String())
+	})
+
+	t.Run("then", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "find-player", "-c=" + configFileName, "--then", "kick {id} {name}", "grief"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Kicked: 76561198000000002 Griefer\n", w.String())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "find-player", "-c=" + configFileName, "--then", "kick {id}", "Bob"})
+		assert.ErrorIs(t, err, player.ErrNotFound)
+	})
+
+	t.Run("unknown player list", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "find-player", "-c=" + configFileName, "-e=custom", "Alice"})
+		assert.ErrorIs(t, err, executor.ErrEmptyPlayers)
+	})
+}
+
+func TestExpect(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		conns []*gorilla.Conn
+	)
+
+	upgrader := gorilla.Upgrader{}
+	serverWebRCON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		mu.Lock()
+		conns = append(conns, ws)
+		mu.Unlock()
+
+		for {
+			var message websocket.Message
+			if err = ws.ReadJSON(&message); err != nil {
+				return
+			}
+
+			mu.Lock()
+			_ = ws.WriteJSON(websocket.Message{Message: "Saving...", Identifier: message.Identifier, Type: "Generic"})
+			mu.Unlock()
+
+			if message.Message != "server.save" {
+				continue
+			}
+
+			// Result of the save is printed to the console of all connections later.
+			time.AfterFunc(50*time.Millisecond, func() {
+				mu.Lock()
+				defer mu.Unlock()
+
+				for _, conn := range conns {
+					_ = conn.WriteJSON(websocket.Message{Message: "Saved 1234 ents", Identifier: 0, Type: "Generic"})
+				}
+			})
+		}
+	}))
+	defer serverWebRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	address := strings.TrimPrefix(serverWebRCON.URL, "http://")
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, address, "password", "", "web"))
+	defer os.Remove(configFileName)
+
+	t.Run("batch", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "@expect /Saved \\d+ ents/ 5s\nserver.save\n@expect /Saving/\nstatus\n")
+		defer os.Remove(batchFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "-F=" + batchFileName})
+		assert.NoError(t, err)
+		assert.Equal(t, "Saving...\n--------\nSaving...\n", w.String())
+	})
+
+	t.Run("batch timeout", func(t *testing.T) {
+		batchFileName := "rcon-test-batch.txt"
+		createFile(batchFileName, "@expect /Restarted/ 200ms\nserver.save\nstatus\n")
+		defer os.Remove(batchFileName)
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName, "-F=" + batchFileName})
+		assert.ErrorIs(t, err, executor.ErrExpectNotReceived)
+	})
+
+	t.Run("script", func(t *testing.T) {
+		scriptFileName := "rcon-test-script.lua"
+		createFile(scriptFileName, `print(expect("", "server.save", "Saved \\d+", 5))`+"\n"+
+			`print(expect("", "status", "Restarted", "200ms"))`)
+		defer os.Remove(scriptFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "script", "run", "-c=" + configFileName, scriptFileName})
+		assert.NoError(t, err)
+		assert.Equal(t, "Saved 1234 ents\tnil\nnil\texpected output is not received: \"Restarted\" in 200ms\n", w.String())
+	})
+}
+
+func TestSuite(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "")+
+		"\n"+fmt.Sprintf(ConfigLayoutYAML, "broken", "", "password", "", ""))
+	defer os.Remove(configFileName)
+
+	suiteFileName := "rcon-test-suite.yaml"
+	junitFileName := "rcon-test-junit.xml"
+
+	defer func() {
+		os.Remove(suiteFileName)
+		os.Remove(junitFileName)
+	}()
+
+	t.Run("passed", func(t *testing.T) {
+		createFile(suiteFileName, "name: smoke\ntests:\n  - name: help\n    command: help\n    contains: help you\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "test", "-c=" + configFileName, "--junit=" + junitFileName, suiteFileName})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "PASS  default: help (")
+		assert.Contains(t, w.String(), "Tests: 1 total, 1 passed, 0 failed in ")
+
+		junit, err := os.ReadFile(junitFileName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(junit), `<testsuite name="default" tests="1" failures="0" errors="0"`)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		createFile(suiteFileName, "envs: [default, broken]\ntests:\n  - command: help\n    not_match: help\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "test", "-c=" + configFileName, suiteFileName})
+		assert.ErrorIs(t, err, executor.ErrSuiteFailed)
+		assert.EqualError(t, err, "cli: test suite failed: 2 of 2 tests")
+		assert.Contains(t, w.String(), "FAIL  default: help: unexpected response: response matches \"help\"\n"+
+			"ERROR broken: help: address is not set: to set address add -a host:port\n")
+	})
+
+	t.Run("empty suite", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "test", "-c=" + configFileName})
+		assert.ErrorIs(t, err, executor.ErrEmptySuite)
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test commands policy.
+	t.Run("denied command", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", AllowedCommands: []string{"help"}}
+
+		err := app.Execute(&w, ses, "help", "unknown")
+		assert.ErrorIs(t, err, policy.ErrCommandDenied)
+		assert.Equal(t, "Can I help you?\n"+executor.CommandsResponseSeparator+"\n", w.String())
+
+		ses = &config.Session{Address: serverRCON.Addr(), Password: "password", DeniedCommands: []string{"quit"}}
+
+		err = app.Execute(&w, ses, "help; QUIT")
+		assert.ErrorIs(t, err, policy.ErrCommandDenied)
+		assert.NoError(t, app.Execute(&w, ses, `help "a; quit"`))
+	})
+
+	// Test confirmation of destructive commands.
+	t.Run("confirm command", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("y\n")
+		r.WriteString("n\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", ConfirmCommands: []string{"help"}}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+
+		err = app.Execute(&w, ses, "help")
+		assert.ErrorIs(t, err, executor.ErrCommandCancelled)
+
+		err = app.Execute(&w, ses, "status; HELP")
+		assert.ErrorIs(t, err, executor.ErrCommandCancelled)
+
+		ses.Yes = true
+		err = app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+	})
+
+	t.Run("statements", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		ses := config.Session{Address: serverRCON.Addr(), Password: "password", AllowedCommands: []string{"help"}}
+
+		err := app.Execute(w, &ses, "help\nunknown")
+		assert.ErrorIs(t, err, policy.ErrCommandDenied)
+
+		err = app.Execute(w, &ses, "help\r\nunknown")
+		assert.ErrorIs(t, err, policy.ErrCommandDenied)
+
+		err = app.Execute(w, &ses, "/help")
+		assert.NoError(t, err)
+
+		ses = config.Session{Address: serverRCON.Addr(), Password: "password", DeniedCommands: []string{"unknown"}}
+
+		w.Reset()
+
+		err = app.Execute(w, &ses, "/unknown")
+		assert.ErrorIs(t, err, policy.ErrCommandDenied)
+		assert.NotContains(t, w.String(), "unknown command")
+	})
+
+	t.Run("use environment", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") +
+			"\n  denied_commands: [unknown]\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "other", serverRCON.Addr(), "password", "", "") +
+			"\n  denied_commands: [help]"
+		createFile(configFileName, stringBody)
+		defer os.Remove(configFileName)
+
+		r := &bytes.Buffer{}
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandUse + " other\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{"", "-c=" + configFileName})
+		assert.ErrorIs(t, err, policy.ErrCommandDenied)
+		assert.Contains(t, w.String(), "> Can I help you?\n")
+		assert.NotContains(t, w.String(), "other> Can I help you?")
+	})
+}
+
+func TestCheck(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	configFileName := "rcon-test-local.yaml"
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "")+
+		"\n  players_command: help\n  players_pattern: '(?P<name>\\w+)'\n"+
+		fmt.Sprintf(ConfigLayoutYAML, "custom", serverRCON.Addr(), "password", "", ""))
+	defer os.Remove(configFileName)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		output string
+	}{
+		{name: "ok", args: []string{"--warn-players=5"}, code: 0, output: "RCON OK - 4 players, response time "},
+		{name: "warning", args: []string{"--warn-players=4", "--crit-players=10"}, code: 1, output: "RCON WARNING - 4 players"},
+		{name: "critical", args: []string{"--crit-players=3"}, code: 2, output: "RCON CRITICAL - 4 players"},
+		{name: "slow", args: []string{"--command=help", "-e=custom", "--crit-time=1ns"}, code: 2, output: "RCON CRITICAL - response time "},
+		{name: "unknown", args: []string{"-e=custom"}, code: 3, output: "RCON UNKNOWN - command is not set"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, w, "")
+			defer app.Close()
+
+			err := app.Run(append([]string{"", "check", "-c=" + configFileName}, test.args...))
+			assert.True(t, strings.HasPrefix(w.String(), test.output), w.String())
+
+			if test.code == 0 {
+				assert.NoError(t, err)
+				assert.Contains(t, w.String(), " | players=4;5;;0 time=")
+
+				return
+			}
+
+			code, reported := executor.ExitCode(err)
+			assert.Equal(t, test.code, code)
+			assert.True(t, reported)
+		})
+	}
+}
+
+func TestZabbix(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			response := "unknown command"
+
+			switch c.Request().Body() {
+			case "players":
+				response = "Players: Alice, Bob"
+			case "stats":
+				response = "fps: 59.8 uptime: 3h"
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, response).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		data, err := zabbix.Decode(conn)
+		if err != nil {
+			return
+		}
+
+		received <- data
+		_, _ = conn.Write(zabbix.Encode([]byte(`{"response":"success","info":"processed: 4; failed: 0; total: 4"}`)))
+	}()
+
+	configFileName := "rcon-test-local.yaml"
+	createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "rust-eu", serverRCON.Addr(), "password", "", "")+
+		"\n  players_command: players\n  players_pattern: '(?:: |, )(?P<name>\\w+)'"+
+		"\nparsers:\n  stats: 'fps: (?P<fps>[\\d.]+) uptime: (?P<uptime>\\w+)'")
+	defer os.Remove(configFileName)
+
+	w := &bytes.Buffer{}
+
+	app := executor.NewExecutor(nil, w, "")
+	defer app.Close()
+
+	err = app.Run([]string{"", "-c=" + configFileName, "-e=rust-eu", "--zabbix=" + listener.Addr().String(),
+		"--parse=stats", "players", "stats"})
+	assert.NoError(t, err)
+
+	var request struct {
+		Data []zabbix.Item `json:"data"`
+	}
+
+	assert.NoError(t, json.Unmarshal(<-received, &request))
+
+	values := make(map[string]string)
+
+	for _, item := range request.Data {
+		assert.Equal(t, "rust-eu", item.Host)
+		values[item.Key] = item.Value
+	}
+
+	assert.Contains(t, values, "rcon.latency[players]")
+	assert.Contains(t, values, "rcon.latency[stats]")
+	assert.Equal(t, "2", values["rcon.players"])
+	assert.Equal(t, "59.8", values["rcon.fps"])
+	assert.NotContains(t, values, "rcon.uptime")
+}
+
 // getVar returns environment variable or default value.
 func getVar(key string, fallback string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// DefaultExpectTimeout is the default time to wait for the expected output
+// of the command.
+const DefaultExpectTimeout = 30 * time.Second
+
+// ErrExpectNotReceived is returned when the expected output of the command
+// is not received in time.
+var ErrExpectNotReceived = errors.New("expected output is not received")
+
+// expect sends the command with send and waits until its response or the
+// console output of the server has the line matching pattern. The console
+// stream is opened before the command is sent, so output printed right
+// after the command is not missed. Only the response is matched for
+// protocols without console stream. It returns the matched line.
+func (executor *Executor) expect(
+	ses *config.Session, pattern *regexp.Regexp, timeout time.Duration, send func() (string, error),
+) (string, error) {
+	if timeout == 0 {
+		timeout = DefaultExpectTimeout
+	}
+
+	if err := checkPlaintext(ses); err != nil {
+		return "", err
+	}
+
+	if err := executor.detect(ses); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stream *client.Stream
+
+	if ses.Type == config.ProtocolTELNET || ses.Type == config.ProtocolWebRCON {
+		opts, err := streamOptions(ses)
+		if err != nil {
+			return "", err
+		}
+
+		if stream, err = client.OpenStream(ctx, ses.Type, ses.Address, ses.Password, opts...); err != nil {
+			return "", fmt.Errorf("expect: %w", err)
+		}
+		defer stream.Close()
+	}
+
+	response, err := send()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(response, "\n") {
+		if pattern.MatchString(line) {
+			return strings.TrimSpace(line), nil
+		}
+	}
+
+	if stream == nil {
+		return "", fmt.Errorf("%w: %q in the response", ErrExpectNotReceived, pattern)
+	}
+
+	lines := stream.Lines()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("%w: %q in %s", ErrExpectNotReceived, pattern, timeout)
+		case line, ok := <-lines:
+			if !ok {
+				if err = stream.Err(); err != nil {
+					return "", fmt.Errorf("expect: %w", err)
+				}
+
+				return "", fmt.Errorf("%w: %q before the stream is closed", ErrExpectNotReceived, pattern)
+			}
+
+			executor.alert(executor.w, ses, line)
+
+			if pattern.MatchString(line) {
+				return line, nil
+			}
+		}
+	}
+}
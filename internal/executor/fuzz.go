@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/fuzz"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// ErrFuzzProtocol is returned when fuzzing is requested for protocol
+	// other than RCON.
+	ErrFuzzProtocol = errors.New("fuzzing is supported only for rcon protocol")
+
+	// ErrUnknownCase is returned when fuzzing case is not found.
+	ErrUnknownCase = errors.New("unknown fuzzing case")
+
+	// ErrServerDown is returned when the server stopped responding during
+	// fuzzing.
+	ErrServerDown = errors.New("server stopped responding")
+)
+
+// fuzzCommand returns subcommand which sends malformed packets.
+func (executor *Executor) fuzzCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "fuzz",
+		Usage: "Send malformed and boundary RCON packets to test robustness of the server",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringSliceFlag{
+				Name:  "case",
+				Usage: "Run only the case. Can be set several times",
+			},
+			&cli.DurationFlag{
+				Name:  "response-timeout",
+				Usage: "Time to wait for the reaction of the server to each case",
+				Value: 2 * time.Second,
+			},
+		},
+		Action: executor.fuzz,
+	}
+}
+
+// fuzz runs cases one by one and prints reactions of the server. Fuzzing is
+// stopped if the server stops responding.
+func (executor *Executor) fuzz(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	if ses.Type != "" && ses.Type != config.ProtocolRCON {
+		return fmt.Errorf("%w: %s", ErrFuzzProtocol, ses.Type)
+	}
+
+	cases, err := fuzzCases(c.StringSlice("case"))
+	if err != nil {
+		return err
+	}
+
+	for _, fc := range cases {
+		result := fuzz.Run(ses.Address, ses.Password, fc, c.Duration("response-timeout"))
+		_, _ = fmt.Fprintf(executor.w, "%-20s %s\n", fc.Name, result)
+
+		if !result.Alive {
+			return fmt.Errorf("%w: after %s", ErrServerDown, fc.Name)
+		}
+	}
+
+	return nil
+}
+
+// fuzzCases returns cases with the names or all cases if names are empty.
+func fuzzCases(names []string) ([]fuzz.Case, error) {
+	all := fuzz.Cases()
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	cases := make([]fuzz.Case, 0, len(names))
+
+	for _, name := range names {
+		found := false
+
+		for _, fc := range all {
+			if fc.Name == name {
+				cases = append(cases, fc)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCase, name)
+		}
+	}
+
+	return cases, nil
+}
@@ -0,0 +1,211 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/dictionary"
+	"github.com/gorcon/rcon-cli/internal/game"
+)
+
+// DefaultHelpCommand is the command which lists commands of the server if
+// neither help_command nor game is set.
+const DefaultHelpCommand = "help"
+
+// DefaultUnknownCommand matches responses to unknown commands if the game
+// preset has no own pattern.
+const DefaultUnknownCommand = `(?i)unknown command`
+
+// ErrEmptyGame is returned when commands are requested for environment
+// without game and commands learned from the server.
+var ErrEmptyGame = errors.New("commands are unknown: add game or commands_from_help to config environment")
+
+var (
+	// ErrNoUsage is returned when the syntax hint of the command is not
+	// found in the dictionary of the environment game.
+	ErrNoUsage = errors.New("usage is unknown")
+
+	// ErrUnknownCommand is returned when validate_commands is set and the
+	// command is not found in the command dictionary.
+	ErrUnknownCommand = errors.New("unknown command")
+)
+
+// applyGame sets empty session fields to the values of the game preset.
+func applyGame(ses *config.Session) {
+	preset, ok := game.Lookup(ses.Game)
+	if !ok {
+		return
+	}
+
+	if ses.Type == "" {
+		ses.Type = preset.Protocol
+	}
+
+	if ses.SayCommand == "" {
+		ses.SayCommand = preset.SayCommand
+	}
+
+	if ses.HelpCommand == "" {
+		ses.HelpCommand = preset.HelpCommand
+	}
+
+	if ses.PlayersCommand == "" {
+		ses.PlayersCommand = preset.PlayersCommand
+	}
+
+	if ses.PlayersPattern == "" {
+		ses.PlayersPattern = preset.Players
+	}
+
+	ses.Address = preset.Address(ses.Address)
+	ses.LogStripColors = ses.LogStripColors || preset.Colors
+}
+
+// fragmented returns true if the game server of the session splits long
+// responses into fragments which must be collected.
+func fragmented(ses *config.Session) bool {
+	preset, ok := game.Lookup(ses.Game)
+
+	return ok && preset.Fragmented
+}
+
+// bundled returns the bundled command dictionary of the session game.
+func bundled(ses *config.Session) []dictionary.Entry {
+	preset, _ := game.Lookup(ses.Game)
+	entries, _ := dictionary.Game(preset.Dictionary)
+
+	return entries
+}
+
+// known returns frequently used commands of the session game, commands of
+// the bundled dictionary and commands learned from the server.
+func (executor *Executor) known(ses *config.Session) []string {
+	preset, _ := game.Lookup(ses.Game)
+
+	return dictionary.Merge(dictionary.Merge(preset.Commands, dictionary.Names(bundled(ses))), executor.learned)
+}
+
+// names returns unique names of known commands, e.g. `whitelist` of
+// `whitelist add`.
+func (executor *Executor) names(ses *config.Session) []string {
+	commands := executor.known(ses)
+
+	names := make([]string, 0, len(commands))
+	for _, command := range commands {
+		name, _, _ := strings.Cut(command, " ")
+		names = append(names, name)
+	}
+
+	return dictionary.Merge(names)
+}
+
+// commands prints the command dictionary of the session game and commands
+// learned from the server which start with prefix.
+func (executor *Executor) commands(w io.Writer, ses *config.Session, prefix string) error {
+	commands := executor.known(ses)
+	if len(commands) == 0 {
+		return ErrEmptyGame
+	}
+
+	for _, command := range dictionary.Complete(commands, prefix) {
+		_, _ = fmt.Fprintln(w, command)
+	}
+
+	return nil
+}
+
+// learnCommands runs the help command if commands_from_help is set and
+// keeps commands of its response for :commands. Errors are ignored, the
+// dictionary of the game is used then.
+func (executor *Executor) learnCommands(ses *config.Session) {
+	executor.learned = nil
+
+	if !ses.CommandsFromHelp {
+		return
+	}
+
+	command := ses.HelpCommand
+	if command == "" {
+		command = DefaultHelpCommand
+	}
+
+	response, err := executor.client.Execute(command)
+	if err != nil {
+		return
+	}
+
+	executor.learned = dictionary.ParseHelp(response)
+}
+
+// usage prints the syntax hint of the command from the bundled dictionary
+// of the session game.
+func (executor *Executor) usage(w io.Writer, ses *config.Session, command string) error {
+	name, _, _ := strings.Cut(command, " ")
+	if name == "" {
+		return fmt.Errorf("%w: type %s command", ErrNoUsage, CommandUsage)
+	}
+
+	hint, ok := dictionary.Usage(bundled(ses), name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoUsage, name)
+	}
+
+	_, _ = fmt.Fprintln(w, hint)
+
+	return nil
+}
+
+// validate returns ErrUnknownCommand if validate_commands is set and the
+// first word of the command is not in the command dictionary. Commands are
+// not validated if the dictionary is empty.
+func (executor *Executor) validate(ses *config.Session, command string) error {
+	if !ses.ValidateCommands {
+		return nil
+	}
+
+	names := executor.names(ses)
+	if len(names) == 0 {
+		return nil
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 || dictionary.Contains(names, fields[0]) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnknownCommand, fields[0])
+}
+
+// suggest prints commands of the dictionary which are closest to the command
+// if the response of the server tells that the command is unknown.
+func (executor *Executor) suggest(w io.Writer, ses *config.Session, command, response string) {
+	preset, _ := game.Lookup(ses.Game)
+
+	pattern := preset.UnknownCommand
+	if pattern == "" {
+		pattern = DefaultUnknownCommand
+	}
+
+	if ok, err := regexp.MatchString(pattern, response); err != nil || !ok {
+		return
+	}
+
+	name, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+
+	names := executor.names(ses)
+	if name == "" || dictionary.Contains(names, name) {
+		return
+	}
+
+	switch suggested := dictionary.Suggest(names, name); len(suggested) {
+	case 0:
+	case 1:
+		_, _ = fmt.Fprintf(w, "Did you mean this?\n\t%s\n", suggested[0])
+	default:
+		_, _ = fmt.Fprintf(w, "Did you mean one of these?\n\t%s\n", strings.Join(suggested, "\n\t"))
+	}
+}
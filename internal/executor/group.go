@@ -0,0 +1,284 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/gorcon/rcon-cli/internal/stream"
+	"github.com/urfave/cli/v2"
+)
+
+// GroupPrefix marks the environment name as a group of environments from
+// the config groups section. Example: -e @eu.
+const GroupPrefix = config.GroupPrefix
+
+var (
+	// ErrUnknownGroup is returned when group is not defined in the config.
+	ErrUnknownGroup = errors.New("unknown group")
+
+	// ErrNoTargets is returned when no environment has requested tags.
+	ErrNoTargets = errors.New("no environments match tags")
+
+	// ErrParallelFormat is returned when output format can not be used in
+	// parallel mode.
+	ErrParallelFormat = errors.New("output format is not supported in parallel mode")
+
+	// ErrGroupInteractive is returned when group or tags are used in
+	// interactive mode.
+	ErrGroupInteractive = errors.New("group and tags can not be used in interactive mode: type commands to execute")
+)
+
+// targets returns environments selected by group in env flag and by tag
+// flags. Group members or all environments are filtered by tags. The second
+// returned value is false if neither group nor tags are set.
+func (executor *Executor) targets(c *cli.Context) ([]string, bool, error) {
+	group, isGroup := strings.CutPrefix(c.String("env"), GroupPrefix)
+	tags := c.StringSlice("tag")
+
+	if !isGroup && len(tags) == 0 {
+		return nil, false, nil
+	}
+
+	var envs []string
+
+	if isGroup {
+		members, ok := executor.groups[group]
+		if !ok {
+			return nil, true, fmt.Errorf("%w: %s", ErrUnknownGroup, group)
+		}
+
+		envs = members
+	} else {
+		for env := range executor.envs {
+			envs = append(envs, env)
+		}
+
+		sort.Strings(envs)
+	}
+
+	if len(tags) == 0 {
+		return envs, true, nil
+	}
+
+	selected := make([]string, 0, len(envs))
+
+	for _, env := range envs {
+		if hasTags(executor.envs[env].Tags, tags) {
+			selected = append(selected, env)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, true, fmt.Errorf("%w: %s", ErrNoTargets, strings.Join(tags, ", "))
+	}
+
+	return selected, true, nil
+}
+
+// hasTags returns true if all wanted tags are in tags.
+func hasTags(tags []string, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+
+		for _, tag := range tags {
+			if tag == w {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runFunc runs commands on the server of the session with the executor.
+type runFunc func(executor *Executor, ses *config.Session) error
+
+// fanOut runs fn for each environment one after another. Text output of
+// each server is preceded by the environment name header. With --keep-going
+// failed servers are skipped and their errors are returned at the end.
+func (executor *Executor) fanOut(c *cli.Context, envs []string, fn runFunc) error {
+	if c.Bool("parallel") {
+		return executor.fanOutParallel(c, envs, fn)
+	}
+
+	done, finish := executor.startProgress(c, len(envs))
+	defer finish()
+
+	var errs []error
+
+	for i, env := range envs {
+		ses, err := executor.switchEnv(c, env)
+		if err != nil && !executor.keepGoing {
+			return fmt.Errorf("%s: %w", env, err)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", env, err))
+
+			continue
+		}
+
+		if !output.IsStructured(ses.Format) {
+			if i != 0 {
+				_, _ = fmt.Fprintln(executor.w)
+			}
+
+			_, _ = fmt.Fprintf(executor.w, "==> %s <==\n", env)
+		}
+
+		err = fn(executor, ses)
+		done()
+
+		if err != nil && !executor.keepGoing {
+			return fmt.Errorf("%s: %w", env, err)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", env, err))
+		}
+	}
+
+	if err := failures(errs, len(envs), "servers"); err != nil {
+		_ = executor.Close()
+
+		return err
+	}
+
+	return executor.Close()
+}
+
+// fanOutParallel runs fn for all environments concurrently. Each output line
+// is prefixed with the environment name. Commands which require confirmation
+// are cancelled unless confirmation is disabled, because answers can not be
+// read by several servers at once. Errors of all servers are returned.
+func (executor *Executor) fanOutParallel(c *cli.Context, envs []string, fn runFunc) error {
+	if format := c.String("format"); format == output.FormatCSV || format == output.FormatYAML {
+		return fmt.Errorf("%w: %s", ErrParallelFormat, format)
+	}
+
+	done, finish := executor.startProgress(c, len(envs))
+	defer finish()
+
+	if c.Bool("ordered") {
+		return executor.fanOutOrdered(c, envs, fn, done)
+	}
+
+	mux := stream.NewMux(executor.w)
+	errs := make([]error, len(envs))
+
+	var wg sync.WaitGroup
+
+	for i, env := range envs {
+		wg.Add(1)
+
+		go func(i int, env string, w io.WriteCloser) {
+			defer wg.Done()
+			defer w.Close()
+
+			worker := executor.newWorker(w)
+			defer worker.Close()
+
+			if err := worker.parallel(c, env, fn); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", env, err)
+				_, _ = fmt.Fprintln(w, errs[i])
+			}
+
+			done()
+		}(i, env, mux.Writer(env))
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// fanOutOrdered runs fn for all environments concurrently and buffers
+// output of each server. Output of the server is flushed when the server and
+// all servers before it are done, so it is printed in the order of
+// environments as in sequential mode.
+func (executor *Executor) fanOutOrdered(c *cli.Context, envs []string, fn runFunc, done func()) error {
+	buffers := make([]bytes.Buffer, len(envs))
+	finished := make([]chan struct{}, len(envs))
+	errs := make([]error, len(envs))
+
+	for i, env := range envs {
+		finished[i] = make(chan struct{})
+
+		go func(i int, env string) {
+			defer close(finished[i])
+
+			worker := executor.newWorker(&buffers[i])
+			defer worker.Close()
+
+			if err := worker.parallel(c, env, fn); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", env, err)
+				_, _ = fmt.Fprintln(&buffers[i], errs[i])
+			}
+
+			done()
+		}(i, env)
+	}
+
+	structured := output.IsStructured(c.String("format"))
+
+	for i, env := range envs {
+		<-finished[i]
+
+		if !structured {
+			if i != 0 {
+				_, _ = fmt.Fprintln(executor.w)
+			}
+
+			_, _ = fmt.Fprintf(executor.w, "==> %s <==\n", env)
+		}
+
+		_, _ = buffers[i].WriteTo(executor.w)
+	}
+
+	return errors.Join(errs...)
+}
+
+// newWorker returns executor which runs commands on one server in parallel
+// mode. Reporters and settings of the run are shared with the worker.
+func (executor *Executor) newWorker(w io.Writer) *Executor {
+	worker := executor.child(nil, w)
+	worker.summary = executor.summary
+	worker.statsd = executor.statsd
+	worker.tracer = executor.tracer
+	worker.otel = executor.otel
+	worker.zabbix = executor.zabbix
+	worker.header = executor.header
+	worker.keepGoing = executor.keepGoing
+	worker.progress = executor.progress
+	worker.color = executor.color
+	worker.alerts = executor.alerts
+
+	return worker
+}
+
+// parallel loads session of the environment and runs fn in parallel mode.
+func (executor *Executor) parallel(c *cli.Context, env string, fn runFunc) error {
+	ses, err := executor.switchEnv(c, env)
+	if err != nil {
+		return err
+	}
+
+	if output.IsStructured(ses.Format) && ses.Format != output.FormatJSON {
+		return fmt.Errorf("%w: %s", ErrParallelFormat, ses.Format)
+	}
+
+	return fn(executor, ses)
+}
@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/output"
+)
+
+// Placeholders of --header template.
+const (
+	// HeaderNumber is replaced with the number of the command in the run,
+	// starting from 1.
+	HeaderNumber = "{n}"
+
+	// HeaderCommand is replaced with the command.
+	HeaderCommand = "{command}"
+)
+
+// printHeader prints --header template before the response of the command.
+// Structured formats contain the command in records, so the header is not
+// printed for them.
+func (executor *Executor) printHeader(w io.Writer, ses *config.Session, command string) {
+	if executor.header == "" || output.IsStructured(ses.Format) {
+		return
+	}
+
+	executor.headers++
+
+	header := strings.NewReplacer(HeaderNumber, strconv.Itoa(executor.headers), HeaderCommand, command).
+		Replace(executor.header)
+
+	_, _ = fmt.Fprintln(w, header)
+}
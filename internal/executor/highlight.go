@@ -0,0 +1,22 @@
+package executor
+
+import (
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/highlight"
+)
+
+// highlight colors the response by highlight rules of the session if the
+// output is a terminal with colors. Rules are validated with the config, so
+// the response is returned as is if they are invalid.
+func (executor *Executor) highlight(ses *config.Session, response string) string {
+	if !executor.color || len(ses.Highlight) == 0 {
+		return response
+	}
+
+	h, err := highlight.New(ses.Highlight)
+	if err != nil {
+		return response
+	}
+
+	return h.Apply(response)
+}
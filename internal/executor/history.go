@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrEmptyLog is returned when history is requested for environment
+// without log.
+var ErrEmptyLog = errors.New("log is not set: to set log add -l path or set log in config environment")
+
+// historyCommand returns subcommand which prints past requests and responses
+// from the log.
+func (executor *Executor) historyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "history",
+		Usage: "Print past commands and responses from the log",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:    "log",
+				Aliases: []string{"l"},
+				Usage:   "Path to the log file. If not specified it is taken from the config",
+			},
+			&cli.StringFlag{
+				Name:    "grep",
+				Aliases: []string{"g"},
+				Usage:   "Print only records which command or response match the regular expression",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Print only records newer than duration. Example 7d, 12h or 30m",
+			},
+		},
+		Action: executor.history,
+	}
+}
+
+// history prints log records filtered by flags.
+func (executor *Executor) history(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Log == "" {
+		return ErrEmptyLog
+	}
+
+	var since time.Time
+
+	if value := c.String("since"); value != "" {
+		var duration time.Duration
+		if duration, err = parseSince(value); err != nil {
+			return err
+		}
+
+		since = time.Now().Add(-duration)
+	}
+
+	var grep *regexp.Regexp
+
+	if value := c.String("grep"); value != "" {
+		if grep, err = regexp.Compile(value); err != nil {
+			return fmt.Errorf("grep: %w", err)
+		}
+	}
+
+	entries, err := logger.Read(ses.Log, since)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+
+		if ses.Address != "" && entry.Address != ses.Address {
+			continue
+		}
+
+		if grep != nil && !grep.MatchString(entry.Request) && !grep.MatchString(entry.Response) {
+			continue
+		}
+
+		_, _ = fmt.Fprint(executor.w, entry.String())
+	}
+
+	return nil
+}
+
+// parseSince parses duration with additional `d` unit for days.
+func parseSince(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("since: invalid duration %q", value)
+		}
+
+		const day = 24 * time.Hour
+
+		return time.Duration(n) * day, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("since: %w", err)
+	}
+
+	return duration, nil
+}
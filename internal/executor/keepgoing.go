@@ -0,0 +1,20 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrKeepGoing is returned with --keep-going flag when some of commands or
+// servers failed. It wraps errors of all failures.
+var ErrKeepGoing = errors.New("failed")
+
+// failures returns ErrKeepGoing with errs of failed units of total or nil if
+// nothing failed.
+func failures(errs []error, total int, unit string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w %d of %d %s:\n%w", ErrKeepGoing, len(errs), total, unit, errors.Join(errs...))
+}
@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+)
+
+// inMaintenance returns true if a maintenance window of the session
+// environment lasts now.
+func (executor *Executor) inMaintenance(ses *config.Session) bool {
+	return ses.Env != "" && executor.maintenance.Active(ses.Env, time.Now())
+}
+
+// alert checks the response by alerts of the config file. Alerts are
+// suppressed during maintenance of the session environment.
+func (executor *Executor) alert(w io.Writer, ses *config.Session, response string) {
+	if executor.inMaintenance(ses) {
+		return
+	}
+
+	if err := executor.alerts.Check(ses.Address, response); err != nil {
+		_, _ = fmt.Fprintln(w, redact.Error(err))
+	}
+}
@@ -0,0 +1,248 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/gorcon/rcon-cli/internal/alias"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+)
+
+// Interactive mode meta-commands. Meta-commands are handled by the CLI and
+// are not sent to the remote server.
+const (
+	// CommandAlias lists aliases or defines a new one.
+	// Example: `:alias warn say Restart in $1 minutes`.
+	CommandAlias = ":alias"
+
+	// CommandUse switches to another config environment.
+	// Example: `:use rust`.
+	CommandUse = ":use"
+
+	// CommandCopy places the last response on the system clipboard.
+	CommandCopy = ":copy"
+
+	// CommandShell runs a local shell command.
+	// Example: `:! grep Ban server.log`.
+	CommandShell = ":!"
+
+	// CommandCommands lists frequently used commands of the environment
+	// game and commands learned from the server. Commands are filtered by
+	// prefix if it is set. Example: `:commands ban`.
+	CommandCommands = ":commands"
+
+	// CommandUsage prints the syntax hint of the command from the bundled
+	// dictionary of the environment game. Example: `:usage kick`.
+	CommandUsage = ":usage"
+)
+
+var (
+	// ErrUnknownMetaCommand is returned when command starts with colon but is
+	// not a known meta-command.
+	ErrUnknownMetaCommand = errors.New("unknown meta-command")
+
+	// ErrEnvSwitchUnavailable is returned when environment can not be
+	// switched because the config is not loaded.
+	ErrEnvSwitchUnavailable = errors.New("environment switching is not available")
+
+	// ErrEmptyEnv is returned when environment name is not set.
+	ErrEmptyEnv = errors.New("environment is not set")
+
+	// ErrUnknownProfile is returned when environment to switch to is not
+	// defined in the config.
+	ErrUnknownProfile = errors.New("unknown environment")
+
+	// ErrNothingToCopy is returned when no response has been received yet.
+	ErrNothingToCopy = errors.New("nothing to copy")
+
+	// ErrEmptyShellCommand is returned when local shell command is not set.
+	ErrEmptyShellCommand = errors.New("shell command is not set")
+)
+
+// meta executes interactive meta-command. Returns false if command is not
+// a meta-command and must be sent to the remote server.
+func (executor *Executor) meta(w io.Writer, ses *config.Session, command string) (bool, error) {
+	if !strings.HasPrefix(command, ":") {
+		return false, nil
+	}
+
+	if args, ok := strings.CutPrefix(command, CommandShell); ok {
+		return true, executor.shell(w, strings.TrimSpace(args))
+	}
+
+	name, args, _ := strings.Cut(command, " ")
+
+	switch name {
+	case CommandAlias:
+		return true, executor.alias(w, strings.TrimSpace(args))
+	case CommandUse:
+		return true, executor.use(w, ses, strings.TrimSpace(args))
+	case CommandCopy:
+		return true, executor.copyLast(w)
+	case CommandCommands:
+		return true, executor.commands(w, ses, strings.TrimSpace(args))
+	case CommandUsage:
+		return true, executor.usage(w, ses, strings.TrimSpace(args))
+	default:
+		return true, fmt.Errorf("%w: %s", ErrUnknownMetaCommand, name)
+	}
+}
+
+// alias prints all aliases if args is empty, otherwise defines the alias.
+func (executor *Executor) alias(w io.Writer, args string) error {
+	if args == "" {
+		for _, name := range executor.aliases.Names() {
+			_, _ = fmt.Fprintf(w, "%s%s = %s\n", alias.Prefix, name, executor.aliases[name])
+		}
+
+		return nil
+	}
+
+	name, template, _ := strings.Cut(args, " ")
+
+	return executor.aliases.Set(name, strings.TrimSpace(template))
+}
+
+// use reloads credentials of the environment from the config and reconnects.
+// Password which is not set in the config is asked once and cached.
+// The current connection is kept if the new one can not be established.
+func (executor *Executor) use(w io.Writer, ses *config.Session, env string) error {
+	if env == "" {
+		return fmt.Errorf("%w: type %s env", ErrEmptyEnv, CommandUse)
+	}
+
+	if executor.sessions == nil {
+		return ErrEnvSwitchUnavailable
+	}
+
+	if _, ok := executor.envs[env]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, env)
+	}
+
+	next, err := executor.sessions(env)
+	if err != nil {
+		return err
+	}
+
+	if next.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if next.Password == "" && executor.scanner != nil {
+		next.Password = executor.askPassword(w, next.Address, func() string {
+			executor.scanner.Scan()
+
+			return executor.scanner.Text()
+		})
+	}
+
+	if next.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	current := executor.swap(nil)
+
+	if err = executor.Dial(next); err != nil {
+		executor.swap(current)
+
+		return err
+	}
+
+	if current != nil {
+		_ = current.Close()
+	}
+
+	*ses = *next
+	executor.env = env
+
+	_, _ = fmt.Fprintf(w, "Waiting commands for %s (or type %s to exit)\n", ses.Address, CommandQuit)
+	executor.learnCommands(ses)
+	executor.onConnect(w, ses)
+
+	return nil
+}
+
+// askPassword returns the cached password of the address or reads it with
+// read after the prompt. Entered password is cached and passed to the
+// credential agent.
+func (executor *Executor) askPassword(w io.Writer, address string, read func() string) string {
+	if password, ok := executor.credentials.Get(address); ok {
+		return password
+	}
+
+	if password, ok := executor.agent.Get(address); ok {
+		executor.credentials.Set(address, password)
+
+		return password
+	}
+
+	_, _ = fmt.Fprint(w, "Enter password: ")
+
+	password := read()
+	redact.Add(password)
+
+	if password != "" {
+		executor.credentials.Set(address, password)
+		executor.agent.Set(address, password)
+	}
+
+	return password
+}
+
+// copyLast places the last response on the system clipboard.
+func (executor *Executor) copyLast(w io.Writer) error {
+	if executor.last == "" {
+		return ErrNothingToCopy
+	}
+
+	if err := clipboard.WriteAll(executor.last); err != nil {
+		return fmt.Errorf("copy: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(w, "Copied %d bytes to clipboard\n", len(executor.last))
+
+	return nil
+}
+
+// shell runs local command with the system shell. Its output is written
+// between header and footer lines to separate it from server responses.
+func (executor *Executor) shell(w io.Writer, command string) error {
+	if command == "" {
+		return fmt.Errorf("%w: type %s command", ErrEmptyShellCommand, CommandShell)
+	}
+
+	name, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		name, flag = "cmd", "/C"
+	}
+
+	cmd := exec.Command(name, flag, command) //nolint:gosec // Command is typed by user.
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	_, _ = fmt.Fprintf(w, "==> %s %s <==\n", CommandShell, command)
+	err := cmd.Run()
+	_, _ = fmt.Fprintf(w, "==> %s <==\n", CommandShell)
+
+	if err != nil {
+		return fmt.Errorf("shell: %w", err)
+	}
+
+	return nil
+}
+
+// prompt returns interactive mode prompt with the environment chosen with
+// :use meta-command.
+func (executor *Executor) prompt() string {
+	if executor.env == "" {
+		return "> "
+	}
+
+	return executor.env + "> "
+}
@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/mqtt"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultMQTTBroker is the address of MQTT broker.
+const DefaultMQTTBroker = "tcp://127.0.0.1:1883"
+
+// mqttCommand returns subcommand which executes commands received from MQTT
+// broker.
+func (executor *Executor) mqttCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "mqtt",
+		Usage: "Execute commands received on MQTT topics and publish responses",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringSliceFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environments which receive commands, can be set several times",
+				Value:   cli.NewStringSlice(config.DefaultConfigEnv),
+			},
+			&cli.StringFlag{
+				Name:  "broker",
+				Usage: "MQTT broker address",
+				Value: DefaultMQTTBroker,
+			},
+			&cli.StringFlag{
+				Name:  "client-id",
+				Usage: "MQTT client identifier",
+				Value: "rcon-cli",
+			},
+			&cli.StringFlag{
+				Name:  "username",
+				Usage: "MQTT broker username",
+			},
+			&cli.StringFlag{
+				Name:    "mqtt-password",
+				Usage:   "MQTT broker password",
+				EnvVars: []string{"RCON_MQTT_PASSWORD"},
+			},
+			&cli.StringFlag{
+				Name:  "topic-prefix",
+				Usage: "First level of <prefix>/<env>/command and <prefix>/<env>/result topics",
+				Value: mqtt.DefaultTopicPrefix,
+			},
+			&cli.UintFlag{
+				Name:  "qos",
+				Usage: "MQTT quality of service level: 0, 1 or 2",
+				Value: 1,
+			},
+		},
+		Action: executor.mqtt,
+	}
+}
+
+// mqtt subscribes to command topics of environments and executes received
+// commands until interrupted. Commands policy and log of each environment
+// are applied. Commands which require confirmation are cancelled.
+func (executor *Executor) mqtt(c *cli.Context) error {
+	client, err := mqtt.Connect(mqtt.Options{
+		Broker:   c.String("broker"),
+		ClientID: c.String("client-id"),
+		Username: c.String("username"),
+		Password: c.String("mqtt-password"),
+		QoS:      byte(c.Uint("qos")),
+		Timeout:  config.DefaultTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	prefix := c.String("topic-prefix")
+
+	closeAll, err := executor.subscribeEnvs(c, func(env string, handler func(string) (string, error)) (string, error) {
+		return mqtt.CommandTopic(prefix, env), client.Subscribe(prefix, env, handler)
+	})
+	if err != nil {
+		return err
+	}
+	defer closeAll()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/otlp"
+	"github.com/gorcon/rcon-cli/internal/redact"
+)
+
+// spansTo starts the root span of the run if OpenTelemetry collector
+// endpoint is set. The run is a child of TRACEPARENT span from environment.
+// Returned function ends the root span with err and exports spans.
+func (executor *Executor) spansTo(endpoint string, timeout time.Duration) func(err error) {
+	if endpoint == "" {
+		return func(error) {}
+	}
+
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = otlp.DefaultServiceName
+	}
+
+	executor.otel = otlp.NewTracer(service, executor.version, "rcon", os.Getenv("TRACEPARENT"))
+
+	return func(err error) {
+		defer func() { executor.otel = nil }()
+
+		executor.otel.End(err)
+
+		if err = executor.otel.Export(endpoint, timeout); err != nil {
+			_, _ = fmt.Fprintln(executor.w, redact.Error(err))
+		}
+	}
+}
+
+// traceDial records dial and auth spans of the new connection. Dial is the
+// duration of TCP handshake measured by the client dialer, the rest is
+// authentication.
+// If the server is unreachable only dial span is recorded.
+func (executor *Executor) traceDial(ses *config.Session, start time.Time, dial time.Duration, err error) {
+	if executor.otel == nil {
+		return
+	}
+
+	attrs := map[string]string{"server.address": ses.Address, "rcon.protocol": ses.Type}
+	end := time.Now()
+
+	var failure string
+	if err != nil {
+		failure = err.Error()
+	}
+
+	if dial == 0 {
+		executor.otel.Record("dial", otlp.KindClient, start, end, attrs, failure)
+
+		return
+	}
+
+	executor.otel.Record("dial", otlp.KindClient, start, start.Add(dial), attrs, "")
+	executor.otel.Record("auth", otlp.KindClient, start.Add(dial), end, attrs, failure)
+}
+
+// traceExecute records execute span of the command which ended now. Only the
+// first word of the command is recorded, arguments can contain secrets.
+func (executor *Executor) traceExecute(address string, command string, duration time.Duration, failure string) {
+	if executor.otel == nil {
+		return
+	}
+
+	name, _, _ := strings.Cut(command, " ")
+	attrs := map[string]string{"server.address": address, "rcon.command": name}
+	end := time.Now()
+
+	executor.otel.Record("execute", otlp.KindClient, end.Add(-duration), end, attrs, failure)
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// ErrPlaintextPassword is returned when the password would be sent in
+// plaintext to the public address.
+var ErrPlaintextPassword = errors.New(
+	"password is sent in plaintext to public address: add --insecure flag or allow_plaintext setting to connect")
+
+// checkPlaintext returns ErrPlaintextPassword if the protocol of the session
+// sends the password in plaintext and the server is not in the private
+// network or on the local host. Source RCON and telnet have no encryption.
+// Detection of the protocol tries them with the password, so it is checked
+// before detection.
+func checkPlaintext(ses *config.Session) error {
+	if ses.AllowPlaintext {
+		return nil
+	}
+
+	switch ses.Type {
+	case "", config.ProtocolRCON, config.ProtocolTELNET, config.ProtocolAuto:
+	default:
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(ses.Address)
+	if err != nil {
+		host = strings.Trim(ses.Address, "[]")
+	}
+
+	if isPrivate(host) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrPlaintextPassword, ses.Address)
+}
+
+// isPrivate returns true if all addresses of the host are loopback, private
+// or link-local. Hosts which can not be resolved are reported as public, as
+// they can resolve to a public address when the connection is dialed.
+func isPrivate(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		if ips, err = net.LookupIP(host); err != nil || len(ips) == 0 {
+			return false
+		}
+	}
+
+	for _, ip := range ips {
+		if !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() {
+			return false
+		}
+	}
+
+	return true
+}
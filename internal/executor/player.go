@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// ErrEmptyPlayer is returned when find-player is called without name.
+	ErrEmptyPlayer = errors.New("player name is not set: to find player type find-player name")
+
+	// ErrEmptyPlayers is returned when the player list command or pattern is
+	// not known for the environment.
+	ErrEmptyPlayers = errors.New("player list is unknown: add game or players_command and players_pattern " +
+		"to config environment")
+)
+
+// findPlayerCommand returns subcommand which finds the player by name and
+// executes the follow-up command with the ID of the player.
+func (executor *Executor) findPlayerCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "find-player",
+		Usage:     "Find online player by name and execute command with ID of the player",
+		ArgsUsage: "name",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:  "then",
+				Usage: "Command to execute with the found player. Example --then 'kick {id}'",
+			},
+		},
+		Action: executor.findPlayer,
+	}
+}
+
+// findPlayer lists players with the player list command of the environment,
+// finds the player by exact, partial or mistyped name and prints its ID and
+// name. The follow-up command is executed with placeholders replaced.
+func (executor *Executor) findPlayer(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return ErrEmptyPlayer
+	}
+
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	if ses.PlayersCommand == "" || ses.PlayersPattern == "" {
+		return ErrEmptyPlayers
+	}
+
+	re, err := player.Compile(ses.PlayersPattern)
+	if err != nil {
+		return fmt.Errorf("find player: %w", err)
+	}
+
+	response, err := executor.request(ses, ses.PlayersCommand)
+	if err != nil {
+		return err
+	}
+
+	found, err := player.Find(player.Parse(re, response), name)
+	if err != nil {
+		return err //nolint:wrapcheck // Error of player package contains the name.
+	}
+
+	then := c.String("then")
+	if then == "" {
+		_, _ = fmt.Fprintf(executor.w, "%s\t%s\n", found.ID, found.Name)
+
+		return nil
+	}
+
+	return executor.Execute(executor.w, ses, player.Command(then, found))
+}
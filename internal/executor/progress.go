@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"os"
+
+	"github.com/gorcon/rcon-cli/internal/progress"
+	"github.com/gorcon/rcon-cli/internal/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+// startProgress draws the progress line of total units on stderr if
+// --progress flag is set and stderr is a terminal. The first caller owns the
+// line: it gets the function which marks one unit as done, nested runs like
+// batch file of fan-out server only update the current command. Returned
+// finish function erases the line.
+func (executor *Executor) startProgress(c *cli.Context, total int) (func(), func()) {
+	if executor.progress != nil || !c.Bool("progress") || !terminal.IsTerminal(os.Stderr) {
+		return func() {}, func() {}
+	}
+
+	bar := progress.New(os.Stderr, total)
+	executor.progress = bar
+
+	return bar.Done, func() {
+		bar.Finish()
+		executor.progress = nil
+	}
+}
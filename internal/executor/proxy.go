@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/bench"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/proxy"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultProxyListen is the address on which proxy accepts clients.
+const DefaultProxyListen = "127.0.0.1:25580"
+
+// ErrUnsupportedListenType is returned when proxy can not accept clients of
+// the protocol.
+var ErrUnsupportedListenType = errors.New("unsupported listen type")
+
+// proxyServer accepts proxy clients.
+type proxyServer interface {
+	Serve(listener net.Listener) error
+}
+
+// proxyCommand returns subcommand which forwards commands of local RCON
+// clients to the server.
+func (executor *Executor) proxyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "proxy",
+		Usage: "Accept RCON clients locally and forward their commands to the server",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "Address on which clients are accepted",
+				Value: DefaultProxyListen,
+			},
+			&cli.StringFlag{
+				Name:  "listen-type",
+				Usage: "Protocol of local clients: rcon, web or telnet. It can differ from the server protocol",
+				Value: config.ProtocolRCON,
+			},
+			&cli.StringFlag{
+				Name:    "proxy-password",
+				Usage:   "Password of local clients. Server password is not exposed to them",
+				EnvVars: []string{"RCON_PROXY_PASSWORD"},
+			},
+			&cli.StringFlag{
+				Name:  "rate",
+				Usage: "Maximum rate of forwarded commands. Example 10/s, 60/m",
+			},
+		},
+		Action: executor.proxy,
+	}
+}
+
+// proxy forwards commands of local clients over one upstream connection.
+// Commands policy and log of the environment are applied. Commands which
+// require confirmation are cancelled.
+func (executor *Executor) proxy(c *cli.Context) error {
+	// Upstream executor has no input, so confirmation is never given.
+	upstream := executor.child(nil, &syncWriter{w: executor.w})
+	defer upstream.Close()
+
+	ses, err := upstream.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	var mu sync.Mutex
+
+	concurrent := pipelined(ses)
+
+	handler := func(command string) (string, error) {
+		if !concurrent {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+
+		_, _ = fmt.Fprintf(upstream.w, "%s %q\n", time.Now().Format(time.DateTime), command)
+
+		return upstream.request(ses, command)
+	}
+
+	if rate := c.String("rate"); rate != "" {
+		var interval time.Duration
+		if interval, err = bench.ParseRate(rate); err != nil {
+			return err
+		}
+
+		handler = proxy.Limit(handler, interval)
+	}
+
+	server, err := newProxyServer(c.String("listen-type"), c.String("proxy-password"), handler)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", c.String("listen"))
+	if err != nil {
+		return fmt.Errorf("proxy: %w", err)
+	}
+	defer listener.Close()
+
+	_, _ = fmt.Fprintf(executor.w, "Forwarding commands from %s to %s\n", listener.Addr(), ses.Address)
+
+	return server.Serve(listener)
+}
+
+// newProxyServer creates server which accepts clients of the protocol.
+func newProxyServer(protocol string, password string, handler proxy.Handler) (proxyServer, error) {
+	switch protocol {
+	case config.ProtocolRCON:
+		return proxy.NewServer(password, handler)
+	case config.ProtocolWebRCON:
+		return proxy.NewWebServer(password, handler)
+	case config.ProtocolTELNET:
+		return proxy.NewTelnetServer(password, handler)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedListenType, protocol)
+	}
+}
@@ -0,0 +1,178 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultRestartMessage is the warning message if restart template is not
+// set for environment.
+const DefaultRestartMessage = "Server restarts in %s"
+
+// restartCommand returns subcommand which restarts the server with warnings
+// for players.
+func (executor *Executor) restartCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "restart",
+		Usage: "Warn players, save the world and shut down the server",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.DurationFlag{
+				Name:  "countdown",
+				Usage: "Time before shutdown. Warnings are sent at times from restart config",
+				Value: 15 * time.Minute,
+			},
+			&cli.BoolFlag{
+				Name:  "wait",
+				Usage: "Wait until the server is back up after shutdown",
+			},
+			&cli.DurationFlag{
+				Name:  "wait-timeout",
+				Usage: "Maximum time to wait for the server",
+				Value: DefaultWaitTimeout,
+			},
+		},
+		Action: executor.restart,
+	}
+}
+
+// restart sends warnings during countdown, then save and shutdown commands.
+func (executor *Executor) restart(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	// The server is stopped on purpose, so the user is not asked.
+	ses.Yes = true
+
+	shutdown := time.Now().Add(c.Duration("countdown"))
+
+	if err = executor.countdown(ses, shutdown); err != nil {
+		return err
+	}
+
+	if ses.Restart.SaveCommand != "" {
+		_ = executor.Close()
+
+		if err = executor.Execute(executor.w, ses, ses.Restart.SaveCommand); err != nil {
+			return err
+		}
+	}
+
+	command := ses.Restart.ShutdownCommand
+	if command == "" {
+		command = config.DefaultShutdownCommand
+	}
+
+	// Server can close connection without response to shutdown command.
+	if err = executor.Execute(executor.w, ses, command); err != nil {
+		_, _ = fmt.Fprintln(executor.w, redact.Error(err))
+	}
+
+	_ = executor.Close()
+
+	if !c.Bool("wait") {
+		return nil
+	}
+
+	if err = executor.waitDown(ses, c.Duration("wait-timeout")); err != nil {
+		return err
+	}
+
+	if err = executor.waitUp(ses, c.Duration("wait-timeout")); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "Server %s is back up\n", ses.Address)
+
+	return nil
+}
+
+// countdown sends restart warnings to players at warning times before
+// shutdown and sleeps until shutdown.
+func (executor *Executor) countdown(ses *config.Session, shutdown time.Time) error {
+	warnings := append([]time.Duration(nil), ses.Restart.WarningTimes()...)
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i] > warnings[j] })
+
+	command := ses.SayCommand
+	if command == "" {
+		command = DefaultSayCommand
+	}
+
+	for _, warning := range warnings {
+		left := time.Until(shutdown)
+		if warning > left {
+			continue
+		}
+
+		time.Sleep(left - warning)
+
+		message, err := executor.restartMessage(ses, warning, shutdown)
+		if err != nil {
+			return err
+		}
+
+		// Server can drop idle connection during long countdown.
+		_ = executor.Close()
+
+		if err = executor.Execute(executor.w, ses, command+" "+message); err != nil {
+			return err
+		}
+	}
+
+	time.Sleep(time.Until(shutdown))
+
+	return nil
+}
+
+// restartMessage renders restart warning with time left before shutdown.
+func (executor *Executor) restartMessage(ses *config.Session, left time.Duration, shutdown time.Time) (string, error) {
+	if ses.Restart.Template == "" {
+		return fmt.Sprintf(DefaultRestartMessage, formatDuration(left)), nil
+	}
+
+	vars := map[string]string{"Left": formatDuration(left), "Time": shutdown.Format("15:04")}
+
+	return executor.templates.Render(ses.Restart.Template, ses.Locale, vars)
+}
+
+// formatDuration formats duration without zero minutes and seconds,
+// e.g. 5m instead of 5m0s.
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/announce"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultSayCommand is the server command which broadcasts messages if
+// say_command is not set for environment.
+const DefaultSayCommand = "say"
+
+// ErrEmptyMessage is returned when say is called without message and
+// template.
+var ErrEmptyMessage = errors.New("message is not set: type message or set --template name")
+
+// sayCommand returns subcommand which broadcasts messages and announcement
+// templates to the server.
+func (executor *Executor) sayCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "say",
+		Usage:     "Broadcast message or announcement template to the server",
+		ArgsUsage: "[message]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:  "template",
+				Usage: "Name of announcement template from the config",
+			},
+			&cli.StringSliceFlag{
+				Name:  "var",
+				Usage: "Set template variable. Example --var Time=18:00",
+			},
+			&cli.StringFlag{
+				Name:  "locale",
+				Usage: "Locale of announcement template",
+			},
+		},
+		Action: executor.say,
+	}
+}
+
+// say sends the message or rendered template with say command of the
+// environment.
+func (executor *Executor) say(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	message, err := executor.announcement(c, ses)
+	if err != nil {
+		return err
+	}
+
+	if message == "" {
+		return ErrEmptyMessage
+	}
+
+	command := ses.SayCommand
+	if command == "" {
+		command = DefaultSayCommand
+	}
+
+	return executor.Execute(executor.w, ses, command+" "+message)
+}
+
+// announcement returns message from args or renders template from flags.
+func (executor *Executor) announcement(c *cli.Context, ses *config.Session) (string, error) {
+	name := c.String("template")
+	if name == "" {
+		return strings.Join(c.Args().Slice(), " "), nil
+	}
+
+	vars, err := announce.ParseVars(c.StringSlice("var"))
+	if err != nil {
+		return "", err
+	}
+
+	return executor.templates.Render(name, ses.Locale, vars)
+}
@@ -0,0 +1,174 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/announce"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/cron"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/scheduler"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrNoAnnouncements is returned when the config has no recurring
+// announcements.
+var ErrNoAnnouncements = errors.New("no announcements in the config")
+
+// schedulerCommand returns subcommand which broadcasts recurring
+// announcements of the config.
+func (executor *Executor) schedulerCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "scheduler",
+		Usage: "Broadcast recurring announcements of the config by crontab schedules",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+		},
+		Action: executor.scheduler,
+	}
+}
+
+// scheduledEnv is the environment to which announcements are broadcast.
+type scheduledEnv struct {
+	upstream *Executor
+	ses      *config.Session
+	handler  func(command string) (string, error)
+}
+
+// scheduler broadcasts announcements until interrupted. Each environment of
+// the announcement gets its own random jitter. Announcements are skipped
+// during maintenance of the environment.
+func (executor *Executor) scheduler(c *cli.Context) error {
+	file, err := config.NewFile(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if len(file.Announcements) == 0 {
+		return ErrNoAnnouncements
+	}
+
+	envs := make(map[string]*scheduledEnv)
+	w := &syncWriter{w: executor.w}
+
+	defer func() {
+		for _, env := range envs {
+			_ = env.upstream.Close()
+		}
+	}()
+
+	var jobs []scheduler.Job
+
+	for _, name := range file.Announcements.Names() {
+		name, announcement := name, file.Announcements[name]
+
+		var schedule *cron.Schedule
+		if schedule, err = cron.Parse(announcement.Cron); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, env := range expandGroups(announcement.Envs, file.Groups) {
+			target, ok := envs[env]
+			if !ok {
+				// Upstream executor has no input, so confirmation is never given.
+				upstream := executor.child(nil, w)
+
+				var ses *config.Session
+				if ses, err = upstream.switchEnv(c, env); err != nil {
+					_ = upstream.Close()
+
+					return fmt.Errorf("%s: %w", env, err)
+				}
+
+				target = &scheduledEnv{upstream: upstream, ses: ses, handler: executor.upstreamHandler(upstream, ses, env)}
+				envs[env] = target
+			}
+
+			jobs = append(jobs, scheduler.Job{
+				Schedule: schedule,
+				Jitter:   announcement.Jitter,
+				Run: func() {
+					executor.announce(name, announcement, file.Templates, target)
+				},
+			})
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	_, _ = fmt.Fprintf(executor.w, "Scheduled %d announcements to %d servers\n", len(file.Announcements), len(envs))
+
+	scheduler.Run(ctx, jobs)
+
+	return nil
+}
+
+// announce broadcasts the announcement with say command of the environment.
+// Errors are logged, so the next announcements are still broadcast.
+func (executor *Executor) announce(
+	name string, announcement announce.Schedule, templates announce.Templates, target *scheduledEnv,
+) {
+	if target.upstream.inMaintenance(target.ses) {
+		_, _ = fmt.Fprintf(target.upstream.w, "%s %s %s skipped: maintenance\n",
+			time.Now().Format(time.DateTime), target.ses.Env, name)
+
+		return
+	}
+
+	message, err := announcement.Text(templates, target.ses.Locale)
+	if err != nil {
+		_, _ = fmt.Fprintf(target.upstream.w, "%s %s %s: %s\n", time.Now().Format(time.DateTime), target.ses.Env, name, redact.Error(err))
+
+		return
+	}
+
+	command := target.ses.SayCommand
+	if command == "" {
+		command = DefaultSayCommand
+	}
+
+	if _, err = target.handler(command + " " + message); err != nil {
+		_, _ = fmt.Fprintf(target.upstream.w, "%s %s %s: %s\n", time.Now().Format(time.DateTime), target.ses.Env, name, redact.Error(err))
+	}
+}
+
+// expandGroups replaces groups with GroupPrefix by their environments.
+// Duplicates are removed.
+func expandGroups(envs []string, groups map[string][]string) []string {
+	expanded := make([]string, 0, len(envs))
+	seen := make(map[string]bool, len(envs))
+
+	add := func(env string) {
+		if !seen[env] {
+			seen[env] = true
+			expanded = append(expanded, env)
+		}
+	}
+
+	for _, env := range envs {
+		group, ok := strings.CutPrefix(env, GroupPrefix)
+		if !ok {
+			add(env)
+
+			continue
+		}
+
+		for _, member := range groups[group] {
+			add(member)
+		}
+	}
+
+	return expanded
+}
@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/script"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrEmptyScript is returned when script file is not set.
+var ErrEmptyScript = errors.New("script file is not set: to run script type script run file.lua")
+
+// scriptCommand returns subcommand which runs Lua scripts.
+func (executor *Executor) scriptCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "script",
+		Usage: "Run Lua scripts for automation on several servers",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "run",
+				Usage:     "Run Lua script from the file",
+				ArgsUsage: "file.lua",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to the configuration file",
+						Value:   config.DefaultConfigName,
+					},
+					&cli.StringFlag{
+						Name:    "env",
+						Aliases: []string{"e"},
+						Usage:   "Config environment with server credentials for execute with empty env",
+						Value:   config.DefaultConfigEnv,
+					},
+				},
+				Action: executor.scriptRun,
+			},
+		},
+	}
+}
+
+// scriptRun runs Lua script. Each config environment used in the script has
+// its own connection which is closed when the script ends.
+func (executor *Executor) scriptRun(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return ErrEmptyScript
+	}
+
+	if _, err := executor.NewSession(c); err != nil {
+		return err
+	}
+
+	type target struct {
+		executor *Executor
+		ses      *config.Session
+	}
+
+	// Confirmation answers are read by all connections from the same input.
+	if executor.scanner == nil && executor.r != nil {
+		executor.scanner = bufio.NewScanner(executor.r)
+	}
+
+	targets := make(map[string]target)
+
+	defer func() {
+		for _, t := range targets {
+			_ = t.executor.Close()
+		}
+	}()
+
+	open := func(env string) (target, error) {
+		if env == "" {
+			env = c.String("env")
+		}
+
+		t, ok := targets[env]
+		if ok {
+			return t, nil
+		}
+
+		t.executor = executor.child(executor.r, executor.w)
+		t.executor.scanner = executor.scanner
+
+		ses, err := t.executor.newSession(c, env)
+		if err != nil {
+			return t, err
+		}
+
+		if ses.Address == "" {
+			return t, ErrEmptyAddress
+		}
+
+		if ses.Password == "" {
+			return t, ErrEmptyPassword
+		}
+
+		t.ses = ses
+		targets[env] = t
+
+		return t, nil
+	}
+
+	execute := func(env string, command string) (string, error) {
+		t, err := open(env)
+		if err != nil {
+			return "", err
+		}
+
+		return t.executor.request(t.ses, command)
+	}
+
+	expect := func(env, command, pattern string, timeout time.Duration) (string, error) {
+		t, err := open(env)
+		if err != nil {
+			return "", err
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("expect: %w", err)
+		}
+
+		return t.executor.expect(t.ses, re, timeout, func() (string, error) {
+			return t.executor.request(t.ses, command)
+		})
+	}
+
+	return script.New(executor.w, execute, expect, executor.parsers).RunFile(name)
+}
+
+// request sends command to the remote server and returns the response
+// without printing it. Aliases, hooks, commands policy, confirmation and log
+// are applied the same way as for printed commands.
+func (executor *Executor) request(ses *config.Session, command string) (string, error) {
+	rec, err := executor.roundTrip(executor.w, ses, command)
+	if rec == nil || rec.Error != "" {
+		return "", err
+	}
+
+	if err != nil {
+		// Reconnect on the next request. Pipelined connection is kept if only
+		// the command timed out, so concurrent commands are not interrupted.
+		if !pipelined(ses) || !errors.Is(err, os.ErrDeadlineExceeded) {
+			_ = executor.Close()
+		}
+
+		return rec.Response, fmt.Errorf("execute: %w", client.Classify(err))
+	}
+
+	executor.complete(executor.w, ses, rec)
+
+	return rec.Response, nil
+}
@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/secret"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// MaskedPassword replaces passwords and secrets in printed config.
+const MaskedPassword = redact.Mask
+
+// ErrUnknownEnv is returned when environment is not found in the config.
+var ErrUnknownEnv = errors.New("unknown environment")
+
+// configCommand returns subcommand which inspects the configuration file.
+func (executor *Executor) configCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Inspect the configuration file",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "show",
+				Usage: "Print resolved environments with defaults and inherited fields, passwords are masked",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Path to the configuration file",
+						Value:   config.DefaultConfigName,
+					},
+					&cli.StringFlag{
+						Name:    "env",
+						Aliases: []string{"e"},
+						Usage:   "Print only the environment. If not specified all environments are printed",
+					},
+				},
+				Action: executor.configShow,
+			},
+		},
+	}
+}
+
+// configShow prints environments of the config file as they are used to
+// connect to servers.
+func (executor *Executor) configShow(c *cli.Context) error {
+	name := config.Find(c.String("config"))
+
+	file, err := config.NewFile(name)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	envs := make([]string, 0, len(file.Environments))
+	for env := range file.Environments {
+		envs = append(envs, env)
+	}
+
+	if env := c.String("env"); env != "" {
+		if _, ok := file.Environments[env]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownEnv, env)
+		}
+
+		envs = []string{env}
+	}
+
+	resolved := make(config.Config, len(envs))
+	for _, env := range envs {
+		resolved[env] = resolve(c, file.Environments, env)
+	}
+
+	data, err := yaml.Marshal(resolved)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	var abs string
+	if abs, err = filepath.Abs(name); err == nil {
+		name = abs
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "# %s\n%s", name, data)
+
+	return nil
+}
+
+// resolve returns the session of the environment as it is created to
+// connect to the server and masks the password and headers. References to
+// password manager secrets are not resolved and not masked.
+func resolve(c *cli.Context, envs config.Config, env string) config.Session {
+	ses := flagSession(c)
+	mergeSession(c, &ses, envs, env)
+
+	if ses.Timeout == 0 {
+		ses.Timeout = config.DefaultTimeout
+	}
+
+	if ses.Password != "" && !secret.IsReference(ses.Password) {
+		ses.Password = MaskedPassword
+	}
+
+	ses.Headers = maskHeaders(ses.Headers)
+
+	return ses
+}
@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/game"
+)
+
+// sendParts sends command to the server. Command which exceeds the protocol
+// limit is split at semicolons and sent in parts if the server executes
+// semicolon separated commands, responses of the parts are joined.
+func (executor *Executor) sendParts(ses *config.Session, command string) (string, error) {
+	conn, err := executor.connection()
+	if err != nil {
+		return "", err
+	}
+
+	if len(command) <= client.MaxCommandLen || !semicolons(ses) {
+		return conn.Execute(command) //nolint:wrapcheck // Classified by caller.
+	}
+
+	parts, err := client.SplitCommand(command)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Classified by caller.
+	}
+
+	responses := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		var result string
+		if result, err = conn.Execute(part); err != nil {
+			break
+		}
+
+		if result = strings.TrimSpace(result); result != "" {
+			responses = append(responses, result)
+		}
+	}
+
+	return strings.Join(responses, "\n"), err //nolint:wrapcheck // Classified by caller.
+}
+
+// semicolons returns true if the server of the session executes semicolon
+// separated commands. It is the rule of Source servers, which are expected
+// if the game is not set.
+func semicolons(ses *config.Session) bool {
+	if ses.Type != "" && ses.Type != config.ProtocolRCON {
+		return false
+	}
+
+	if ses.Game == "" {
+		return true
+	}
+
+	preset, _ := game.Lookup(ses.Game)
+
+	return preset.Semicolons
+}
+
+// statements returns commands which the server of the session executes
+// separately for the command. Servers execute each line of the command and
+// Source servers also each statement separated by semicolon.
+func statements(ses *config.Session, command string) []string {
+	separator := ""
+	if semicolons(ses) {
+		separator = client.CommandSeparator
+	}
+
+	lines := strings.FieldsFunc(command, func(r rune) bool {
+		return r == '\n' || r == '\r'
+	})
+
+	result := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		result = append(result, client.Statements(line, separator)...)
+	}
+
+	return result
+}
+
+// splitCommands splits each command by the delimiter to separate commands.
+// Delimiters inside double quotes are kept, empty commands between
+// delimiters are dropped.
+func splitCommands(commands []string, delimiter string) []string {
+	if delimiter == "" {
+		return commands
+	}
+
+	split := make([]string, 0, len(commands))
+
+	for _, command := range commands {
+		split = append(split, client.Statements(command, delimiter)...)
+	}
+
+	return split
+}
@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/statsd"
+)
+
+// statsdTo starts sending metrics of commands to the StatsD server with the
+// address if it is set. Returned function closes the connection.
+func (executor *Executor) statsdTo(address string, prefix string, tags bool) (func(), error) {
+	if address == "" {
+		return func() {}, nil
+	}
+
+	client, err := statsd.New(address, prefix, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	executor.statsd = client
+
+	return func() {
+		executor.statsd = nil
+		_ = client.Close()
+	}, nil
+}
+
+// emit sends timing and counters of the command to StatsD if it is enabled.
+// Only the first word of the command is used as tag to limit cardinality.
+func (executor *Executor) emit(address string, command string, duration time.Duration, failed bool) {
+	if executor.statsd == nil {
+		return
+	}
+
+	name, _, _ := strings.Cut(command, " ")
+	tags := []string{"address:" + address, "command:" + name}
+
+	executor.statsd.Count("command.count", 1, tags...)
+	executor.statsd.Timing("command.duration", duration, tags...)
+
+	if failed {
+		executor.statsd.Count("command.errors", 1, tags...)
+	}
+}
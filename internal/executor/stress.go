@@ -0,0 +1,217 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/bench"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// Default settings of stress command.
+const (
+	DefaultStressConnections = 10
+	DefaultStressRate        = "10/s"
+	DefaultStressDuration    = 10 * time.Second
+)
+
+// ErrNoConnections is returned when no stress connection was established.
+var ErrNoConnections = errors.New("no connections established")
+
+// stressCommand returns subcommand which loads the server with concurrent
+// connections.
+func (executor *Executor) stressCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stress",
+		Usage: "Open concurrent connections and send commands at the rate to test the server",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.IntFlag{
+				Name:  "connections",
+				Usage: "Number of concurrent authenticated connections",
+				Value: DefaultStressConnections,
+			},
+			&cli.StringFlag{
+				Name:  "rate",
+				Usage: "Total rate of commands. Example 100/s, 600/m",
+				Value: DefaultStressRate,
+			},
+			&cli.DurationFlag{
+				Name:  "duration",
+				Usage: "Duration of the test",
+				Value: DefaultStressDuration,
+			},
+			&cli.StringFlag{
+				Name:  "command",
+				Usage: "Command to send",
+				Value: DefaultBenchCommand,
+			},
+		},
+		Action: executor.stress,
+	}
+}
+
+// stressStats collects results of concurrent requests.
+type stressStats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	failed    int
+	skipped   int
+}
+
+// add saves the result of the request.
+func (s *stressStats) add(latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.failed++
+
+		return
+	}
+
+	s.latencies = append(s.latencies, latency)
+}
+
+// stress opens connections and sends commands at the rate for the duration.
+// Requests are skipped if all connections are busy.
+func (executor *Executor) stress(c *cli.Context) error {
+	count := c.Int("connections")
+	if count <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidCount, count)
+	}
+
+	interval, err := bench.ParseRate(c.String("rate"))
+	if err != nil {
+		return err
+	}
+
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	command := c.String("command")
+	if err = executor.check(ses, command); err != nil {
+		return err
+	}
+
+	workers, failed := executor.connect(ses, count)
+	_, _ = fmt.Fprintf(executor.w, "%s %q: %d connections, %d failed\n", ses.Address, command, len(workers), failed)
+
+	if len(workers) == 0 {
+		return ErrNoConnections
+	}
+
+	stats := fire(ses, workers, command, interval, c.Duration("duration"))
+
+	_, _ = fmt.Fprintln(executor.w, bench.Summarize(stats.latencies, stats.failed))
+
+	if stats.skipped != 0 {
+		_, _ = fmt.Fprintf(executor.w, "%d requests skipped, all connections were busy\n", stats.skipped)
+	}
+
+	return nil
+}
+
+// connect establishes count connections concurrently. Returns executors
+// with established connections and the number of failed ones.
+func (executor *Executor) connect(ses *config.Session, count int) ([]*Executor, int) {
+	workers := make([]*Executor, count)
+
+	var wg sync.WaitGroup
+
+	for i := range workers {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			worker := executor.child(nil, io.Discard)
+			if err := worker.Dial(ses); err == nil {
+				workers[i] = worker
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	connected := make([]*Executor, 0, count)
+
+	for _, worker := range workers {
+		if worker != nil {
+			connected = append(connected, worker)
+		}
+	}
+
+	return connected, count - len(connected)
+}
+
+// fire sends the command with one of free workers every interval until
+// duration is elapsed. Workers are closed after all requests are completed.
+func fire(ses *config.Session, workers []*Executor, command string, interval, duration time.Duration) *stressStats {
+	stats := stressStats{}
+	jobs := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	for _, worker := range workers {
+		wg.Add(1)
+
+		go func(worker *Executor) {
+			defer wg.Done()
+			defer worker.Close()
+
+			for range jobs {
+				stats.add(worker.measure(ses, command))
+			}
+		}(worker)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	deadline := time.After(duration)
+
+loop:
+	for {
+		select {
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+			select {
+			case jobs <- struct{}{}:
+			default:
+				stats.skipped++
+			}
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return &stats
+}
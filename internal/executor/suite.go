@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/suite"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// ErrEmptySuite is returned when the test suite file is not set.
+	ErrEmptySuite = errors.New("suite file is not set: to run tests type test suite.yaml")
+
+	// ErrSuiteFailed is returned when some tests of the suite failed.
+	ErrSuiteFailed = errors.New("test suite failed")
+)
+
+// testCommand returns subcommand which runs declarative test suites.
+func (executor *Executor) testCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "test",
+		Usage:     "Run test suite of commands with expected responses from YAML file",
+		ArgsUsage: "suite.yaml",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials. Overrides environments of the suite",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:  "junit",
+				Usage: "Write JUnit XML report to the file",
+			},
+		},
+		Action: executor.test,
+	}
+}
+
+// test runs tests of the suite on each environment one by one, prints the
+// result of each test and the overview. All tests are run even if some of
+// them fail.
+func (executor *Executor) test(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return ErrEmptySuite
+	}
+
+	if _, err := executor.NewSession(c); err != nil {
+		return err
+	}
+
+	s, err := suite.Load(name)
+	if err != nil {
+		return err //nolint:wrapcheck // Error of suite package has the prefix.
+	}
+
+	envs := s.Envs
+	if len(envs) == 0 || c.IsSet("env") {
+		envs = []string{c.String("env")}
+	}
+
+	defer executor.Close()
+
+	report := suite.NewReport(s.Name)
+
+	for _, env := range envs {
+		ses, err := executor.switchEnv(c, env)
+
+		for i := range s.Tests {
+			result := suite.Result{Env: env, Name: s.Tests[i].Name, Command: s.Tests[i].Command}
+
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result = executor.runTest(ses, &s.Tests[i], result)
+			}
+
+			_, _ = fmt.Fprintln(executor.w, result)
+			report.Add(result)
+		}
+	}
+
+	_, _ = fmt.Fprintln(executor.w, report)
+
+	if junit := c.String("junit"); junit != "" {
+		if err = report.WriteJUnitFile(junit); err != nil {
+			return err //nolint:wrapcheck // Error of suite package has the prefix.
+		}
+	}
+
+	if failed := report.Failed(); failed != 0 {
+		return fmt.Errorf("%w: %d of %d tests", ErrSuiteFailed, failed, len(report.Results))
+	}
+
+	return nil
+}
+
+// runTest executes the command of the test and checks the response.
+func (executor *Executor) runTest(ses *config.Session, t *suite.Test, result suite.Result) suite.Result {
+	start := time.Now()
+	response, err := executor.request(ses, t.Command)
+	result.Duration = time.Since(start)
+	result.Response = response
+
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if err = t.Check(response); err != nil {
+		result.Failure = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/gorcon/rcon-cli/internal/pushgateway"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/summary"
+	"github.com/urfave/cli/v2"
+)
+
+// summaryTo starts collecting outcomes of commands if the summary file name,
+// Pushgateway URL or --print-summary flag is set. Returned function marks the
+// run as failed if err is not nil, writes the report to the file, pushes
+// metrics, prints the overview and stops collecting.
+func (executor *Executor) summaryTo(c *cli.Context) func(err error) {
+	name, pushURL, overview := c.String("summary"), c.String("push-metrics"), c.Bool("print-summary")
+	if name == "" && pushURL == "" && !overview {
+		return func(error) {}
+	}
+
+	executor.summary = summary.New()
+
+	return func(err error) {
+		defer func() { executor.summary = nil }()
+
+		if err != nil {
+			executor.summary.Fail()
+		}
+
+		executor.summary.Finish()
+
+		if name != "" {
+			if err = executor.summary.WriteFile(name); err != nil {
+				_, _ = fmt.Fprintln(executor.w, redact.Error(err))
+			}
+		}
+
+		if pushURL != "" {
+			if err = executor.pushMetrics(pushURL, c.Duration("timeout")); err != nil {
+				_, _ = fmt.Fprintln(executor.w, redact.Error(err))
+			}
+		}
+
+		if overview {
+			executor.printSummary(c.String("format"))
+		}
+	}
+}
+
+// printSummary prints the overview of the run. JSON format gets one JSON
+// line with summary object, CSV and YAML formats get nothing, because the
+// overview does not fit their records.
+func (executor *Executor) printSummary(format string) {
+	switch format {
+	case "", output.FormatText:
+		_, _ = fmt.Fprintln(executor.w, executor.summary.Overview())
+	case output.FormatJSON:
+		overview := struct {
+			Summary summary.Overview `json:"summary"`
+		}{Summary: executor.summary.Overview()}
+
+		_ = json.NewEncoder(executor.w).Encode(overview)
+	}
+}
+
+// pushMetrics pushes metrics of the summary to Pushgateway.
+func (executor *Executor) pushMetrics(pushURL string, timeout time.Duration) error {
+	metrics := &bytes.Buffer{}
+	if err := executor.summary.WriteMetrics(metrics); err != nil {
+		return err
+	}
+
+	return pushgateway.Push(pushURL, metrics.Bytes(), timeout)
+}
+
+// summarize adds outcome of the command to the summary, StatsD metrics,
+// OpenTelemetry spans and Zabbix items if they are enabled. Record is nil if
+// the command was not sent.
+func (executor *Executor) summarize(
+	ses *config.Session, command string, rec *output.Record, duration time.Duration, err error,
+) {
+	if executor.summary == nil && executor.statsd == nil && executor.otel == nil && executor.zabbix == nil {
+		return
+	}
+
+	var response, failure string
+
+	if rec != nil {
+		command, response, failure = rec.Command, rec.Response, rec.Error
+	}
+
+	if err != nil {
+		failure = err.Error()
+	}
+
+	executor.emit(ses.Address, command, duration, failure != "")
+	executor.traceExecute(ses.Address, command, duration, failure)
+
+	if failure == "" {
+		executor.collect(ses, rec, duration)
+	}
+
+	if executor.summary != nil {
+		executor.summary.Add(ses.Address, command, response, duration, failure)
+	}
+}
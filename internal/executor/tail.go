@@ -0,0 +1,293 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/urfave/cli/v2"
+)
+
+// ConsoleTailCommand is the command of Rust server which returns recent
+// console lines as JSON. It is used to print the backlog of --since.
+const ConsoleTailCommand = "console.tail"
+
+// DefaultTailBacklog is the number of recent console lines which are
+// requested for --since.
+const DefaultTailBacklog = 1000
+
+// DefaultTailBackoff is the delay before the first reconnect of the lost
+// console stream. The delay is doubled after each failed attempt up to
+// MaxTailBackoff.
+const DefaultTailBackoff = time.Second
+
+// MaxTailBackoff is the maximum delay between reconnects of the lost console
+// stream.
+const MaxTailBackoff = time.Minute
+
+// ErrSinceUnsupported is returned when --since is set for the protocol which
+// does not keep console history.
+var ErrSinceUnsupported = errors.New("since is supported for web protocol only")
+
+// consoleEntry is the console line of console.tail response.
+type consoleEntry struct {
+	Message string `json:"Message"`
+	Time    int64  `json:"Time"`
+}
+
+// tailCommand returns subcommand which follows console output of the server.
+func (executor *Executor) tailCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tail",
+		Usage: "Follow console output of telnet or web server, e.g. joins and chat",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Config environment with server credentials",
+				Value:   config.DefaultConfigEnv,
+			},
+			&cli.StringFlag{
+				Name:    "grep",
+				Aliases: []string{"g"},
+				Usage:   "Print only lines which match the regular expression",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Print console lines newer than duration before following. Example 10m or 1h",
+			},
+			&cli.IntFlag{
+				Name:  "reconnect",
+				Usage: "Reconnect the lost console stream up to count attempts in a row",
+			},
+			&cli.DurationFlag{
+				Name:  "backoff",
+				Usage: "Delay before the first reconnect, doubled after each failed attempt",
+				Value: DefaultTailBackoff,
+			},
+		},
+		Action: executor.tail,
+	}
+}
+
+// tail prints console output of the server until interrupted or until the
+// connection is lost. Lines are highlighted and checked by alerts. The lost
+// stream is reconnected with --reconnect and markers of the loss and the
+// resume are printed between console lines.
+func (executor *Executor) tail(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" {
+		return ErrEmptyPassword
+	}
+
+	var grep *regexp.Regexp
+
+	if value := c.String("grep"); value != "" {
+		if grep, err = regexp.Compile(value); err != nil {
+			return fmt.Errorf("grep: %w", err)
+		}
+	}
+
+	if err = checkPlaintext(ses); err != nil {
+		return err
+	}
+
+	if err = executor.detect(ses); err != nil {
+		return err
+	}
+
+	if value := c.String("since"); value != "" {
+		var since time.Duration
+		if since, err = parseSince(value); err != nil {
+			return err
+		}
+
+		if err = executor.backlog(ses, grep, time.Now().Add(-since)); err != nil {
+			return err
+		}
+	}
+
+	opts, err := streamOptions(ses)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opened, err := executor.follow(ctx, ses, grep, opts, false)
+	if !opened {
+		return err
+	}
+
+	reconnect := c.Int("reconnect")
+
+	for attempt := 1; attempt <= reconnect && ctx.Err() == nil; attempt++ {
+		delay := tailBackoff(c.Duration("backoff"), attempt)
+
+		reason := "closed by server"
+		if err != nil {
+			reason = redact.Error(err)
+		}
+
+		_, _ = fmt.Fprintf(executor.w, "--- connection lost: %s, reconnecting in %s (%d/%d)\n",
+			reason, delay, attempt, reconnect)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		if opened, err = executor.follow(ctx, ses, grep, opts, true); opened {
+			// Attempts are counted again after the stream is resumed.
+			attempt = 0
+		}
+	}
+
+	// Interrupted by the user, otherwise the stream is lost for good.
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+// streamOptions returns options of the console stream of the session.
+func streamOptions(ses *config.Session) ([]client.Option, error) {
+	d, err := dialer(ses)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := webOptions(ses)
+	if err != nil {
+		return nil, err
+	}
+
+	telnetOpts, err := telnetOptions(ses)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(opts, telnetOpts...), client.WithTimeout(ses.Timeout), client.WithDialer(d)), nil
+}
+
+// tailBackoff returns the delay before the reconnect attempt starting from
+// 1. The delay is doubled after each attempt up to MaxTailBackoff.
+func tailBackoff(backoff time.Duration, attempt int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempt && delay < MaxTailBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, MaxTailBackoff)
+}
+
+// follow opens the console stream and prints its lines until the stream
+// ends. It returns true if the stream was opened and the error which ended
+// it. Marker of the resume is printed if resumed is set.
+func (executor *Executor) follow(
+	ctx context.Context, ses *config.Session, grep *regexp.Regexp, opts []client.Option, resumed bool,
+) (bool, error) {
+	stream, err := client.OpenStream(ctx, ses.Type, ses.Address, ses.Password, opts...)
+	if err != nil {
+		return false, fmt.Errorf("tail: %w", err)
+	}
+	defer stream.Close()
+
+	if resumed {
+		_, _ = fmt.Fprintf(executor.w, "--- reconnected to %s\n", ses.Address)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = stream.Close()
+		case <-done:
+		}
+	}()
+
+	for line := range stream.Lines() {
+		executor.tailLine(ses, grep, line)
+	}
+
+	if err = stream.Err(); err != nil {
+		return true, fmt.Errorf("tail: %w", err)
+	}
+
+	return true, nil
+}
+
+// backlog prints console lines newer than since which are kept by the
+// server. Only Rust Web RCON returns console history.
+func (executor *Executor) backlog(ses *config.Session, grep *regexp.Regexp, since time.Time) error {
+	if ses.Type != config.ProtocolWebRCON {
+		return ErrSinceUnsupported
+	}
+
+	if err := executor.Dial(ses); err != nil {
+		return err
+	}
+
+	response, err := executor.client.Execute(ConsoleTailCommand + " " + strconv.Itoa(DefaultTailBacklog))
+	if err != nil {
+		return fmt.Errorf("tail: %w", client.Classify(err))
+	}
+
+	var entries []consoleEntry
+	if err = json.Unmarshal([]byte(response), &entries); err != nil {
+		return fmt.Errorf("tail: invalid %s response: %w", ConsoleTailCommand, err)
+	}
+
+	for _, entry := range entries {
+		if time.Unix(entry.Time, 0).Before(since) {
+			continue
+		}
+
+		for _, line := range strings.Split(strings.TrimRight(entry.Message, "\n"), "\n") {
+			executor.tailLine(ses, grep, line)
+		}
+	}
+
+	return nil
+}
+
+// tailLine prints the console line if it matches grep. Alerts check all
+// lines, including not printed ones.
+func (executor *Executor) tailLine(ses *config.Session, grep *regexp.Regexp, line string) {
+	executor.alert(executor.w, ses, line)
+
+	if grep != nil && !grep.MatchString(line) {
+		return
+	}
+
+	_, _ = fmt.Fprintln(executor.w, executor.highlight(ses, line))
+}
@@ -0,0 +1,29 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// tee duplicates output of the executor to the file with the name if it is
+// set. The file is truncated like the tee utility does. Returned function
+// closes the file and restores the output.
+func (executor *Executor) tee(name string) (func(), error) {
+	if name == "" {
+		return func() {}, nil
+	}
+
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("tee: %w", err)
+	}
+
+	w := executor.w
+	executor.w = io.MultiWriter(w, file)
+
+	return func() {
+		executor.w = w
+		_ = file.Close()
+	}, nil
+}
@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// telnetOptions returns client options which open telnet connections of the
+// session with the prompt and custom login.
+func telnetOptions(ses *config.Session) ([]client.Option, error) {
+	var opts []client.Option
+
+	if ses.Prompt != "" {
+		prompt, err := regexp.Compile(ses.Prompt)
+		if err != nil {
+			return nil, fmt.Errorf("prompt: %w", err)
+		}
+
+		opts = append(opts, client.WithPrompt(prompt))
+	}
+
+	if len(ses.Login) != 0 {
+		steps := make([]client.LoginStep, 0, len(ses.Login))
+
+		for _, step := range ses.Login {
+			expect, err := regexp.Compile(step.Expect)
+			if err != nil {
+				return nil, fmt.Errorf("login: %w", err)
+			}
+
+			steps = append(steps, client.LoginStep{Expect: expect, Send: step.Send, Password: step.Password})
+		}
+
+		opts = append(opts, client.WithLogin(steps...))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/output"
+)
+
+// timedDialer measures TCP handshake of the connection opened by the client,
+// so dial and auth durations are told apart without extra connections.
+type timedDialer struct {
+	client.Dialer
+	dial time.Duration
+}
+
+// DialContext connects to the address and records the duration of the
+// handshake if the connection is established.
+func (d *timedDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	start := time.Now()
+
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err == nil {
+		d.dial = time.Since(start)
+	}
+
+	return conn, err //nolint:wrapcheck // Classified by client.
+}
+
+// duration returns the duration of the handshake. Returns zero if the
+// dialer is not set or the server is unreachable.
+func (d *timedDialer) duration() time.Duration {
+	if d == nil {
+		return 0
+	}
+
+	return d.dial
+}
+
+// takeTiming returns timing of the request with roundTrip duration. Dial
+// and auth durations of the new connection are reported only once.
+func (executor *Executor) takeTiming(roundTrip time.Duration) *output.Timing {
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
+	timing := executor.timing
+	if timing == nil {
+		timing = &output.Timing{}
+	}
+
+	executor.timing = nil
+	timing.RoundTrip = output.Milliseconds(roundTrip)
+
+	return timing
+}
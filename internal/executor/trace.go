@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon-cli/internal/trace"
+)
+
+// PcapngExt is the extension of trace file which is written in pcapng format.
+const PcapngExt = ".pcapng"
+
+// traceTo starts recording packets of new connections to the file with
+// the name if it is set. Files with PcapngExt extension are written in
+// pcapng format. Returned function stops recording and closes the file.
+func (executor *Executor) traceTo(name string) (func(), error) {
+	if name == "" {
+		return func() {}, nil
+	}
+
+	file, err := logger.OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	executor.tracer = trace.New(file)
+
+	if strings.HasSuffix(name, PcapngExt) {
+		if executor.tracer, err = trace.NewPcapng(file); err != nil {
+			_ = file.Close()
+
+			return nil, err
+		}
+	}
+
+	return func() {
+		// Relays write the last records when connections are closed.
+		_ = executor.Close()
+		_ = executor.tracer.Close()
+		executor.tracer = nil
+		_ = file.Close()
+	}, nil
+}
+
+// address returns the address to connect to. If tracing is enabled it is
+// the address of the local relay which connects to the server with dialer.
+func (executor *Executor) address(ses *config.Session, dialer *net.Dialer) (string, error) {
+	if executor.tracer == nil {
+		return ses.Address, nil
+	}
+
+	return executor.tracer.Relay(ses.Address, ses.Type, dialer)
+}
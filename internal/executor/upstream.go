@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// subscribeFunc subscribes the handler of the environment to a message
+// broker and returns the name of the topic on which commands are received.
+type subscribeFunc func(env string, handler func(command string) (string, error)) (string, error)
+
+// subscribeEnvs creates upstream executor for each environment of the env
+// flag and subscribes its handler. Upstream executors have no input, so
+// commands which require confirmation are cancelled. Returned function
+// closes upstream connections.
+func (executor *Executor) subscribeEnvs(c *cli.Context, subscribe subscribeFunc) (func(), error) {
+	upstreams := make([]*Executor, 0, len(c.StringSlice("env")))
+	w := &syncWriter{w: executor.w}
+
+	closeAll := func() {
+		for _, upstream := range upstreams {
+			_ = upstream.Close()
+		}
+	}
+
+	for _, env := range c.StringSlice("env") {
+		upstream := executor.child(nil, w)
+		upstreams = append(upstreams, upstream)
+
+		ses, err := upstream.switchEnv(c, env)
+		if err != nil {
+			closeAll()
+
+			return nil, fmt.Errorf("%s: %w", env, err)
+		}
+
+		var topic string
+		if topic, err = subscribe(env, executor.upstreamHandler(upstream, ses, env)); err != nil {
+			closeAll()
+
+			return nil, fmt.Errorf("%s: %w", env, err)
+		}
+
+		_, _ = fmt.Fprintf(executor.w, "Executing commands from %s to %s\n", topic, ses.Address)
+	}
+
+	return closeAll, nil
+}
+
+// upstreamHandler returns handler which logs and executes commands on the
+// server of the session. Commands of pipelined connections are executed
+// concurrently, others one by one. Commands are logged to the output of the
+// upstream which must be safe for concurrent use.
+func (executor *Executor) upstreamHandler(
+	upstream *Executor, ses *config.Session, env string,
+) func(command string) (string, error) {
+	var mu sync.Mutex
+
+	concurrent := pipelined(ses)
+
+	return func(command string) (string, error) {
+		if !concurrent {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+
+		_, _ = fmt.Fprintf(upstream.w, "%s %s %q\n", time.Now().Format(time.DateTime), env, command)
+
+		return upstream.request(ses, command)
+	}
+}
+
+// pipelined returns true if the connection of the session executes concurrent
+// commands over one socket.
+func pipelined(ses *config.Session) bool {
+	return ses.Type == config.ProtocolWebRCON
+}
+
+// syncWriter serializes writes of upstream executors which handle commands
+// concurrently to the shared output.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write implements io.Writer.
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w.Write(p) //nolint:wrapcheck // Errors of the output are returned as is.
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// WaitInterval is the delay between authentication attempts while waiting
+// for the server.
+const WaitInterval = 2 * time.Second
+
+// DefaultWaitTimeout is the default maximum time to wait for the server.
+const DefaultWaitTimeout = 10 * time.Minute
+
+// ErrWaitTimeout is returned when server state did not change in time.
+var ErrWaitTimeout = errors.New("wait timeout")
+
+// waitUp retries authentication until the server becomes reachable or
+// timeout expires.
+func (executor *Executor) waitUp(ses *config.Session, timeout time.Duration) error {
+	return executor.wait(ses, timeout, true)
+}
+
+// waitDown retries authentication until the server stops responding or
+// timeout expires.
+func (executor *Executor) waitDown(ses *config.Session, timeout time.Duration) error {
+	return executor.wait(ses, timeout, false)
+}
+
+func (executor *Executor) wait(ses *config.Session, timeout time.Duration, up bool) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		_ = executor.Close()
+
+		err := executor.Dial(ses)
+		if (err == nil) == up {
+			return nil
+		}
+
+		if time.Now().Add(WaitInterval).After(deadline) {
+			if up {
+				return fmt.Errorf("%w: server %s is not reachable: %s", ErrWaitTimeout, ses.Address, err)
+			}
+
+			_ = executor.Close()
+
+			return fmt.Errorf("%w: server %s is still running", ErrWaitTimeout, ses.Address)
+		}
+
+		time.Sleep(WaitInterval)
+	}
+}
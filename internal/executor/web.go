@@ -0,0 +1,143 @@
+package executor
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/gorcon/rcon-cli/client"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/secret"
+	"github.com/gorcon/rcon-cli/internal/tlspin"
+)
+
+// webOptions returns client options which open Web RCON connections of the
+// session with handshake headers, compression and heartbeat, through HTTP
+// proxy and over TLS.
+func webOptions(ses *config.Session) ([]client.Option, error) {
+	var opts []client.Option
+
+	if ses.HTTPProxy != "" {
+		proxy, err := url.Parse(ses.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("http proxy: %w", err)
+		}
+
+		opts = append(opts, client.WithProxy(proxy))
+	}
+
+	if len(ses.Headers) != 0 {
+		header := http.Header{}
+		for name, value := range ses.Headers {
+			header.Set(name, value)
+		}
+
+		opts = append(opts, client.WithHeader(header))
+	}
+
+	if ses.Compression {
+		opts = append(opts, client.WithCompression())
+	}
+
+	if ses.PingInterval > 0 {
+		opts = append(opts, client.WithHeartbeat(ses.PingInterval, ses.PongTimeout))
+	}
+
+	tlsConfig, err := tlsConfig(ses)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		opts = append(opts, client.WithTLS(tlsConfig))
+	}
+
+	return opts, nil
+}
+
+// tlsConfig returns TLS config of the session. Pinned fingerprint replaces
+// verification of the server certificate by certificate authorities, client
+// certificate is presented to servers which require it. Nil config is
+// returned if TLS is not enabled.
+func tlsConfig(ses *config.Session) (*tls.Config, error) {
+	if !ses.TLS && ses.TLSFingerprint == "" && ses.TLSCert == "" {
+		return nil, nil //nolint:nilnil // TLS is not enabled.
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if ses.TLSFingerprint != "" {
+		var err error
+		if cfg, err = tlspin.Config(ses.TLSFingerprint); err != nil {
+			return nil, err
+		}
+	}
+
+	if ses.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(ses.TLSCert, ses.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	// Server name is set explicitly, because connections can be dialed to
+	// the local address of the trace relay.
+	cfg.ServerName = ses.Address
+	if host, _, err := net.SplitHostPort(ses.Address); err == nil {
+		cfg.ServerName = host
+	}
+
+	return cfg, nil
+}
+
+// resolveHeaders returns copy of handshake headers with resolved references
+// to password manager secrets. Resolved secrets are redacted from output.
+func resolveHeaders(headers map[string]string) (map[string]string, error) {
+	if len(headers) == 0 {
+		return nil, nil //nolint:nilnil // No headers.
+	}
+
+	resolved := make(map[string]string, len(headers))
+
+	for name, value := range headers {
+		if !secret.IsReference(value) {
+			resolved[name] = value
+
+			continue
+		}
+
+		var err error
+		if resolved[name], err = secret.Resolve(value); err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+
+		redact.Add(resolved[name])
+	}
+
+	return resolved, nil
+}
+
+// maskHeaders returns copy of handshake headers with masked values, because
+// they usually contain tokens. References to password manager secrets are
+// not masked.
+func maskHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+
+	masked := make(map[string]string, len(headers))
+
+	for name, value := range headers {
+		masked[name] = value
+		if !secret.IsReference(value) {
+			masked[name] = MaskedPassword
+		}
+	}
+
+	return masked
+}
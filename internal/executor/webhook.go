@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/webhook"
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultWebhookListen is the address on which webhook requests are
+// accepted.
+const DefaultWebhookListen = ":8090"
+
+// ErrNoWebhooks is returned when the config has no webhook actions.
+var ErrNoWebhooks = errors.New("no webhooks in the config")
+
+// webhookCommand returns subcommand which runs config webhook actions on
+// HTTP requests.
+func (executor *Executor) webhookCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "webhook",
+		Usage: "Run commands of config webhook actions on authenticated HTTP POST /<action> requests",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to the configuration file",
+				Value:   config.DefaultConfigName,
+			},
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "Address on which requests are accepted",
+				Value: DefaultWebhookListen,
+			},
+		},
+		Action: executor.webhook,
+	}
+}
+
+// webhook serves webhook actions from the config. Commands policy and log of
+// the action environment are applied. Commands which require confirmation
+// are cancelled.
+func (executor *Executor) webhook(c *cli.Context) error {
+	file, err := config.NewFile(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if len(file.Webhooks) == 0 {
+		return ErrNoWebhooks
+	}
+
+	handlers := make(map[string]func(command string) (string, error))
+	upstreams := make([]*Executor, 0, len(file.Webhooks))
+	w := &syncWriter{w: executor.w}
+
+	defer func() {
+		for _, upstream := range upstreams {
+			_ = upstream.Close()
+		}
+	}()
+
+	for _, action := range file.Webhooks {
+		if _, ok := handlers[action.Env]; ok {
+			continue
+		}
+
+		// Upstream executor has no input, so confirmation is never given.
+		upstream := executor.child(nil, w)
+		upstreams = append(upstreams, upstream)
+
+		var ses *config.Session
+		if ses, err = upstream.switchEnv(c, action.Env); err != nil {
+			return fmt.Errorf("%s: %w", action.Env, err)
+		}
+
+		handlers[action.Env] = executor.upstreamHandler(upstream, ses, action.Env)
+	}
+
+	server := webhook.NewServer(file.Webhooks, func(env string, command string) (string, error) {
+		return handlers[env](command)
+	})
+
+	listener, err := net.Listen("tcp", c.String("listen"))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer listener.Close()
+
+	_, _ = fmt.Fprintf(executor.w, "Accepting webhooks on %s\n", listener.Addr())
+
+	return server.Serve(listener)
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/gorcon/rcon-cli/internal/zabbix"
+)
+
+// zabbixTo starts collecting values of trapper items if the address of
+// Zabbix server is set. Returned function sends collected values and stops
+// collecting. Errors of sending are printed, they do not fail the run.
+func (executor *Executor) zabbixTo(address string, timeout time.Duration) func() {
+	if address == "" {
+		return func() {}
+	}
+
+	executor.zabbix = &zabbix.Batch{}
+
+	return func() {
+		items := executor.zabbix.Items()
+		executor.zabbix = nil
+
+		if len(items) == 0 {
+			return
+		}
+
+		if _, err := zabbix.Send(address, items, timeout); err != nil {
+			_, _ = fmt.Fprintln(executor.w, redact.Error(err))
+		}
+	}
+}
+
+// collect adds values of the successful command to Zabbix items: latency
+// of the command, number of online players if it is the player list command
+// and numeric fields of the first record parsed with --parse. Keys are
+// rcon.latency[command], rcon.players and rcon.<field>.
+func (executor *Executor) collect(ses *config.Session, rec *output.Record, duration time.Duration) {
+	if executor.zabbix == nil || rec == nil {
+		return
+	}
+
+	host := zabbixHost(ses)
+	name, _, _ := strings.Cut(rec.Command, " ")
+
+	executor.zabbix.Add(host, zabbix.Key(zabbix.DefaultPrefix+".latency", name), number(duration.Seconds()))
+
+	if rec.Command == ses.PlayersCommand && ses.PlayersPattern != "" {
+		if re, err := player.Compile(ses.PlayersPattern); err == nil {
+			players := len(player.Parse(re, rec.Response))
+			executor.zabbix.Add(host, zabbix.DefaultPrefix+".players", strconv.Itoa(players))
+		}
+	}
+
+	if len(rec.Parsed) == 0 {
+		return
+	}
+
+	for _, field := range rec.Fields {
+		value := strings.TrimSpace(rec.Parsed[0][field])
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			executor.zabbix.Add(host, zabbix.DefaultPrefix+"."+field, value)
+		}
+	}
+}
+
+// zabbixHost returns the host name of Zabbix items of the session.
+func zabbixHost(ses *config.Session) string {
+	switch {
+	case ses.ZabbixHost != "":
+		return ses.ZabbixHost
+	case ses.Env != "":
+		return ses.Env
+	}
+
+	host, _, err := net.SplitHostPort(ses.Address)
+	if err != nil {
+		return ses.Address
+	}
+
+	return host
+}
+
+// number formats the value without trailing zeros.
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
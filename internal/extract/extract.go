@@ -0,0 +1,120 @@
+// Package extract pulls single values out of JSON responses with jq-style
+// paths like `.Players[0].Name`.
+package extract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPath is returned when path has wrong syntax.
+	ErrInvalidPath = errors.New("invalid path")
+
+	// ErrNotFound is returned when path does not exist in the document.
+	ErrNotFound = errors.New("path not found")
+
+	// ErrNotJSON is returned when response is not a valid JSON document.
+	ErrNotJSON = errors.New("response is not json")
+)
+
+// Extract returns value from JSON document data by path. Strings are
+// returned as is, other values are returned in JSON encoding. Numbers are
+// kept as written, so large IDs like Steam IDs are not rounded.
+func Extract(data string, path string) (string, error) {
+	keys, err := split(path)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range keys {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = node[key]; !ok {
+				return "", fmt.Errorf("%w: %s", ErrNotFound, path)
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", fmt.Errorf("%w: %s", ErrNotFound, path)
+			}
+
+			value = node[i]
+		default:
+			return "", fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	js, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	return string(js), nil
+}
+
+// decode parses the JSON document with numbers as json.Number.
+func decode(data string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotJSON, err)
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: data after the document", ErrNotJSON)
+	}
+
+	return value, nil
+}
+
+// split converts path like `.Players[0].Name` to keys `Players`, `0`, `Name`.
+func split(path string) ([]string, error) {
+	if !strings.HasPrefix(path, ".") {
+		return nil, fmt.Errorf("%w: %q must start with a dot", ErrInvalidPath, path)
+	}
+
+	keys := make([]string, 0)
+	rest := path[1:]
+
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q has unclosed bracket", ErrInvalidPath, path)
+			}
+
+			keys = append(keys, strings.Trim(rest[1:end], `"`))
+			rest = rest[end+1:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			keys = append(keys, rest[:end])
+			rest = rest[end:]
+		}
+	}
+
+	return keys, nil
+}
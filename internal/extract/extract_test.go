@@ -0,0 +1,66 @@
+package extract_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/extract"
+	"github.com/stretchr/testify/assert"
+)
+
+const serverInfo = `{"Hostname": "Rust Server [DOCKER]", "MaxPlayers": 500, ` +
+	`"Players": [{"Name": "admin", "Ping": 15}], "Tags": {"game mode": "vanilla"}}`
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"whole document", ".", `{"Hostname":"Rust Server [DOCKER]","MaxPlayers":500,` +
+			`"Players":[{"Name":"admin","Ping":15}],"Tags":{"game mode":"vanilla"}}`},
+		{"string field", ".Hostname", "Rust Server [DOCKER]"},
+		{"number field", ".MaxPlayers", "500"},
+		{"array item", ".Players[0].Name", "admin"},
+		{"array item with dot", ".Players.[0].Ping", "15"},
+		{"quoted key", `.Tags["game mode"]`, "vanilla"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := extract.Extract(serverInfo, tt.path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+
+	t.Run("steam id", func(t *testing.T) {
+		value, err := extract.Extract(`{"Players": [{"SteamID": 76561198000000001, "Health": 87.5}]}`, ".Players[0].SteamID")
+		assert.NoError(t, err)
+		assert.Equal(t, "76561198000000001", value)
+
+		value, err = extract.Extract(`{"Players": [{"SteamID": 76561198000000001, "Health": 87.5}]}`, ".Players[0]")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"Health":87.5,"SteamID":76561198000000001}`, value)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := extract.Extract(serverInfo, ".Players[1]")
+		assert.ErrorIs(t, err, extract.ErrNotFound)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := extract.Extract(serverInfo, "Hostname")
+		assert.ErrorIs(t, err, extract.ErrInvalidPath)
+
+		_, err = extract.Extract(serverInfo, ".Players[0")
+		assert.ErrorIs(t, err, extract.ErrInvalidPath)
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		_, err := extract.Extract("hostname: Rust Server", ".Hostname")
+		assert.ErrorIs(t, err, extract.ErrNotJSON)
+
+		_, err = extract.Extract(`{"Hostname": "Rust"} {}`, ".Hostname")
+		assert.ErrorIs(t, err, extract.ErrNotJSON)
+	})
+}
@@ -0,0 +1,200 @@
+// Package fuzz sends malformed and boundary Source RCON packets to a server
+// to help authors of server plugins harden their RCON implementations.
+package fuzz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// Packet types which are not defined by the protocol.
+const (
+	typeUnknown  int32 = 99
+	typeNegative int32 = -1
+)
+
+// burstSize is the number of packets in the burst case.
+const burstSize = 100
+
+// ErrAuthFailed is returned when authentication before the case failed.
+var ErrAuthFailed = errors.New("authentication failed")
+
+// Case is a payload which is sent to the server.
+type Case struct {
+	Name string
+	// Auth means the payload is sent on authenticated connection.
+	Auth    bool
+	Payload []byte
+}
+
+// Result is the reaction of the server to the case.
+type Result struct {
+	Name string
+	// Bytes is the number of bytes received in response to the payload.
+	Bytes int
+	// Closed means the server closed the connection.
+	Closed bool
+	// Alive means the server accepted a new connection after the case.
+	Alive bool
+	Err   error
+}
+
+// String describes the reaction of the server.
+func (r Result) String() string {
+	var reaction string
+
+	switch {
+	case r.Err != nil:
+		reaction = r.Err.Error()
+	case r.Bytes != 0 && r.Closed:
+		reaction = fmt.Sprintf("responded with %d bytes and closed connection", r.Bytes)
+	case r.Bytes != 0:
+		reaction = fmt.Sprintf("responded with %d bytes", r.Bytes)
+	case r.Closed:
+		reaction = "closed connection"
+	default:
+		reaction = "no response"
+	}
+
+	if !r.Alive {
+		reaction += ", server stopped responding"
+	}
+
+	return reaction
+}
+
+// Packet encodes packet with declared size which can differ from the real
+// length of body. Body is written as is without terminators.
+func Packet(size int32, id int32, typ int32, body []byte) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, len(body)+12)) //nolint:gomnd // Size, ID and type fields.
+
+	_ = binary.Write(buf, binary.LittleEndian, size)
+	_ = binary.Write(buf, binary.LittleEndian, id)
+	_ = binary.Write(buf, binary.LittleEndian, typ)
+	buf.Write(body)
+
+	return buf.Bytes()
+}
+
+// valid encodes well-formed packet with the body.
+func valid(id int32, typ int32, body string) []byte {
+	return Packet(int32(len(body))+rcon.MinPacketSize, id, typ, append([]byte(body), 0x00, 0x00))
+}
+
+// Cases returns all fuzzing cases.
+func Cases() []Case {
+	body := []byte("status")
+	command := valid(1, rcon.SERVERDATA_EXECCOMMAND, string(body))
+	oversized := string(bytes.Repeat([]byte("a"), int(rcon.MaxPacketSize)))
+	huge := string(bytes.Repeat([]byte("a"), 1<<20)) //nolint:gomnd // 1 MiB.
+
+	return []Case{
+		{Name: "zero size", Payload: Packet(0, 1, rcon.SERVERDATA_AUTH, nil)},
+		{Name: "negative size", Payload: Packet(-1, 1, rcon.SERVERDATA_AUTH, []byte{0x00, 0x00})},
+		{Name: "too small size", Payload: Packet(rcon.MinPacketSize-1, 1, rcon.SERVERDATA_AUTH, []byte{0x00, 0x00})},
+		{Name: "huge size", Payload: Packet(math.MaxInt32, 1, rcon.SERVERDATA_AUTH, []byte{0x00, 0x00})},
+		{Name: "truncated header", Payload: command[:6]},
+		{Name: "truncated body", Payload: command[:len(command)-4]},
+		{Name: "missing terminators", Payload: Packet(rcon.PacketHeaderSize+6, 1, rcon.SERVERDATA_EXECCOMMAND, body)},
+		{Name: "exec before auth", Payload: command},
+		{Name: "empty password", Payload: valid(1, rcon.SERVERDATA_AUTH, "")},
+		{Name: "oversized body", Auth: true, Payload: valid(1, rcon.SERVERDATA_EXECCOMMAND, oversized)},
+		{Name: "huge body", Auth: true, Payload: valid(1, rcon.SERVERDATA_EXECCOMMAND, huge)},
+		{Name: "unknown type", Auth: true, Payload: valid(1, typeUnknown, "status")},
+		{Name: "negative type", Auth: true, Payload: valid(1, typeNegative, "status")},
+		{Name: "negative id", Auth: true, Payload: valid(-1, rcon.SERVERDATA_EXECCOMMAND, "status")},
+		{Name: "invalid utf-8", Auth: true, Payload: valid(1, rcon.SERVERDATA_EXECCOMMAND, "\xff\xfe\xfd")},
+		{Name: "nul in body", Auth: true, Payload: valid(1, rcon.SERVERDATA_EXECCOMMAND, "sta\x00tus")},
+		{Name: "burst", Auth: true, Payload: bytes.Repeat(command, burstSize)},
+	}
+}
+
+// Run sends the case payload to the server and reads the response until
+// the connection is closed or timeout is elapsed. Then it checks whether
+// the server is still alive.
+func Run(address string, password string, c Case, timeout time.Duration) Result {
+	result := Result{Name: c.Name}
+	result.Bytes, result.Closed, result.Err = send(address, password, c, timeout)
+	result.Alive = alive(address, password, timeout)
+
+	return result
+}
+
+// send writes payload and counts bytes of the response.
+func send(address string, password string, c Case, timeout time.Duration) (int, bool, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return 0, false, fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	if c.Auth {
+		if err = auth(conn, password, timeout); err != nil {
+			return 0, false, err
+		}
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err = conn.Write(c.Payload); err != nil {
+		// The server can close the connection before the whole payload
+		// is written, it is a valid reaction.
+		return 0, true, nil //nolint:nilerr // Closed connection is the result.
+	}
+
+	// Deadline means the server keeps the connection open, any other result
+	// means the connection was closed or reset.
+	n, err := io.Copy(io.Discard, conn)
+
+	return int(n), !errors.Is(err, os.ErrDeadlineExceeded), nil
+}
+
+// auth authenticates the connection with well-formed packet.
+func auth(conn net.Conn, password string, timeout time.Duration) error {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := rcon.NewPacket(rcon.SERVERDATA_AUTH, 1, password).WriteTo(conn); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+
+	// Server can send empty SERVERDATA_RESPONSE_VALUE before auth response.
+	for i := 0; i < 2; i++ {
+		packet := rcon.Packet{}
+		if _, err := packet.ReadFrom(conn); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+
+		if packet.Type != rcon.SERVERDATA_AUTH_RESPONSE {
+			continue
+		}
+
+		if packet.ID == -1 {
+			return ErrAuthFailed
+		}
+
+		return nil
+	}
+
+	return ErrAuthFailed
+}
+
+// alive returns true if the server accepts authenticated connection.
+func alive(address string, password string, timeout time.Duration) bool {
+	conn, err := rcon.Dial(address, password, rcon.SetDialTimeout(timeout), rcon.SetDeadline(timeout))
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
@@ -0,0 +1,110 @@
+package fuzz_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/fuzz"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve is a strict RCON server which closes connection on malformed packets.
+func serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+
+			for {
+				var size int32
+				if err := binary.Read(conn, binary.LittleEndian, &size); err != nil {
+					return
+				}
+
+				if size < rcon.MinPacketSize || size > rcon.MaxPacketSize {
+					return
+				}
+
+				body := make([]byte, size)
+				if _, err := io.ReadFull(conn, body); err != nil {
+					return
+				}
+
+				if !bytes.HasSuffix(body, []byte{0x00, 0x00}) {
+					return
+				}
+
+				id := int32(binary.LittleEndian.Uint32(body[0:4]))
+				typ := int32(binary.LittleEndian.Uint32(body[4:8]))
+				text := string(body[8 : len(body)-2])
+
+				switch typ {
+				case rcon.SERVERDATA_AUTH:
+					if text != "password" {
+						id = -1
+					}
+
+					_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, id, "").WriteTo(conn)
+				case rcon.SERVERDATA_EXECCOMMAND:
+					_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, id, "ok").WriteTo(conn)
+				default:
+					return
+				}
+			}
+		}(conn)
+	}
+}
+
+func TestPacket(t *testing.T) {
+	assert.Equal(t, []byte{10, 0, 0, 0, 1, 0, 0, 0, 3, 0, 0, 0, 0, 0}, fuzz.Packet(10, 1, 3, []byte{0, 0}))
+}
+
+func TestRun(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	go serve(listener)
+
+	const timeout = 100 * time.Millisecond
+
+	results := map[string]fuzz.Result{}
+	for _, c := range fuzz.Cases() {
+		result := fuzz.Run(listener.Addr().String(), "password", c, timeout)
+		assert.True(t, result.Alive, c.Name)
+		assert.NoError(t, result.Err, c.Name)
+
+		results[c.Name] = result
+	}
+
+	assert.Len(t, results, len(fuzz.Cases()), "names must be unique")
+	assert.Equal(t, "closed connection", results["zero size"].String())
+	assert.Equal(t, "closed connection", results["unknown type"].String())
+	assert.Equal(t, "no response", results["truncated body"].String())
+	assert.Equal(t, 100*16, results["burst"].Bytes)
+
+	t.Run("auth failed", func(t *testing.T) {
+		result := fuzz.Run(listener.Addr().String(), "wrong", fuzz.Case{Name: "auth", Auth: true}, timeout)
+		assert.ErrorIs(t, result.Err, fuzz.ErrAuthFailed)
+		assert.False(t, result.Alive)
+	})
+
+	t.Run("server down", func(t *testing.T) {
+		down, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		down.Close()
+
+		result := fuzz.Run(down.Addr().String(), "password", fuzz.Cases()[0], timeout)
+		assert.Error(t, result.Err)
+		assert.Contains(t, result.String(), "server stopped responding")
+	})
+}
@@ -0,0 +1,120 @@
+// Package game contains presets which bundle the protocol, the default port
+// and quirks of popular games.
+package game
+
+import (
+	"net"
+	"sort"
+	"strconv"
+)
+
+// Preset contains connection settings and quirks of the game.
+type Preset struct {
+	// Protocol is the protocol type of the game server.
+	Protocol string
+	// Port is the default RCON port of the game server.
+	Port int
+	// Colors is true if responses contain color codes.
+	Colors bool
+	// SayCommand is the command which broadcasts messages to players.
+	SayCommand string
+	// Semicolons is true if the server executes semicolon separated
+	// commands one by one, so too long commands can be split.
+	Semicolons bool
+	// Fragmented is true if the server splits long responses into several
+	// packets without the end marker, so fragments must be collected.
+	Fragmented bool
+	// HelpCommand is the command which lists commands of the server.
+	HelpCommand string
+	// Commands is the dictionary of frequently used commands.
+	Commands []string
+	// UnknownCommand is the regular expression which matches responses of
+	// the server to unknown commands.
+	UnknownCommand string
+	// Dictionary is the name of the bundled command dictionary with syntax
+	// hints, see dictionary.Game.
+	Dictionary string
+	// PlayersCommand is the command which lists online players. Players is
+	// the regular expression of the player in its response with named
+	// groups id and name, see player package.
+	PlayersCommand string
+	Players        string
+}
+
+// presets contains supported games.
+//
+//nolint:gochecknoglobals // Read only dictionary of games.
+var presets = map[string]Preset{
+	"minecraft": {
+		Protocol: "rcon", Port: 25575, Colors: true, SayCommand: "say", Fragmented: true, HelpCommand: "help",
+		PlayersCommand: "list", Players: `(?:: |, )(?P<name>[^,\s]+)`,
+		Dictionary: "minecraft", UnknownCommand: `Unknown or incomplete command|Unknown command`,
+		Commands: []string{"list", "say", "save-all", "stop", "kick", "ban", "whitelist add", "op", "time set"},
+	},
+	"rust": {
+		Protocol: "web", Port: 28016, Colors: true, SayCommand: "say", HelpCommand: "find .",
+		PlayersCommand: "playerlist", Players: `"SteamID":\s*"(?P<id>\d+)"[^}]*?"DisplayName":\s*"(?P<name>[^"]*)"`,
+		Dictionary: "rust", UnknownCommand: `Command '.*' not found`,
+		Commands: []string{"status", "serverinfo", "playerlist", "say", "kick", "ban", "server.save", "quit"},
+	},
+	"csgo": {
+		Protocol: "rcon", Port: 27015, SayCommand: "say", Semicolons: true, HelpCommand: "cmdlist",
+		PlayersCommand: "status", Players: `(?m)^#\s+(?P<id>\d+)\s+(?:\d+\s+)?"(?P<name>[^"]*)"`,
+		Dictionary: "csgo", UnknownCommand: `Unknown command`,
+		Commands: []string{"status", "users", "say", "kick", "banid", "changelevel", "mp_restartgame", "exec"},
+	},
+	"cs2": {
+		Protocol: "rcon", Port: 27015, SayCommand: "say", Semicolons: true, HelpCommand: "cmdlist",
+		PlayersCommand: "status", Players: `(?m)^#\s+(?P<id>\d+)\s+(?:\d+\s+)?"(?P<name>[^"]*)"`,
+		Dictionary: "csgo", UnknownCommand: `Unknown command`,
+		Commands: []string{"status", "users", "say", "kick", "banid", "changelevel", "mp_restartgame", "exec"},
+	},
+	"ark": {
+		Protocol: "rcon", Port: 27020, SayCommand: "ServerChat", Dictionary: "ark",
+		PlayersCommand: "ListPlayers", Players: `(?m)^\d+\. (?P<name>.+), (?P<id>\d+)\s*$`,
+		Commands: []string{"ListPlayers", "ServerChat", "Broadcast", "KickPlayer", "BanPlayer", "SaveWorld", "DoExit"},
+	},
+	"7dtd": {
+		Protocol: "telnet", Port: 8081, Colors: true, SayCommand: "say", HelpCommand: "help",
+		PlayersCommand: "listplayers", Players: `id=(?P<id>\d+), (?P<name>[^,]+),`,
+		Dictionary: "7dtd", UnknownCommand: `(?i)unknown command`,
+		Commands: []string{"version", "listplayers", "gettime", "say", "kick", "ban add", "saveworld", "shutdown"},
+	},
+	"factorio": {
+		Protocol: "rcon", Port: 27015, SayCommand: "/shout", HelpCommand: "/help", UnknownCommand: `Unknown command`,
+		PlayersCommand: "/players online", Players: `(?m)^\s+(?P<name>\S+)(?: \(online\))?\s*$`,
+		Commands: []string{"/players", "/version", "/time", "/evolution", "/shout", "/kick", "/ban", "/save", "/quit"},
+	},
+}
+
+// Lookup returns the preset of the game.
+func Lookup(name string) (Preset, bool) {
+	preset, ok := presets[name]
+
+	return preset, ok
+}
+
+// Names returns sorted names of supported games.
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Address adds the default port of the game to address without port.
+func (p Preset) Address(address string) string {
+	if address == "" {
+		return address
+	}
+
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+
+	return net.JoinHostPort(address, strconv.Itoa(p.Port))
+}
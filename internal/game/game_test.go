@@ -0,0 +1,104 @@
+package game_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/game"
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	preset, ok := game.Lookup("rust")
+	assert.True(t, ok)
+	assert.Equal(t, "web", preset.Protocol)
+	assert.Equal(t, 28016, preset.Port)
+	assert.False(t, preset.Fragmented)
+
+	preset, _ = game.Lookup("minecraft")
+	assert.True(t, preset.Fragmented)
+
+	_, ok = game.Lookup("tetris")
+	assert.False(t, ok)
+
+	for _, name := range game.Names() {
+		preset, _ := game.Lookup(name)
+		_, err := regexp.Compile(preset.UnknownCommand)
+		assert.NoError(t, err, name)
+	}
+
+	assert.Equal(t, []string{"7dtd", "ark", "cs2", "csgo", "factorio", "minecraft", "rust"}, game.Names())
+}
+
+func TestPreset_Players(t *testing.T) {
+	tests := map[string]struct {
+		response string
+		want     []player.Player
+	}{
+		"minecraft": {
+			response: "There are 2 of a max of 20 players online: Alice, Bob_2",
+			want:     []player.Player{{ID: "Alice", Name: "Alice"}, {ID: "Bob_2", Name: "Bob_2"}},
+		},
+		"rust": {
+			response: `[{"SteamID": "76561198000000001", "OwnerSteamID": "0", "DisplayName": "Alice", "Ping": 40}]`,
+			want:     []player.Player{{ID: "76561198000000001", Name: "Alice"}},
+		},
+		"csgo": {
+			response: "# userid name uniqueid connected ping loss state rate adr\n" +
+				"#  2 1 \"Alice Smith\" STEAM_1:0:1234 05:12 40 0 active 196608 10.0.0.2:27005\n#end",
+			want: []player.Player{{ID: "2", Name: "Alice Smith"}},
+		},
+		"cs2": {
+			response: "# userid name uniqueid connected ping loss state adr\n" +
+				"#      3 \"Bob\" [U:1:5678] 00:31 62 1 active 10.0.0.3:27005",
+			want: []player.Player{{ID: "3", Name: "Bob"}},
+		},
+		"ark": {
+			response: "0. Alice Smith, 76561198000000001 \n1. Bob, 76561198000000002",
+			want: []player.Player{
+				{ID: "76561198000000001", Name: "Alice Smith"}, {ID: "76561198000000002", Name: "Bob"},
+			},
+		},
+		"7dtd": {
+			response: "1. id=171, Alice, pos=(1.0, 2.0, 3.0), rot=(0.0, 0.0, 0.0), remote=True\nTotal of 1 in the game",
+			want:     []player.Player{{ID: "171", Name: "Alice"}},
+		},
+		"factorio": {
+			response: "Online players (2):\n  Alice (online)\n  Bob (online)",
+			want:     []player.Player{{ID: "Alice", Name: "Alice"}, {ID: "Bob", Name: "Bob"}},
+		},
+	}
+
+	for _, name := range game.Names() {
+		t.Run(name, func(t *testing.T) {
+			preset, _ := game.Lookup(name)
+			assert.NotEmpty(t, preset.PlayersCommand)
+
+			re, err := player.Compile(preset.Players)
+			assert.NoError(t, err)
+			assert.Equal(t, tests[name].want, player.Parse(re, tests[name].response))
+		})
+	}
+}
+
+func TestPreset_Address(t *testing.T) {
+	preset, _ := game.Lookup("minecraft")
+
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"", ""},
+		{"127.0.0.1", "127.0.0.1:25575"},
+		{"mc.example.com", "mc.example.com:25575"},
+		{"127.0.0.1:25576", "127.0.0.1:25576"},
+		{"::1", "[::1]:25575"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			assert.Equal(t, test.want, preset.Address(test.address))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package highlight colors parts of responses which match regular
+// expressions, so errors, joins and bans stand out in large outputs.
+package highlight
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownColor is returned when the rule has unsupported color.
+var ErrUnknownColor = errors.New("unknown color")
+
+// colors contains ANSI color codes by names.
+//
+//nolint:gochecknoglobals // Read only dictionary of colors.
+var colors = map[string]string{
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"bold":    "1",
+}
+
+// reset is ANSI sequence which resets the color.
+const reset = "\x1b[0m"
+
+// Rule maps the regular expression to the color of matched text.
+type Rule struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Color   string `json:"color" yaml:"color"`
+}
+
+// Highlighter colors matches of rules. Rules are applied in order, the
+// earlier rule wins if matches overlap. Methods of nil Highlighter return
+// text as is.
+type Highlighter struct {
+	patterns []*regexp.Regexp
+	codes    []string
+}
+
+// New compiles rules. Returns nil Highlighter if rules are empty.
+func New(rules []Rule) (*Highlighter, error) {
+	if len(rules) == 0 {
+		return nil, nil //nolint:nilnil // Nil Highlighter does nothing.
+	}
+
+	h := &Highlighter{}
+
+	for _, rule := range rules {
+		code, ok := colors[strings.ToLower(rule.Color)]
+		if !ok {
+			names := Colors()
+
+			return nil, fmt.Errorf("%w: %s: use %s or %s", ErrUnknownColor, rule.Color,
+				strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+		}
+
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("highlight: %w", err)
+		}
+
+		h.patterns = append(h.patterns, pattern)
+		h.codes = append(h.codes, code)
+	}
+
+	return h, nil
+}
+
+// Colors returns sorted names of supported colors.
+func Colors() []string {
+	names := make([]string, 0, len(colors))
+	for name := range colors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Apply returns text with matches of rules wrapped into ANSI color
+// sequences. Colors are reset at line breaks, so prefixes added to lines
+// later are not colored.
+func (h *Highlighter) Apply(text string) string {
+	if h == nil || text == "" {
+		return text
+	}
+
+	// painted contains index of the rule which colors the byte, -1 if none.
+	painted := make([]int, len(text))
+	for i := range painted {
+		painted[i] = -1
+	}
+
+	for rule, pattern := range h.patterns {
+		for _, match := range pattern.FindAllStringIndex(text, -1) {
+			for i := match[0]; i < match[1]; i++ {
+				if painted[i] == -1 && text[i] != '\n' {
+					painted[i] = rule
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+
+	current := -1
+
+	for i := 0; i < len(text); i++ {
+		if painted[i] != current {
+			if current != -1 {
+				b.WriteString(reset)
+			}
+
+			if painted[i] != -1 {
+				b.WriteString("\x1b[" + h.codes[painted[i]] + "m")
+			}
+
+			current = painted[i]
+		}
+
+		b.WriteByte(text[i])
+	}
+
+	if current != -1 {
+		b.WriteString(reset)
+	}
+
+	return b.String()
+}
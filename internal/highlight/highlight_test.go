@@ -0,0 +1,71 @@
+package highlight_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/highlight"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	h, err := highlight.New(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, h)
+
+	_, err = highlight.New([]highlight.Rule{{Pattern: "error", Color: "purple"}})
+	assert.ErrorIs(t, err, highlight.ErrUnknownColor)
+	assert.EqualError(t, err, "unknown color: purple: use blue, bold, cyan, green, magenta, red, white or yellow")
+
+	_, err = highlight.New([]highlight.Rule{{Pattern: "(", Color: "red"}})
+	assert.Error(t, err)
+}
+
+func TestHighlighter_Apply(t *testing.T) {
+	h, err := highlight.New([]highlight.Rule{
+		{Pattern: `(?i)error`, Color: "red"},
+		{Pattern: `joined|error code`, Color: "Green"},
+		{Pattern: `banned`, Color: "yellow"},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "no matches",
+			text: "Players: 0",
+			want: "Players: 0",
+		},
+		{
+			name: "several rules",
+			text: "Bob joined\nAlice banned",
+			want: "Bob \x1b[32mjoined\x1b[0m\nAlice \x1b[33mbanned\x1b[0m",
+		},
+		{
+			name: "earlier rule wins",
+			text: "Error code 5",
+			want: "\x1b[31mError\x1b[0m code 5",
+		},
+		{
+			name: "overlap continues with the next rule",
+			text: "error code 5",
+			want: "\x1b[31merror\x1b[0m\x1b[32m code\x1b[0m 5",
+		},
+		{
+			name: "colors are reset at line breaks",
+			text: "ERROR\nerror",
+			want: "\x1b[31mERROR\x1b[0m\n\x1b[31merror\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, h.Apply(test.text))
+		})
+	}
+
+	var nilHighlighter *highlight.Highlighter
+	assert.Equal(t, "error", nilHighlighter.Apply("error"))
+}
@@ -0,0 +1,103 @@
+package hook
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/gorcon/rcon-cli/internal/alias"
+)
+
+// Environment variables which are passed to hook programs.
+const (
+	EnvAddress  = "RCON_ADDRESS"
+	EnvCommand  = "RCON_COMMAND"
+	EnvResponse = "RCON_RESPONSE"
+	EnvError    = "RCON_ERROR"
+)
+
+// ErrEmptyProgram is returned when hook program is empty.
+var ErrEmptyProgram = errors.New("hook program is not set")
+
+// Exec runs local programs before and after commands are sent.
+type Exec struct {
+	w    io.Writer
+	pre  [][]string
+	post [][]string
+}
+
+// NewExec creates a new Exec. Each hook is a program with arguments which
+// are split by spaces except quoted ones. Output of programs is written to w.
+func NewExec(pre []string, post []string, w io.Writer) (*Exec, error) {
+	e := Exec{w: w}
+
+	for _, program := range pre {
+		args := alias.Split(program)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("pre %w", ErrEmptyProgram)
+		}
+
+		e.pre = append(e.pre, args)
+	}
+
+	for _, program := range post {
+		args := alias.Split(program)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("post %w", ErrEmptyProgram)
+		}
+
+		e.post = append(e.post, args)
+	}
+
+	return &e, nil
+}
+
+// Pre runs pre hooks with the command in environment variables. Returns
+// an error if any program failed, so the command must not be sent.
+func (e *Exec) Pre(address string, command string) error {
+	if e == nil {
+		return nil
+	}
+
+	env := []string{EnvAddress + "=" + address, EnvCommand + "=" + command}
+
+	for _, args := range e.pre {
+		if err := e.run(args, env); err != nil {
+			return fmt.Errorf("pre hook %s: %w", args[0], err)
+		}
+	}
+
+	return nil
+}
+
+// Post runs post hooks with the command, the response and the error text in
+// environment variables.
+func (e *Exec) Post(address string, command string, response string, errText string) error {
+	if e == nil {
+		return nil
+	}
+
+	env := []string{
+		EnvAddress + "=" + address, EnvCommand + "=" + command,
+		EnvResponse + "=" + response, EnvError + "=" + errText,
+	}
+
+	for _, args := range e.post {
+		if err := e.run(args, env); err != nil {
+			return fmt.Errorf("post hook %s: %w", args[0], err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Exec) run(args []string, env []string) error {
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // Programs are set by user in config.
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = e.w
+	cmd.Stderr = e.w
+
+	return cmd.Run()
+}
@@ -0,0 +1,144 @@
+package hook_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/hook"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+func createFile(t *testing.T, name string, content string) {
+	t.Helper()
+
+	assert.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+	t.Cleanup(func() { os.Remove(name) })
+}
+
+func TestJS(t *testing.T) {
+	var sent []string
+
+	execute := func(command string) (string, error) {
+		sent = append(sent, command)
+
+		return "ok", nil
+	}
+
+	createFile(t, "test-hook-rewrite.js", `
+		function beforeSend(command) {
+			if (command === "kickall") return false;
+			if (command.startsWith("p")) return "players";
+		}
+		function afterResponse(command, response) {
+			return response.toUpperCase();
+		}
+		function onError(command, error) {
+			print("failed", command, error, execute("say " + error));
+		}
+	`)
+	createFile(t, "test-hook-empty.js", `var x = 1;`)
+
+	w := bytes.Buffer{}
+
+	js, err := hook.NewJS([]string{"test-hook-rewrite.js", "test-hook-empty.js"}, &w, execute)
+	assert.NoError(t, err)
+
+	t.Run("before send", func(t *testing.T) {
+		command, err := js.BeforeSend("p")
+		assert.NoError(t, err)
+		assert.Equal(t, "players", command)
+
+		command, err = js.BeforeSend("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "help", command)
+
+		_, err = js.BeforeSend("kickall")
+		assert.ErrorIs(t, err, hook.ErrCancelled)
+	})
+
+	t.Run("after response", func(t *testing.T) {
+		response, err := js.AfterResponse("help", "Can I help you?")
+		assert.NoError(t, err)
+		assert.Equal(t, "CAN I HELP YOU?", response)
+	})
+
+	t.Run("on error", func(t *testing.T) {
+		err := js.OnError("unknown", errUnknownCommand)
+		assert.NoError(t, err)
+		assert.Equal(t, "failed unknown unknown command ok\n", w.String())
+		assert.Equal(t, []string{"say unknown command"}, sent)
+	})
+
+	t.Run("nil hooks", func(t *testing.T) {
+		var empty *hook.JS
+
+		command, err := empty.BeforeSend("help")
+		assert.NoError(t, err)
+		assert.Equal(t, "help", command)
+	})
+
+	t.Run("exception", func(t *testing.T) {
+		createFile(t, "test-hook-throw.js", `function beforeSend(command) { throw new Error("boom"); }`)
+
+		throw, err := hook.NewJS([]string{"test-hook-throw.js"}, &w, execute)
+		assert.NoError(t, err)
+
+		_, err = throw.BeforeSend("help")
+		assert.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		_, err := hook.NewJS([]string{"nonexistent.js"}, &w, execute)
+		assert.Error(t, err)
+
+		createFile(t, "test-hook-syntax.js", `function (`)
+
+		_, err = hook.NewJS([]string{"test-hook-syntax.js"}, &w, execute)
+		assert.Error(t, err)
+	})
+}
+
+func TestExec(t *testing.T) {
+	t.Run("pre and post", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		e, err := hook.NewExec(
+			[]string{`sh -c "echo pre $RCON_ADDRESS $RCON_COMMAND"`},
+			[]string{`sh -c "echo post $RCON_COMMAND $RCON_RESPONSE $RCON_ERROR"`},
+			&w,
+		)
+		assert.NoError(t, err)
+
+		err = e.Pre("127.0.0.1:16260", "help")
+		assert.NoError(t, err)
+
+		err = e.Post("127.0.0.1:16260", "help", "Can I help you?", "")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "pre 127.0.0.1:16260 help\npost help Can I help you?\n", w.String())
+	})
+
+	t.Run("failed pre", func(t *testing.T) {
+		e, err := hook.NewExec([]string{"false"}, nil, &bytes.Buffer{})
+		assert.NoError(t, err)
+
+		err = e.Pre("127.0.0.1:16260", "help")
+		assert.ErrorContains(t, err, "pre hook false")
+	})
+
+	t.Run("empty program", func(t *testing.T) {
+		_, err := hook.NewExec(nil, []string{" "}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, hook.ErrEmptyProgram)
+	})
+
+	t.Run("nil hooks", func(t *testing.T) {
+		var e *hook.Exec
+
+		assert.NoError(t, e.Pre("127.0.0.1:16260", "help"))
+		assert.NoError(t, e.Post("127.0.0.1:16260", "help", "", ""))
+	})
+}
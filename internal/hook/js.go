@@ -0,0 +1,169 @@
+// Package hook invokes user defined hooks on command events.
+package hook
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+// JavaScript hook functions which are called on command events.
+const (
+	// FuncBeforeSend is called with the command before it is sent. It can
+	// return a string to rewrite the command or false to cancel it.
+	FuncBeforeSend = "beforeSend"
+
+	// FuncAfterResponse is called with the command and the response. It can
+	// return a string to rewrite the response.
+	FuncAfterResponse = "afterResponse"
+
+	// FuncOnError is called with the command and the error message.
+	FuncOnError = "onError"
+)
+
+// ErrCancelled is returned when command is cancelled by hook.
+var ErrCancelled = errors.New("command is cancelled by hook")
+
+// ExecuteFunc sends command to the remote server and returns the response.
+// It is available in hooks as execute function to trigger extra commands.
+type ExecuteFunc func(command string) (string, error)
+
+// JS runs JavaScript hooks. It is safe for concurrent use, hooks are called
+// one by one as JavaScript runtimes are not.
+type JS struct {
+	mu       sync.Mutex
+	runtimes []*goja.Runtime
+}
+
+// NewJS loads JavaScript hook files. Functions print and execute are
+// available in the files.
+func NewJS(names []string, w io.Writer, execute ExecuteFunc) (*JS, error) {
+	js := JS{runtimes: make([]*goja.Runtime, 0, len(names))}
+
+	for _, name := range names {
+		source, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("hook: %w", err)
+		}
+
+		vm := goja.New()
+
+		_ = vm.Set("execute", func(command string) (string, error) {
+			return execute(command)
+		})
+		_ = vm.Set("print", func(call goja.FunctionCall) goja.Value {
+			args := make([]string, 0, len(call.Arguments))
+			for _, arg := range call.Arguments {
+				args = append(args, arg.String())
+			}
+
+			_, _ = fmt.Fprintln(w, strings.Join(args, " "))
+
+			return goja.Undefined()
+		})
+
+		if _, err = vm.RunScript(name, string(source)); err != nil {
+			return nil, fmt.Errorf("hook: %w", err)
+		}
+
+		js.runtimes = append(js.runtimes, vm)
+	}
+
+	return &js, nil
+}
+
+// BeforeSend calls beforeSend hooks and returns the command to send.
+// Returns ErrCancelled if a hook returned false.
+func (js *JS) BeforeSend(command string) (string, error) {
+	if js == nil {
+		return command, nil
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	for _, vm := range js.runtimes {
+		result, err := call(vm, FuncBeforeSend, command)
+		if err != nil {
+			return command, err
+		}
+
+		switch value := result.(type) {
+		case string:
+			command = value
+		case bool:
+			if !value {
+				return command, fmt.Errorf("%w: %s", ErrCancelled, command)
+			}
+		}
+	}
+
+	return command, nil
+}
+
+// AfterResponse calls afterResponse hooks and returns the response to print.
+func (js *JS) AfterResponse(command string, response string) (string, error) {
+	if js == nil {
+		return response, nil
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	for _, vm := range js.runtimes {
+		result, err := call(vm, FuncAfterResponse, command, response)
+		if err != nil {
+			return response, err
+		}
+
+		if value, ok := result.(string); ok {
+			response = value
+		}
+	}
+
+	return response, nil
+}
+
+// OnError calls onError hooks.
+func (js *JS) OnError(command string, e error) error {
+	if js == nil {
+		return nil
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	for _, vm := range js.runtimes {
+		if _, err := call(vm, FuncOnError, command, e.Error()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// call calls JavaScript function name if it is defined and returns its
+// exported result. Result is nil if function is not defined.
+func call(vm *goja.Runtime, name string, args ...string) (interface{}, error) {
+	fn, ok := goja.AssertFunction(vm.Get(name))
+	if !ok {
+		return goja.Undefined().Export(), nil
+	}
+
+	values := make([]goja.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, vm.ToValue(arg))
+	}
+
+	result, err := fn(goja.Undefined(), values...)
+	if err != nil {
+		return nil, fmt.Errorf("hook %s: %w", name, err)
+	}
+
+	return result.Export(), nil
+}
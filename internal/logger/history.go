@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Entry is a single request and response record from the log.
+type Entry struct {
+	Time     time.Time
+	Address  string
+	Request  string
+	Response string
+}
+
+// String returns entry in the log file line format.
+func (e *Entry) String() string {
+	return fmt.Sprintf(DefaultLineFormat, e.Time.Local().Format(DefaultTimeLayout), e.Address, e.Request, e.Response)
+}
+
+// entryHeader matches the first line of the log record.
+var entryHeader = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})] (\S+): (.*)$`)
+
+// Read returns log records created not earlier than since. If name starts
+// with sqlite:// scheme the records are read from SQLite database.
+func Read(name string, since time.Time) ([]Entry, error) {
+	if name == "" {
+		return nil, ErrEmptyFileName
+	}
+
+	if strings.HasPrefix(name, SQLiteScheme) {
+		return ReadSQLite(strings.TrimPrefix(name, SQLiteScheme), since)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+
+	var current *Entry
+
+	var response []string
+
+	flush := func() {
+		if current != nil {
+			current.Response = strings.TrimRight(strings.Join(response, "\n"), "\n")
+			if !current.Time.Before(since) {
+				entries = append(entries, *current)
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<24)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		match := entryHeader.FindStringSubmatch(line)
+		if match == nil {
+			response = append(response, line)
+
+			continue
+		}
+
+		flush()
+
+		created, _ := time.ParseInLocation(DefaultTimeLayout, match[1], time.Local)
+		current = &Entry{Time: created, Address: match[2], Request: match[3]}
+		response = response[:0]
+	}
+
+	flush()
+
+	if err = scanner.Err(); err != nil {
+		return entries, fmt.Errorf("read: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ReadSQLite returns records created not earlier than since from SQLite
+// history database.
+func ReadSQLite(name string, since time.Time) ([]Entry, error) {
+	db, err := OpenSQLite(name)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(
+		"SELECT created_at, address, command, response FROM history WHERE created_at >= ? ORDER BY id",
+		since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("select: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+
+	for rows.Next() {
+		var entry Entry
+		if err = rows.Scan(&entry.Time, &entry.Address, &entry.Request, &entry.Response); err != nil {
+			return entries, fmt.Errorf("scan: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return entries, fmt.Errorf("rows: %w", err)
+	}
+
+	return entries, nil
+}
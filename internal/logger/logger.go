@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/gorcon/rcon-cli/internal/ansi"
 )
 
 // DefaultTimeLayout is layout for convert time.Now to String.
@@ -17,6 +20,39 @@ const DefaultLineFormat = "[%s] %s: %s\n%s\n\n"
 // ErrEmptyFileName is returned when trying to open file with empty name.
 var ErrEmptyFileName = errors.New("empty file name")
 
+// Option configures Write.
+type Option func(o *options)
+
+type options struct {
+	stripColors bool
+	databases   *Databases
+	retention   time.Duration
+}
+
+// StripColors removes ANSI escape sequences and game color codes from
+// the response before it is written if enabled is true.
+func StripColors(enabled bool) Option {
+	return func(o *options) {
+		o.stripColors = enabled
+	}
+}
+
+// WithDatabases writes SQLite history to databases which are kept open
+// instead of opening the database for each write.
+func WithDatabases(databases *Databases) Option {
+	return func(o *options) {
+		o.databases = databases
+	}
+}
+
+// Retention deletes records of SQLite history older than age when new record
+// is written. Records are kept forever if age is zero.
+func Retention(age time.Duration) Option {
+	return func(o *options) {
+		o.retention = age
+	}
+}
+
 // OpenFile opens file for append strings. Creates file if file not exist.
 func OpenFile(name string) (*os.File, error) {
 	if name == "" {
@@ -34,13 +70,8 @@ func OpenFile(name string) (*os.File, error) {
 			return file, fmt.Errorf("open: %w", err)
 		}
 	case os.IsNotExist(err):
-		dir := filepath.Dir(name)
-		if _, err = os.Stat(dir); os.IsNotExist(err) {
-			const perm = 0o766
-
-			if err = os.MkdirAll(dir, perm); err != nil {
-				return file, fmt.Errorf("create directory: %w", err)
-			}
+		if err = createDir(name); err != nil {
+			return file, err
 		}
 
 		file, err = os.Create(name)
@@ -52,13 +83,27 @@ func OpenFile(name string) (*os.File, error) {
 	return file, nil
 }
 
-// Write saves request and response to log file.
-func Write(name string, address string, request string, response string) error {
+// Write saves request and response to log file. If name starts with
+// sqlite:// scheme the history is stored in SQLite database.
+func Write(name string, address string, request string, response string, opts ...Option) error {
 	// Disable logging if log file name is empty.
 	if name == "" {
 		return nil
 	}
 
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.stripColors {
+		response = ansi.Strip(response)
+	}
+
+	if strings.HasPrefix(name, SQLiteScheme) {
+		return WriteSQLite(strings.TrimPrefix(name, SQLiteScheme), address, request, response, opts...)
+	}
+
 	file, err := OpenFile(name)
 	if err != nil {
 		return err
@@ -72,3 +117,17 @@ func Write(name string, address string, request string, response string) error {
 
 	return nil
 }
+
+// createDir creates parent directory for file name if it does not exist.
+func createDir(name string) error {
+	dir := filepath.Dir(name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		const perm = 0o766
+
+		if err = os.MkdirAll(dir, perm); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package logger_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gorcon/rcon-cli/internal/logger"
 	"github.com/stretchr/testify/assert"
@@ -59,4 +60,137 @@ func TestWrite(t *testing.T) {
 		err := logger.Write(logName, address, command, result)
 		assert.NoError(t, err)
 	})
+
+	// Test strip colors from logged response.
+	t.Run("strip colors", func(t *testing.T) {
+		colorLogName := "tmpfile-colors.log"
+		defer os.Remove(colorLogName)
+
+		err := logger.Write(colorLogName, address, command, "\x1b[32m§6Players\x1b[0m", logger.StripColors(true))
+		assert.NoError(t, err)
+
+		entries, err := logger.Read(colorLogName, time.Time{})
+		assert.NoError(t, err)
+
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "Players", entries[0].Response)
+		}
+	})
+}
+
+func TestWriteSQLite(t *testing.T) {
+	dbName := "temp/history.db"
+	defer os.RemoveAll("temp")
+
+	address := "127.0.0.1:16200"
+	command := "players"
+	result := `Players connected (1):
+-admin`
+
+	// Test empty database name.
+	t.Run("empty database name", func(t *testing.T) {
+		err := logger.WriteSQLite("", address, command, result)
+		assert.EqualError(t, err, "empty file name")
+	})
+
+	// Test create database and append records.
+	t.Run("create database", func(t *testing.T) {
+		err := logger.Write(logger.SQLiteScheme+dbName, address, command, result)
+		assert.NoError(t, err)
+
+		err = logger.Write(logger.SQLiteScheme+dbName, address, "status", "ok")
+		assert.NoError(t, err)
+
+		db, err := logger.OpenSQLite(dbName)
+		assert.NoError(t, err)
+		defer db.Close()
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM history WHERE address = ?", address).Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("databases", func(t *testing.T) {
+		databases := &logger.Databases{}
+
+		db, err := databases.Get(dbName)
+		assert.NoError(t, err)
+
+		other, err := databases.Get(dbName)
+		assert.NoError(t, err)
+		assert.Same(t, db, other)
+
+		err = logger.Write(logger.SQLiteScheme+dbName, address, "status", "ok", logger.WithDatabases(databases))
+		assert.NoError(t, err)
+		assert.NoError(t, db.Ping())
+
+		assert.NoError(t, databases.Close())
+		assert.Error(t, db.Ping())
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		db, err := logger.OpenSQLite(dbName)
+		assert.NoError(t, err)
+		defer db.Close()
+
+		_, err = db.Exec("INSERT INTO history (created_at, address, command, response) VALUES (?, ?, ?, ?)",
+			time.Now().UTC().Add(-48*time.Hour), address, "old", "")
+		assert.NoError(t, err)
+
+		err = logger.Write(logger.SQLiteScheme+dbName, address, "new", "", logger.Retention(24*time.Hour))
+		assert.NoError(t, err)
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM history WHERE command = 'old'").Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		err = db.QueryRow("SELECT COUNT(*) FROM history").Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, count)
+	})
+}
+
+func TestRead(t *testing.T) {
+	logName := "tmpfile.log"
+	dbName := "temp/history.db"
+
+	defer func() {
+		os.Remove(logName)
+		os.RemoveAll("temp")
+	}()
+
+	address := "127.0.0.1:16200"
+	result := `Players connected (2):
+-admin
+
+-testuser`
+
+	for _, name := range []string{logName, logger.SQLiteScheme + dbName} {
+		assert.NoError(t, logger.Write(name, address, "players", result))
+		assert.NoError(t, logger.Write(name, address, "save", ""))
+
+		t.Run("read all "+name, func(t *testing.T) {
+			entries, err := logger.Read(name, time.Time{})
+			assert.NoError(t, err)
+			assert.Len(t, entries, 2)
+			assert.Equal(t, address, entries[0].Address)
+			assert.Equal(t, "players", entries[0].Request)
+			assert.Equal(t, result, entries[0].Response)
+			assert.Equal(t, "save", entries[1].Request)
+			assert.Equal(t, "", entries[1].Response)
+		})
+
+		t.Run("read since "+name, func(t *testing.T) {
+			entries, err := logger.Read(name, time.Now().Add(time.Hour))
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+
+	t.Run("empty file name", func(t *testing.T) {
+		_, err := logger.Read("", time.Time{})
+		assert.EqualError(t, err, "empty file name")
+	})
 }
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	// SQLite driver for history backend. It is written in Go, so release
+	// builds stay static with CGO_ENABLED=0.
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteScheme is the log name prefix which enables SQLite history backend.
+// Example: sqlite:///var/lib/rcon/history.db.
+const SQLiteScheme = "sqlite://"
+
+// sqliteSchema creates history table if it does not exist.
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at DATETIME NOT NULL,
+	address TEXT NOT NULL,
+	command TEXT NOT NULL,
+	response TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_created_at ON history (created_at);`
+
+// OpenSQLite opens SQLite history database. Creates database and history
+// table if they do not exist.
+func OpenSQLite(name string) (*sql.DB, error) {
+	if name == "" {
+		return nil, ErrEmptyFileName
+	}
+
+	if err := createDir(name); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", name)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	if _, err = db.Exec(sqliteSchema); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// Databases keeps SQLite history databases open, so each database is opened
+// and its schema is created once for many writes. It is safe for concurrent
+// use. The zero value is ready to use.
+type Databases struct {
+	mu  sync.Mutex
+	dbs map[string]*sql.DB
+}
+
+// Get returns the opened database with the name. Database is opened on the
+// first call.
+func (d *Databases) Get(name string) (*sql.DB, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if db, ok := d.dbs[name]; ok {
+		return db, nil
+	}
+
+	db, err := OpenSQLite(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.dbs == nil {
+		d.dbs = make(map[string]*sql.DB)
+	}
+
+	d.dbs[name] = db
+
+	return db, nil
+}
+
+// Close closes all opened databases.
+func (d *Databases) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var err error
+
+	for name, db := range d.dbs {
+		if closeErr := db.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("close %s: %w", name, closeErr)
+		}
+
+		delete(d.dbs, name)
+	}
+
+	return err
+}
+
+// WriteSQLite saves request and response to SQLite history database. Records
+// older than retention are deleted if it is set.
+func WriteSQLite(name string, address string, request string, response string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	if o.databases != nil {
+		db, err = o.databases.Get(name)
+	} else {
+		db, err = OpenSQLite(name)
+		if db != nil {
+			defer db.Close()
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	_, err = db.Exec(
+		"INSERT INTO history (created_at, address, command, response) VALUES (?, ?, ?, ?)",
+		now, address, request, response)
+	if err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	if o.retention > 0 {
+		if _, err = db.Exec("DELETE FROM history WHERE created_at < ?", now.Add(-o.retention)); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package maintenance contains maintenance windows of environments. During
+// the window alerts are suppressed and commands are sent without
+// confirmation.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/cron"
+)
+
+// ErrInvalidWindow is returned when maintenance window in the config is
+// incomplete.
+var ErrInvalidWindow = errors.New("invalid maintenance window")
+
+// Window is the period which starts by crontab schedule and lasts for the
+// duration.
+type Window struct {
+	// Cron is the crontab schedule of the window start, e.g. "0 4 * * 1".
+	Cron string `json:"cron" yaml:"cron"`
+	// Duration is the length of the window.
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// Windows maps environment names to their maintenance windows.
+type Windows map[string][]Window
+
+// Validate checks that all windows have valid schedule and positive
+// duration.
+func (windows Windows) Validate() error {
+	envs := make([]string, 0, len(windows))
+	for env := range windows {
+		envs = append(envs, env)
+	}
+
+	sort.Strings(envs)
+
+	for _, env := range envs {
+		for _, window := range windows[env] {
+			if _, err := cron.Parse(window.Cron); err != nil {
+				return fmt.Errorf("%w: %s: %s", ErrInvalidWindow, env, err)
+			}
+
+			if window.Duration <= 0 {
+				return fmt.Errorf("%w: %s: duration must be positive", ErrInvalidWindow, env)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Active returns true if a maintenance window of the environment lasts at
+// the time. Windows must be validated.
+func (windows Windows) Active(env string, t time.Time) bool {
+	for _, window := range windows[env] {
+		schedule, err := cron.Parse(window.Cron)
+		if err != nil {
+			continue
+		}
+
+		// The window is active if it started within the duration before t.
+		for start := schedule.Next(t.Add(-window.Duration - time.Minute)); !start.IsZero() && !start.After(t); {
+			if t.Before(start.Add(window.Duration)) {
+				return true
+			}
+
+			start = schedule.Next(start)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package maintenance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/maintenance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindows_Validate(t *testing.T) {
+	assert.NoError(t, maintenance.Windows{"prod": {{Cron: "0 4 * * 1", Duration: time.Hour}}}.Validate())
+
+	err := maintenance.Windows{"prod": {{Cron: "0 4 * *", Duration: time.Hour}}}.Validate()
+	assert.ErrorIs(t, err, maintenance.ErrInvalidWindow)
+
+	err = maintenance.Windows{"prod": {{Cron: "0 4 * * 1"}}}.Validate()
+	assert.EqualError(t, err, "invalid maintenance window: prod: duration must be positive")
+}
+
+func TestWindows_Active(t *testing.T) {
+	windows := maintenance.Windows{
+		"prod": {{Cron: "0 4 * * 1", Duration: time.Hour}},
+		"dev":  {{Cron: "0 23 * * *", Duration: 2 * time.Hour}},
+	}
+
+	// 2024-01-08 is Monday.
+	tests := []struct {
+		name string
+		env  string
+		t    time.Time
+		want bool
+	}{
+		{"before", "prod", time.Date(2024, 1, 8, 3, 59, 59, 0, time.UTC), false},
+		{"start", "prod", time.Date(2024, 1, 8, 4, 0, 0, 0, time.UTC), true},
+		{"inside", "prod", time.Date(2024, 1, 8, 4, 59, 59, 0, time.UTC), true},
+		{"end", "prod", time.Date(2024, 1, 8, 5, 0, 0, 0, time.UTC), false},
+		{"another day", "prod", time.Date(2024, 1, 9, 4, 30, 0, 0, time.UTC), false},
+		{"over midnight", "dev", time.Date(2024, 1, 9, 0, 30, 0, 0, time.UTC), true},
+		{"unknown env", "test", time.Date(2024, 1, 8, 4, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, windows.Active(test.env, test.t))
+		})
+	}
+}
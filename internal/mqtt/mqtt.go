@@ -0,0 +1,138 @@
+// Package mqtt connects to MQTT broker and executes commands received on
+// command topics of environments. Results are published to result topics.
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// DefaultTopicPrefix is the first level of command and result topics.
+const DefaultTopicPrefix = "rcon"
+
+// ErrTimeout is returned when broker does not acknowledge request in time.
+var ErrTimeout = errors.New("mqtt broker timeout")
+
+// Handler executes the command and returns the response.
+type Handler func(command string) (string, error)
+
+// Result is published to the result topic after each command.
+type Result struct {
+	Env      string `json:"env"`
+	Command  string `json:"command"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CommandTopic returns topic on which commands for the environment are
+// received. Example: rcon/prod/command.
+func CommandTopic(prefix string, env string) string {
+	return prefix + "/" + env + "/command"
+}
+
+// ResultTopic returns topic on which results of the environment are
+// published. Example: rcon/prod/result.
+func ResultTopic(prefix string, env string) string {
+	return prefix + "/" + env + "/result"
+}
+
+// Handle executes the command from the payload and returns encoded result.
+// Nil is returned for empty payload.
+func Handle(env string, payload []byte, handler Handler) []byte {
+	command := strings.TrimSpace(string(payload))
+	if command == "" {
+		return nil
+	}
+
+	result := Result{Env: env, Command: command}
+
+	response, err := handler(command)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Response = response
+	}
+
+	data, _ := json.Marshal(result)
+
+	return data
+}
+
+// Options contains MQTT broker connection details.
+type Options struct {
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	QoS      byte
+	Timeout  time.Duration
+}
+
+// Client is MQTT broker connection.
+type Client struct {
+	client  paho.Client
+	qos     byte
+	timeout time.Duration
+}
+
+// Connect connects to the MQTT broker. Client reconnects automatically and
+// restores subscriptions if the connection is lost.
+func Connect(options Options) (*Client, error) {
+	opts := paho.NewClientOptions().
+		AddBroker(options.Broker).
+		SetClientID(options.ClientID).
+		SetUsername(options.Username).
+		SetPassword(options.Password).
+		SetConnectTimeout(options.Timeout).
+		SetAutoReconnect(true).
+		SetCleanSession(false).
+		// Handlers publish results and wait for acknowledgement, so they
+		// must not block the incoming messages.
+		SetOrderMatters(false)
+
+	client := &Client{client: paho.NewClient(opts), qos: options.QoS, timeout: options.Timeout}
+
+	if err := client.wait(client.client.Connect()); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Subscribe subscribes to the command topic of the environment and passes
+// received commands to the handler. Results are published to the result
+// topic of the environment.
+func (c *Client) Subscribe(prefix string, env string, handler Handler) error {
+	topic := ResultTopic(prefix, env)
+
+	callback := func(_ paho.Client, message paho.Message) {
+		if result := Handle(env, message.Payload(), handler); result != nil {
+			_ = c.wait(c.client.Publish(topic, c.qos, false, result))
+		}
+	}
+
+	return c.wait(c.client.Subscribe(CommandTopic(prefix, env), c.qos, callback))
+}
+
+// Close disconnects from the broker.
+func (c *Client) Close() {
+	c.client.Disconnect(uint(c.timeout.Milliseconds()))
+}
+
+// wait waits until the token is completed.
+func (c *Client) wait(token paho.Token) error {
+	if !token.WaitTimeout(c.timeout) {
+		return ErrTimeout
+	}
+
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+
+	return nil
+}
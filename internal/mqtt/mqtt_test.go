@@ -0,0 +1,51 @@
+package mqtt_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/mqtt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopics(t *testing.T) {
+	assert.Equal(t, "rcon/prod/command", mqtt.CommandTopic(mqtt.DefaultTopicPrefix, "prod"))
+	assert.Equal(t, "home/prod/result", mqtt.ResultTopic("home", "prod"))
+}
+
+func TestHandle(t *testing.T) {
+	handler := func(command string) (string, error) {
+		if command == "fail" {
+			return "", errors.New("denied")
+		}
+
+		return "Players: 0", nil
+	}
+
+	t.Run("response", func(t *testing.T) {
+		result := mqtt.Handle("prod", []byte("status\n"), handler)
+		assert.JSONEq(t, `{"env":"prod","command":"status","response":"Players: 0"}`, string(result))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		result := mqtt.Handle("prod", []byte("fail"), handler)
+		assert.JSONEq(t, `{"env":"prod","command":"fail","error":"denied"}`, string(result))
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		assert.Nil(t, mqtt.Handle("prod", []byte(" \n"), handler))
+	})
+}
+
+func TestConnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = mqtt.Connect(mqtt.Options{Broker: "tcp://" + address, ClientID: "test", Timeout: time.Second})
+	assert.Error(t, err)
+}
@@ -0,0 +1,505 @@
+// Package nats implements minimal NATS client which serves request/reply
+// subjects. Only core protocol is supported: no JetStream. Lost connections
+// are detected with pings and restored with subscriptions.
+package nats
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultURL is the address of NATS server.
+	DefaultURL = "nats://127.0.0.1:4222"
+
+	// DefaultSubjectPrefix is the first token of exec subjects.
+	DefaultSubjectPrefix = "rcon"
+
+	// DefaultPort is the port of NATS server if URL has no port.
+	DefaultPort = "4222"
+
+	// DefaultPingInterval is the interval of client pings.
+	DefaultPingInterval = 2 * time.Minute
+
+	// DefaultMaxPingsOut is the number of unanswered pings after which the
+	// connection is considered lost.
+	DefaultMaxPingsOut = 2
+
+	// DefaultMaxReconnects is the number of reconnect attempts in a row.
+	DefaultMaxReconnects = 60
+
+	// DefaultReconnectWait is the delay before the first reconnect attempt.
+	DefaultReconnectWait = time.Second
+
+	// MaxReconnectWait is the maximum delay between reconnect attempts.
+	MaxReconnectWait = time.Minute
+)
+
+// CRLF ends protocol operations and message payloads.
+const CRLF = "\r\n"
+
+var (
+	// ErrProtocol is returned when server sends unexpected operation.
+	ErrProtocol = errors.New("nats protocol error")
+
+	// ErrServer is returned when server reports error with -ERR operation.
+	ErrServer = errors.New("nats server error")
+
+	// ErrConnectionLost is returned when the lost connection is not restored
+	// in MaxReconnects attempts.
+	ErrConnectionLost = errors.New("nats connection lost")
+)
+
+// Handler executes the command and returns the response.
+type Handler func(command string) (string, error)
+
+// Subject returns subject on which commands for the environment are
+// requested. Example: rcon.prod.exec.
+func Subject(prefix string, env string) string {
+	return prefix + "." + env + ".exec"
+}
+
+// Options contains NATS server connection details. Credentials from URL
+// take precedence.
+type Options struct {
+	URL      string
+	Name     string
+	Username string
+	Password string
+	Token    string
+	Timeout  time.Duration
+	// PingInterval is the interval of pings which detect lost connections.
+	// The connection is closed if MaxPingsOut pings are not answered.
+	// Defaults are used if they are not set.
+	PingInterval time.Duration
+	MaxPingsOut  int
+	// MaxReconnects is the number of attempts in a row to restore the lost
+	// connection, the connection is not restored if it is not set.
+	// ReconnectWait is the delay before the first attempt, doubled after
+	// each failed attempt up to MaxReconnectWait.
+	MaxReconnects int
+	ReconnectWait time.Duration
+}
+
+// connect is the payload of CONNECT operation.
+type connect struct {
+	Verbose      bool   `json:"verbose"`
+	Pedantic     bool   `json:"pedantic"`
+	Name         string `json:"name,omitempty"`
+	Lang         string `json:"lang"`
+	Version      string `json:"version"`
+	Protocol     int    `json:"protocol"`
+	Headers      bool   `json:"headers"`
+	NoResponders bool   `json:"no_responders"`
+	User         string `json:"user,omitempty"`
+	Pass         string `json:"pass,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+}
+
+// info is the payload of INFO operation.
+type info struct {
+	TLSRequired bool `json:"tls_required"`
+}
+
+// Conn is NATS server connection.
+type Conn struct {
+	url     *url.URL
+	options Options
+	conn    net.Conn
+	r       *bufio.Reader
+	mu      sync.Mutex
+	sid     int
+	subs    map[string]Handler
+	ops     []string
+	pings   int
+	done    chan struct{}
+	once    sync.Once
+	pending sync.WaitGroup
+}
+
+// Connect connects and authenticates to the NATS server.
+func Connect(options Options) (*Conn, error) {
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), DefaultPort)
+	}
+
+	if u.User != nil {
+		options.Username = u.User.Username()
+		options.Password, _ = u.User.Password()
+	}
+
+	if options.PingInterval <= 0 {
+		options.PingInterval = DefaultPingInterval
+	}
+
+	if options.MaxPingsOut <= 0 {
+		options.MaxPingsOut = DefaultMaxPingsOut
+	}
+
+	if options.ReconnectWait <= 0 {
+		options.ReconnectWait = DefaultReconnectWait
+	}
+
+	c := &Conn{url: u, options: options, subs: make(map[string]Handler), done: make(chan struct{})}
+
+	if err = c.dial(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Subscribe subscribes to the subject in the queue group and passes payloads
+// of requests to the handler. Responses are sent to reply subjects, handler
+// errors are sent in Nats-Service-Error header.
+func (c *Conn) Subscribe(subject string, queue string, handler Handler) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sid++
+	sid := strconv.Itoa(c.sid)
+	c.subs[sid] = handler
+
+	op := "SUB " + subject + " " + sid + CRLF
+	if queue != "" {
+		op = "SUB " + subject + " " + queue + " " + sid + CRLF
+	}
+
+	c.ops = append(c.ops, op)
+
+	return c.write(op)
+}
+
+// Serve reads requests until the connection is closed. The lost connection
+// is restored with subscriptions.
+func (c *Conn) Serve() error {
+	defer c.pending.Wait()
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go c.ping(stop)
+
+	for {
+		err := c.next()
+
+		switch {
+		case err == nil:
+			continue
+		case c.closed():
+			return nil
+		case errors.Is(err, ErrProtocol), errors.Is(err, ErrServer):
+			return err
+		}
+
+		if err = c.reconnect(err); err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	c.once.Do(func() { close(c.done) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.Close() //nolint:wrapcheck // Close error is not returned to the user.
+}
+
+// closed reports whether Close is called.
+func (c *Conn) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// dial connects and authenticates to the server and replaces the current
+// connection. Subscriptions are sent again to the new connection.
+func (c *Conn) dial() error {
+	conn, err := net.DialTimeout("tcp", c.url.Host, c.options.Timeout)
+	if err != nil {
+		return fmt.Errorf("nats: %w", err)
+	}
+
+	next := &Conn{conn: conn, r: bufio.NewReader(conn)}
+
+	if err = next.handshake(c.url, c.options); err != nil {
+		next.conn.Close()
+
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed() {
+		next.conn.Close()
+
+		return fmt.Errorf("nats: %w", net.ErrClosed)
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+
+	c.conn, c.r, c.pings = next.conn, next.r, 0
+
+	if len(c.ops) == 0 {
+		return nil
+	}
+
+	return c.write(strings.Join(c.ops, ""))
+}
+
+// reconnect restores the lost connection in MaxReconnects attempts. The
+// delay between attempts is doubled after each failed attempt.
+func (c *Conn) reconnect(err error) error {
+	delay := c.options.ReconnectWait
+
+	for attempt := 1; attempt <= c.options.MaxReconnects; attempt++ {
+		select {
+		case <-c.done:
+			return nil
+		case <-time.After(delay):
+		}
+
+		if err = c.dial(); err == nil || c.closed() {
+			return nil
+		}
+
+		delay = min(2*delay, MaxReconnectWait)
+	}
+
+	return fmt.Errorf("%w: %s", ErrConnectionLost, err)
+}
+
+// ping sends PING every PingInterval until stop is closed. The connection
+// is closed if MaxPingsOut pings are not answered, so it is reconnected.
+func (c *Conn) ping(stop <-chan struct{}) {
+	ticker := time.NewTicker(c.options.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+
+		if c.pings >= c.options.MaxPingsOut {
+			_ = c.conn.Close()
+		} else {
+			c.pings++
+			_ = c.write("PING" + CRLF)
+		}
+
+		c.mu.Unlock()
+	}
+}
+
+// next reads and handles the protocol operation.
+func (c *Conn) next() error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	return c.dispatch(line)
+}
+
+// handshake reads server INFO, upgrades connection to TLS if required and
+// sends CONNECT. Server response to PING confirms authentication.
+func (c *Conn) handshake(u *url.URL, options Options) error {
+	if options.Timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(options.Timeout))
+		defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+	}
+
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	op, args, _ := strings.Cut(line, " ")
+	if !strings.EqualFold(op, "INFO") {
+		return fmt.Errorf("%w: expected INFO, got %s", ErrProtocol, line)
+	}
+
+	var server info
+	if err = json.Unmarshal([]byte(args), &server); err != nil {
+		return fmt.Errorf("%w: %s", ErrProtocol, err)
+	}
+
+	if server.TLSRequired || u.Scheme == "tls" {
+		conn := tls.Client(c.conn, &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12})
+		c.conn, c.r = conn, bufio.NewReader(conn)
+	}
+
+	data, _ := json.Marshal(connect{
+		Name: options.Name, Lang: "go", Version: "1.0.0", Protocol: 1, Headers: true, NoResponders: true,
+		User: options.Username, Pass: options.Password, AuthToken: options.Token,
+	})
+
+	if err = c.write("CONNECT " + string(data) + CRLF + "PING" + CRLF); err != nil {
+		return err
+	}
+
+	for {
+		if line, err = c.readLine(); err != nil {
+			return err
+		}
+
+		switch op, _, _ = strings.Cut(line, " "); strings.ToUpper(op) {
+		case "PONG":
+			return nil
+		case "-ERR":
+			return fmt.Errorf("%w: %s", ErrServer, strings.TrimSpace(line[len(op):]))
+		}
+	}
+}
+
+// dispatch handles the protocol operation.
+func (c *Conn) dispatch(line string) error {
+	op, args, _ := strings.Cut(line, " ")
+
+	switch strings.ToUpper(op) {
+	case "PING":
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		return c.write("PONG" + CRLF)
+	case "PONG":
+		c.mu.Lock()
+		c.pings = 0
+		c.mu.Unlock()
+
+		return nil
+	case "MSG":
+		return c.message(strings.Fields(args), false)
+	case "HMSG":
+		return c.message(strings.Fields(args), true)
+	case "-ERR":
+		return fmt.Errorf("%w: %s", ErrServer, strings.TrimSpace(args))
+	case "+OK", "INFO":
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown operation %s", ErrProtocol, op)
+	}
+}
+
+// message reads payload of MSG or HMSG operation and passes it to the
+// handler of the subscription. Headers are skipped.
+//
+//	MSG <subject> <sid> [reply-to] <#bytes>
+//	HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+func (c *Conn) message(args []string, headers bool) error {
+	fields := 3
+	if headers {
+		fields = 4
+	}
+
+	if len(args) != fields && len(args) != fields+1 {
+		return fmt.Errorf("%w: invalid message arguments %s", ErrProtocol, strings.Join(args, " "))
+	}
+
+	var reply string
+	if len(args) == fields+1 {
+		reply = args[2]
+	}
+
+	size, err := strconv.Atoi(args[len(args)-1])
+	if err != nil || size < 0 {
+		return fmt.Errorf("%w: invalid message size %s", ErrProtocol, args[len(args)-1])
+	}
+
+	skip := 0
+	if headers {
+		if skip, err = strconv.Atoi(args[len(args)-2]); err != nil || skip < 0 || skip > size {
+			return fmt.Errorf("%w: invalid header size %s", ErrProtocol, args[len(args)-2])
+		}
+	}
+
+	payload := make([]byte, size+len(CRLF))
+	if _, err = io.ReadFull(c.r, payload); err != nil {
+		return fmt.Errorf("nats: %w", err)
+	}
+
+	c.mu.Lock()
+	handler, ok := c.subs[args[1]]
+	c.mu.Unlock()
+
+	if !ok || reply == "" {
+		return nil
+	}
+
+	c.pending.Add(1)
+
+	go func() {
+		defer c.pending.Done()
+
+		_ = c.respond(reply, handler, string(payload[skip:size]))
+	}()
+
+	return nil
+}
+
+// respond executes the command and publishes the response to the reply
+// subject.
+func (c *Conn) respond(reply string, handler Handler, command string) error {
+	response, err := handler(strings.TrimSpace(command))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		header := "NATS/1.0" + CRLF +
+			"Nats-Service-Error: " + strings.NewReplacer("\r", " ", "\n", " ").Replace(err.Error()) + CRLF +
+			"Nats-Service-Error-Code: 500" + CRLF + CRLF
+
+		return c.write(fmt.Sprintf("HPUB %s %d %d%s%s%s", reply, len(header), len(header), CRLF, header, CRLF))
+	}
+
+	return c.write(fmt.Sprintf("PUB %s %d%s%s%s", reply, len(response), CRLF, response, CRLF))
+}
+
+// readLine reads protocol operation line without CRLF.
+func (c *Conn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("nats: %w", err)
+	}
+
+	return strings.TrimRight(line, CRLF), nil
+}
+
+// write sends data to the server. Caller must hold the mutex if the
+// connection is shared.
+func (c *Conn) write(data string) error {
+	if _, err := io.WriteString(c.conn, data); err != nil {
+		return fmt.Errorf("nats: %w", err)
+	}
+
+	return nil
+}
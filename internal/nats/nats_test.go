@@ -0,0 +1,218 @@
+package nats_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/nats"
+	"github.com/stretchr/testify/assert"
+)
+
+// server is a fake NATS server which runs the script with each client.
+func server(t *testing.T, script func(conn net.Conn, r *bufio.Reader)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			_, _ = io.WriteString(conn, `INFO {"server_id":"test","headers":true}`+"\r\n")
+
+			script(conn, bufio.NewReader(conn))
+			conn.Close()
+		}
+	}()
+
+	return "nats://" + listener.Addr().String()
+}
+
+// readLine reads protocol line without CRLF.
+func readLine(r *bufio.Reader) string {
+	line, _ := r.ReadString('\n')
+
+	return strings.TrimRight(line, "\r\n")
+}
+
+func TestSubject(t *testing.T) {
+	assert.Equal(t, "rcon.prod.exec", nats.Subject(nats.DefaultSubjectPrefix, "prod"))
+}
+
+func TestConnect(t *testing.T) {
+	t.Run("authorization violation", func(t *testing.T) {
+		address := server(t, func(conn net.Conn, r *bufio.Reader) {
+			readLine(r)
+			readLine(r)
+			_, _ = io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+		})
+
+		_, err := nats.Connect(nats.Options{URL: address, Timeout: time.Second})
+		assert.ErrorIs(t, err, nats.ErrServer)
+		assert.ErrorContains(t, err, "Authorization Violation")
+	})
+
+	t.Run("credentials from url", func(t *testing.T) {
+		connect := make(chan string, 1)
+
+		address := server(t, func(conn net.Conn, r *bufio.Reader) {
+			connect <- readLine(r)
+			readLine(r)
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		})
+
+		address = strings.Replace(address, "nats://", "nats://user:secret@", 1)
+
+		c, err := nats.Connect(nats.Options{URL: address, Username: "other", Timeout: time.Second})
+		assert.NoError(t, err)
+		assert.NoError(t, c.Close())
+		assert.Contains(t, <-connect, `"user":"user","pass":"secret"`)
+	})
+}
+
+func TestConn_Serve(t *testing.T) {
+	published := make(chan string, 3)
+
+	address := server(t, func(conn net.Conn, r *bufio.Reader) {
+		readLine(r)
+		readLine(r)
+		_, _ = io.WriteString(conn, "PONG\r\n")
+
+		published <- readLine(r)
+
+		_, _ = io.WriteString(conn, "PING\r\n")
+		published <- readLine(r)
+
+		_, _ = io.WriteString(conn, "MSG rcon.prod.exec 1 _INBOX.1 6\r\nstatus\r\n")
+		published <- readLine(r) + "|" + readLine(r)
+
+		_, _ = io.WriteString(conn, "HMSG rcon.prod.exec 1 _INBOX.2 12 16\r\nNATS/1.0\r\n\r\nfail\r\n")
+		header := readLine(r)
+		published <- header + "|" + readLine(r) + "|" + readLine(r)
+
+		_, _ = io.WriteString(conn, "-ERR 'Stale Connection'\r\n")
+	})
+
+	c, err := nats.Connect(nats.Options{URL: address, Timeout: time.Second})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	err = c.Subscribe(nats.Subject("rcon", "prod"), "rcon", func(command string) (string, error) {
+		if command == "fail" {
+			return "", errors.New("denied")
+		}
+
+		return "Players: 0", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SUB rcon.prod.exec rcon 1", <-published)
+
+	done := make(chan error, 1)
+
+	go func() { done <- c.Serve() }()
+
+	assert.Equal(t, "PONG", <-published)
+	assert.Equal(t, "PUB _INBOX.1 10|Players: 0", <-published)
+	assert.Equal(t, "HPUB _INBOX.2 70 70|NATS/1.0|Nats-Service-Error: denied", <-published)
+	assert.ErrorIs(t, <-done, nats.ErrServer)
+}
+
+func TestConn_Reconnect(t *testing.T) {
+	published := make(chan string, 4)
+	clients := 0
+
+	address := server(t, func(conn net.Conn, r *bufio.Reader) {
+		clients++
+
+		readLine(r)
+		readLine(r)
+		_, _ = io.WriteString(conn, "PONG\r\n")
+
+		published <- readLine(r)
+
+		if clients == 1 {
+			// Pings are not answered, client must close the connection.
+			for readLine(r) == "PING" {
+				published <- "PING"
+			}
+
+			return
+		}
+
+		_, _ = io.WriteString(conn, "MSG rcon.prod.exec 1 _INBOX.1 6\r\nstatus\r\n")
+
+		line := readLine(r)
+		for line == "PING" {
+			line = readLine(r)
+		}
+
+		published <- line + "|" + readLine(r)
+
+		_, _ = io.WriteString(conn, "-ERR 'Stale Connection'\r\n")
+	})
+
+	c, err := nats.Connect(nats.Options{
+		URL: address, Timeout: time.Second, PingInterval: 20 * time.Millisecond, MaxPingsOut: 2,
+		MaxReconnects: 3, ReconnectWait: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	err = c.Subscribe(nats.Subject("rcon", "prod"), "", func(string) (string, error) {
+		return "Players: 0", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SUB rcon.prod.exec 1", <-published)
+
+	done := make(chan error, 1)
+
+	go func() { done <- c.Serve() }()
+
+	assert.Equal(t, "PING", <-published)
+	assert.Equal(t, "PING", <-published)
+	assert.Equal(t, "SUB rcon.prod.exec 1", <-published)
+	assert.Equal(t, "PUB _INBOX.1 10|Players: 0", <-published)
+	assert.ErrorIs(t, <-done, nats.ErrServer)
+
+	t.Run("connection lost", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			_, _ = io.WriteString(conn, `INFO {"server_id":"test"}`+"\r\n")
+
+			r := bufio.NewReader(conn)
+			readLine(r)
+			readLine(r)
+			_, _ = io.WriteString(conn, "PONG\r\n")
+
+			conn.Close()
+			listener.Close()
+		}()
+
+		c, err := nats.Connect(nats.Options{
+			URL: "nats://" + listener.Addr().String(), Timeout: time.Second,
+			MaxReconnects: 2, ReconnectWait: 10 * time.Millisecond,
+		})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		assert.ErrorIs(t, c.Serve(), nats.ErrConnectionLost)
+	})
+}
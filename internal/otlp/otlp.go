@@ -0,0 +1,261 @@
+// Package otlp records spans of one run and exports them to OpenTelemetry
+// collector with OTLP/HTTP JSON protocol. Parent span is taken from W3C
+// traceparent, so runs from automation are correlated with pipeline traces.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultServiceName is the service.name resource attribute if it is
+	// not set with OTEL_SERVICE_NAME.
+	DefaultServiceName = "rcon"
+
+	// TracesPath is appended to the collector endpoint.
+	TracesPath = "/v1/traces"
+
+	// ScopeName is the instrumentation scope of spans.
+	ScopeName = "github.com/gorcon/rcon-cli"
+)
+
+// Span kinds and status codes of OTLP.
+const (
+	KindInternal = 1
+	KindClient   = 3
+
+	statusOK    = 1
+	statusError = 2
+)
+
+// ErrExport is returned when collector rejects spans.
+var ErrExport = errors.New("export spans")
+
+// SpanContext identifies the span in the trace.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// ParseTraceparent parses W3C traceparent header value, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01. The second
+// returned value is false if the value is invalid.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+
+	//nolint:gomnd // Version, trace id, parent id and flags.
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return SpanContext{}, false
+	}
+
+	for _, id := range parts[1:3] {
+		if _, err := hex.DecodeString(id); err != nil || strings.Trim(id, "0") == "" {
+			return SpanContext{}, false
+		}
+	}
+
+	return SpanContext{TraceID: strings.ToLower(parts[1]), SpanID: strings.ToLower(parts[2])}, true
+}
+
+// Span is a timed operation of the run.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Kind         int
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	Error        string
+}
+
+// Tracer collects spans of one run under the root span. It is safe for
+// concurrent use.
+type Tracer struct {
+	mu      sync.Mutex
+	service string
+	version string
+	root    Span
+	spans   []Span
+}
+
+// NewTracer starts the root span. If traceparent is valid, the root span is
+// its child, otherwise a new trace is started.
+func NewTracer(service string, version string, name string, traceparent string) *Tracer {
+	root := Span{SpanID: newID(8), Name: name, Kind: KindInternal, Start: time.Now()} //nolint:gomnd // Span id size.
+
+	if parent, ok := ParseTraceparent(traceparent); ok {
+		root.TraceID, root.ParentSpanID = parent.TraceID, parent.SpanID
+	} else {
+		root.TraceID = newID(16) //nolint:gomnd // Trace id size.
+	}
+
+	return &Tracer{service: service, version: version, root: root}
+}
+
+// Record adds finished child span of the root span. The span has error
+// status if failure message is not empty.
+func (t *Tracer) Record(name string, kind int, start time.Time, end time.Time, attrs map[string]string, failure string) {
+	span := Span{
+		TraceID:      t.root.TraceID,
+		SpanID:       newID(8), //nolint:gomnd // Span id size.
+		ParentSpanID: t.root.SpanID,
+		Name:         name,
+		Kind:         kind,
+		Start:        start,
+		End:          end,
+		Attributes:   attrs,
+		Error:        failure,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = append(t.spans, span)
+}
+
+// End ends the root span with error status if err is not nil.
+func (t *Tracer) End(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.root.End = time.Now()
+
+	if err != nil {
+		t.root.Error = err.Error()
+	}
+}
+
+// Spans returns the root span and its children.
+func (t *Tracer) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Span{t.root}, t.spans...)
+}
+
+// MarshalJSON encodes spans as OTLP ExportTraceServiceRequest.
+func (t *Tracer) MarshalJSON() ([]byte, error) {
+	spans := t.Spans()
+	encoded := make([]map[string]interface{}, 0, len(spans))
+
+	for _, span := range spans {
+		encoded = append(encoded, encodeSpan(span))
+	}
+
+	request := map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": attributes(map[string]string{"service.name": t.service}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]string{"name": ScopeName, "version": t.version},
+				"spans": encoded,
+			}},
+		}},
+	}
+
+	return json.Marshal(request) //nolint:wrapcheck // Maps are always encoded.
+}
+
+// Export sends spans to the collector endpoint, e.g. http://collector:4318.
+func (t *Tracer) Export(endpoint string, timeout time.Duration) error {
+	data, err := t.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrExport, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	url := strings.TrimSuffix(endpoint, "/") + TracesPath
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrExport, err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrExport, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 { //nolint:gomnd // Any 2xx status is success.
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512)) //nolint:gomnd // Error message is short.
+
+		return fmt.Errorf("%w: %s: %s", ErrExport, response.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// encodeSpan converts span to OTLP JSON object.
+func encodeSpan(span Span) map[string]interface{} {
+	status := map[string]interface{}{"code": statusOK}
+	if span.Error != "" {
+		status = map[string]interface{}{"code": statusError, "message": span.Error}
+	}
+
+	encoded := map[string]interface{}{
+		"traceId":           span.TraceID,
+		"spanId":            span.SpanID,
+		"name":              span.Name,
+		"kind":              span.Kind,
+		"startTimeUnixNano": strconv.FormatInt(span.Start.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(span.End.UnixNano(), 10),
+		"attributes":        attributes(span.Attributes),
+		"status":            status,
+	}
+
+	if span.ParentSpanID != "" {
+		encoded["parentSpanId"] = span.ParentSpanID
+	}
+
+	return encoded
+}
+
+// attributes converts string attributes to OTLP key values.
+func attributes(attrs map[string]string) []map[string]interface{} {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	encoded := make([]map[string]interface{}, 0, len(attrs))
+
+	for _, key := range keys {
+		encoded = append(encoded, map[string]interface{}{
+			"key":   key,
+			"value": map[string]string{"stringValue": attrs[key]},
+		})
+	}
+
+	return encoded
+}
+
+// newID returns random hex identifier of size bytes.
+func newID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
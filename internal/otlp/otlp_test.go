@@ -0,0 +1,94 @@
+package otlp_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/otlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, ok := otlp.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, otlp.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}, sc)
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+	}
+
+	for _, value := range invalid {
+		_, ok = otlp.ParseTraceparent(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("new trace", func(t *testing.T) {
+		tracer := otlp.NewTracer(otlp.DefaultServiceName, "", "rcon", "")
+		tracer.End(nil)
+
+		spans := tracer.Spans()
+		assert.Len(t, spans, 1)
+		assert.Len(t, spans[0].TraceID, 32)
+		assert.Len(t, spans[0].SpanID, 16)
+		assert.Empty(t, spans[0].ParentSpanID)
+	})
+
+	t.Run("child of traceparent", func(t *testing.T) {
+		tracer := otlp.NewTracer(otlp.DefaultServiceName, "", "rcon",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		start := time.Unix(1700000000, 0)
+		tracer.Record("execute", otlp.KindClient, start, start.Add(time.Second), map[string]string{"a": "b"}, "timeout")
+		tracer.End(nil)
+
+		spans := tracer.Spans()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID)
+		assert.Equal(t, spans[0].TraceID, spans[1].TraceID)
+		assert.Equal(t, spans[0].SpanID, spans[1].ParentSpanID)
+		assert.Equal(t, "timeout", spans[1].Error)
+	})
+}
+
+func TestTracer_Export(t *testing.T) {
+	var path, contentType string
+
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer server.Close()
+
+	tracer := otlp.NewTracer("game", "v1.0.0", "rcon", "")
+	start := time.Unix(1700000000, 0)
+	attrs := map[string]string{"rcon.command": "status"}
+	tracer.Record("execute", otlp.KindClient, start, start.Add(time.Millisecond), attrs, "")
+	tracer.End(nil)
+
+	assert.NoError(t, tracer.Export(server.URL+"/", time.Second))
+	assert.Equal(t, otlp.TracesPath, path)
+	assert.Equal(t, "application/json", contentType)
+
+	data, err := json.Marshal(body["resourceSpans"])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"key":"service.name","value":{"stringValue":"game"}}`)
+	assert.Contains(t, string(data), `"endTimeUnixNano":"1700000000001000000"`)
+	assert.Contains(t, string(data), `{"key":"rcon.command","value":{"stringValue":"status"}}`)
+
+	server.Close()
+	assert.ErrorIs(t, tracer.Export(server.URL, time.Second), otlp.ErrExport)
+}
@@ -0,0 +1,195 @@
+// Package output writes responses from remote servers in machine readable
+// formats.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats.
+const (
+	FormatText = "text"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// ErrUnsupportedFormat is returned when output format is not supported.
+var ErrUnsupportedFormat = errors.New("unsupported output format")
+
+// Record contains the result of executing a command on a remote server.
+type Record struct {
+	Address  string `json:"address" yaml:"address"`
+	Command  string `json:"command" yaml:"command"`
+	Response string `json:"response" yaml:"response"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Fields contains names of parsed fields in order of appearance.
+	Fields []string `json:"-" yaml:"-"`
+	// Parsed contains records extracted from the response by parser.
+	Parsed []map[string]string `json:"parsed,omitempty" yaml:"parsed,omitempty"`
+	// Time is the formatted time when the response was received.
+	Time string `json:"time,omitempty" yaml:"time,omitempty"`
+	// Timing contains durations of request stages if timing is enabled.
+	Timing *Timing `json:"timing,omitempty" yaml:"timing,omitempty"`
+}
+
+// Writer is the interface that wraps the basic WriteRecord method.
+type Writer interface {
+	WriteRecord(rec *Record) error
+}
+
+// NewWriter returns Writer for structured format. Text format is not
+// structured and is written directly by the caller.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatJSON:
+		return NewJSONWriter(w), nil
+	case FormatYAML:
+		return NewYAMLWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// IsSupported returns true if format is known. Empty format means text.
+func IsSupported(format string) bool {
+	switch format {
+	case "", FormatText, FormatCSV, FormatJSON, FormatYAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsStructured returns true if format is not a plain text.
+func IsStructured(format string) bool {
+	return format != "" && format != FormatText
+}
+
+// CSVWriter writes records as comma separated values with header line.
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter creates a new CSVWriter.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// WriteRecord writes rec as a single row. If the response was parsed one
+// row per parsed record is written instead. Header is written before
+// the first row.
+func (cw *CSVWriter) WriteRecord(rec *Record) error {
+	if !cw.header {
+		header := []string{"address", "command", "response", "error"}
+		if rec.Fields != nil {
+			header = append(append([]string{"address", "command"}, rec.Fields...), "error")
+		}
+
+		if err := cw.w.Write(header); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+
+		cw.header = true
+	}
+
+	for _, row := range cw.rows(rec) {
+		if err := cw.w.Write(row); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
+
+// rows converts rec to CSV rows.
+func (cw *CSVWriter) rows(rec *Record) [][]string {
+	if rec.Fields == nil {
+		return [][]string{{rec.Address, rec.Command, rec.Response, rec.Error}}
+	}
+
+	if len(rec.Parsed) == 0 && rec.Error != "" {
+		row := append([]string{rec.Address, rec.Command}, make([]string, len(rec.Fields))...)
+
+		return [][]string{append(row, rec.Error)}
+	}
+
+	rows := make([][]string, 0, len(rec.Parsed))
+
+	for _, parsed := range rec.Parsed {
+		row := []string{rec.Address, rec.Command}
+		for _, field := range rec.Fields {
+			row = append(row, parsed[field])
+		}
+
+		rows = append(rows, append(row, rec.Error))
+	}
+
+	return rows
+}
+
+// JSONWriter writes each record as a JSON object on a separate line.
+type JSONWriter struct {
+	enc *json.Encoder
+}
+
+// NewJSONWriter creates a new JSONWriter.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{enc: json.NewEncoder(w)}
+}
+
+// WriteRecord writes rec as a single JSON line.
+func (jw *JSONWriter) WriteRecord(rec *Record) error {
+	if err := jw.enc.Encode(rec); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	return nil
+}
+
+// YAMLWriter writes each record as a separate YAML document with the same
+// structure as JSONWriter does.
+type YAMLWriter struct {
+	w     io.Writer
+	count int
+}
+
+// NewYAMLWriter creates a new YAMLWriter.
+func NewYAMLWriter(w io.Writer) *YAMLWriter {
+	return &YAMLWriter{w: w}
+}
+
+// WriteRecord writes rec as a YAML document. Documents are separated
+// by `---` line.
+func (yw *YAMLWriter) WriteRecord(rec *Record) error {
+	data, err := yaml.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	if yw.count > 0 {
+		if _, err = io.WriteString(yw.w, "---\n"); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+
+	yw.count++
+
+	if _, err = yw.w.Write(data); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWriter(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		writer, err := output.NewWriter(&bytes.Buffer{}, output.FormatCSV)
+		assert.NoError(t, err)
+		assert.IsType(t, &output.CSVWriter{}, writer)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		writer, err := output.NewWriter(&bytes.Buffer{}, output.FormatJSON)
+		assert.NoError(t, err)
+		assert.IsType(t, &output.JSONWriter{}, writer)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		writer, err := output.NewWriter(&bytes.Buffer{}, output.FormatYAML)
+		assert.NoError(t, err)
+		assert.IsType(t, &output.YAMLWriter{}, writer)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		writer, err := output.NewWriter(&bytes.Buffer{}, "xml")
+		assert.ErrorIs(t, err, output.ErrUnsupportedFormat)
+		assert.Nil(t, writer)
+	})
+}
+
+func TestCSVWriter_WriteRecord(t *testing.T) {
+	w := bytes.Buffer{}
+	writer := output.NewCSVWriter(&w)
+
+	err := writer.WriteRecord(&output.Record{Address: "127.0.0.1:16260", Command: "players", Response: "Players connected (1):\n-admin"})
+	assert.NoError(t, err)
+
+	err = writer.WriteRecord(&output.Record{Address: "127.0.0.1:16260", Command: "unknown", Error: "execute: failed"})
+	assert.NoError(t, err)
+
+	expected := "address,command,response,error\n" +
+		"127.0.0.1:16260,players,\"Players connected (1):\n-admin\",\n" +
+		"127.0.0.1:16260,unknown,,execute: failed\n"
+	assert.Equal(t, expected, w.String())
+}
+
+func TestCSVWriter_WriteRecord_Parsed(t *testing.T) {
+	w := bytes.Buffer{}
+	writer := output.NewCSVWriter(&w)
+
+	err := writer.WriteRecord(&output.Record{
+		Address: "127.0.0.1:16260",
+		Command: "players",
+		Fields:  []string{"name", "ping"},
+		Parsed:  []map[string]string{{"name": "admin", "ping": "15"}, {"name": "testuser", "ping": "40"}},
+	})
+	assert.NoError(t, err)
+
+	err = writer.WriteRecord(&output.Record{
+		Address: "127.0.0.1:16260",
+		Command: "players",
+		Fields:  []string{"name", "ping"},
+		Error:   "execute: failed",
+	})
+	assert.NoError(t, err)
+
+	expected := "address,command,name,ping,error\n" +
+		"127.0.0.1:16260,players,admin,15,\n" +
+		"127.0.0.1:16260,players,testuser,40,\n" +
+		"127.0.0.1:16260,players,,,execute: failed\n"
+	assert.Equal(t, expected, w.String())
+}
+
+func TestJSONWriter_WriteRecord(t *testing.T) {
+	w := bytes.Buffer{}
+	writer := output.NewJSONWriter(&w)
+
+	err := writer.WriteRecord(&output.Record{Address: "127.0.0.1:16260", Command: "players", Response: "Players connected (0):"})
+	assert.NoError(t, err)
+
+	err = writer.WriteRecord(&output.Record{Address: "127.0.0.1:16260", Command: "unknown", Error: "execute: failed"})
+	assert.NoError(t, err)
+
+	expected := `{"address":"127.0.0.1:16260","command":"players","response":"Players connected (0):"}` + "\n" +
+		`{"address":"127.0.0.1:16260","command":"unknown","response":"","error":"execute: failed"}` + "\n"
+	assert.Equal(t, expected, w.String())
+}
+
+func TestYAMLWriter_WriteRecord(t *testing.T) {
+	w := bytes.Buffer{}
+	writer := output.NewYAMLWriter(&w)
+
+	err := writer.WriteRecord(&output.Record{Address: "127.0.0.1:16260", Command: "players", Response: "Players connected (0):"})
+	assert.NoError(t, err)
+
+	err = writer.WriteRecord(&output.Record{Address: "127.0.0.1:16260", Command: "unknown", Error: "execute: failed"})
+	assert.NoError(t, err)
+
+	expected := "address: 127.0.0.1:16260\ncommand: players\nresponse: 'Players connected (0):'\n" +
+		"---\n" +
+		"address: 127.0.0.1:16260\ncommand: unknown\nresponse: \"\"\nerror: 'execute: failed'\n"
+	assert.Equal(t, expected, w.String())
+}
+
+func TestTiming_String(t *testing.T) {
+	timing := output.Timing{
+		Dial:      output.Milliseconds(1500 * time.Microsecond),
+		Auth:      0.7,
+		RoundTrip: 2,
+	}
+	assert.Equal(t, "dial 1.5ms, auth 0.7ms, round-trip 2ms", timing.String())
+
+	timing = output.Timing{RoundTrip: 2.1}
+	assert.Equal(t, "round-trip 2.1ms", timing.String())
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timing contains durations of request stages in milliseconds. Dial and
+// Auth are set only for the request which established the connection.
+type Timing struct {
+	Dial      float64 `json:"dial_ms,omitempty" yaml:"dial_ms,omitempty"`
+	Auth      float64 `json:"auth_ms,omitempty" yaml:"auth_ms,omitempty"`
+	RoundTrip float64 `json:"round_trip_ms" yaml:"round_trip_ms"`
+}
+
+// Milliseconds converts d to milliseconds with microsecond precision.
+func Milliseconds(d time.Duration) float64 {
+	const microseconds = 1000
+
+	return float64(d.Microseconds()) / microseconds
+}
+
+// String returns durations as text, e.g. "dial 1.5ms, auth 0.7ms,
+// round-trip 2.1ms".
+func (t *Timing) String() string {
+	var parts []string
+
+	if t.Dial != 0 {
+		parts = append(parts, "dial "+formatMilliseconds(t.Dial))
+	}
+
+	if t.Auth != 0 {
+		parts = append(parts, "auth "+formatMilliseconds(t.Auth))
+	}
+
+	parts = append(parts, "round-trip "+formatMilliseconds(t.RoundTrip))
+
+	return strings.Join(parts, ", ")
+}
+
+// formatMilliseconds formats ms with the unit.
+func formatMilliseconds(ms float64) string {
+	return strconv.FormatFloat(ms, 'f', -1, 64) + "ms"
+}
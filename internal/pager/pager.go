@@ -0,0 +1,49 @@
+// Package pager pipes long responses through an external pager program.
+package pager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/terminal"
+)
+
+// DefaultPager is the pager command which is used if $PAGER is not set.
+const DefaultPager = "less"
+
+// Print writes text with trailing new line to w. If w is a terminal and
+// the text does not fit its height the text is shown with the pager
+// program from $PAGER environment variable.
+func Print(w io.Writer, text string) error {
+	if !terminal.IsTerminal(w) || strings.Count(text, "\n")+1 < terminal.Height(w) {
+		_, err := fmt.Fprintln(w, text)
+
+		return err
+	}
+
+	args := strings.Fields(os.Getenv("PAGER"))
+	if len(args) == 0 {
+		args = []string{DefaultPager}
+	}
+
+	// Fall back to plain output if pager program is not installed.
+	if _, err := exec.LookPath(args[0]); err != nil {
+		_, err = fmt.Fprintln(w, text)
+
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // Pager is set by user.
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pager: %w", err)
+	}
+
+	return nil
+}
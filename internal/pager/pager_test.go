@@ -0,0 +1,20 @@
+package pager_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/pager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("not a terminal", func(t *testing.T) {
+		t.Setenv("LINES", "2")
+
+		w := bytes.Buffer{}
+		err := pager.Print(&w, "line 1\nline 2\nline 3")
+		assert.NoError(t, err)
+		assert.Equal(t, "line 1\nline 2\nline 3\n", w.String())
+	})
+}
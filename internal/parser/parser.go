@@ -0,0 +1,68 @@
+// Package parser converts free-text responses from remote servers to
+// structured records.
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Parser extracts records from the response with regular expression.
+// Each match of the expression is a record and capture groups are fields
+// of the record. Parser of the table extracts records from rows, see
+// NewTable.
+type Parser struct {
+	re     *regexp.Regexp
+	table  *table
+	fields []string
+}
+
+// New compiles pattern and returns a new Parser. Named capture groups are
+// used as field names, unnamed groups are named by their index.
+func New(pattern string) (*Parser, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	names := re.SubexpNames()
+	fields := make([]string, 0, len(names))
+
+	for i := 1; i < len(names); i++ {
+		if names[i] == "" {
+			fields = append(fields, strconv.Itoa(i))
+		} else {
+			fields = append(fields, names[i])
+		}
+	}
+
+	return &Parser{re: re, fields: fields}, nil
+}
+
+// Fields returns field names in order of capture groups or columns.
+func (p *Parser) Fields() []string {
+	return p.fields
+}
+
+// Parse returns one record per match of the expression or per row of the
+// table in text.
+func (p *Parser) Parse(text string) []map[string]string {
+	if p.table != nil {
+		return p.table.parse(text)
+	}
+
+	matches := p.re.FindAllStringSubmatch(text, -1)
+	records := make([]map[string]string, 0, len(matches))
+
+	for _, match := range matches {
+		record := make(map[string]string, len(p.fields))
+		for i, field := range p.fields {
+			record[field] = match[i+1]
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
@@ -0,0 +1,145 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("named and unnamed groups", func(t *testing.T) {
+		p, err := parser.New(`(?P<name>\w+) \((\d+)\)`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "2"}, p.Fields())
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		p, err := parser.New(`(?P<name>\w+`)
+		assert.Error(t, err)
+		assert.Nil(t, p)
+	})
+}
+
+func TestParser_Parse(t *testing.T) {
+	response := "Players connected (2):\n-admin\n-testuser"
+
+	t.Run("multiple records", func(t *testing.T) {
+		p, err := parser.New(`(?m)^-(?P<player>.+)$`)
+		assert.NoError(t, err)
+
+		expected := []map[string]string{{"player": "admin"}, {"player": "testuser"}}
+		assert.Equal(t, expected, p.Parse(response))
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		p, err := parser.New(`banned: (?P<count>\d+)`)
+		assert.NoError(t, err)
+		assert.Empty(t, p.Parse(response))
+	})
+}
+
+func TestNewTable(t *testing.T) {
+	status := "hostname: Source Server\nplayers : 2 humans, 0 bots (24 max)\n\n" +
+		"# userid name                uniqueid            connected ping loss state  adr\n" +
+		"#      2 \"Alice Smith\"       [U:1:1234]          05:12       40    0 active 10.0.0.2:27005\r\n" +
+		"#      3 \"Bob\"               [U:1:5678]          00:31       62    1 active 10.0.0.3:27005\n" +
+		"#end\n"
+
+	t.Run("words and quotes", func(t *testing.T) {
+		p, err := parser.NewTable(parser.Table{
+			Header: `^# userid`,
+			Row:    `^#\s+\d`,
+			Columns: []parser.Column{
+				{Name: parser.SkipColumn}, {Name: "userid"}, {Name: "name"}, {Name: "uniqueid"},
+				{Name: "connected"}, {Name: "ping"}, {Name: "loss"}, {Name: "state"}, {Name: "adr"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"userid", "name", "uniqueid", "connected", "ping", "loss", "state", "adr"}, p.Fields())
+
+		expected := []map[string]string{
+			{
+				"userid": "2", "name": "Alice Smith", "uniqueid": "[U:1:1234]", "connected": "05:12",
+				"ping": "40", "loss": "0", "state": "active", "adr": "10.0.0.2:27005",
+			},
+			{
+				"userid": "3", "name": "Bob", "uniqueid": "[U:1:5678]", "connected": "00:31",
+				"ping": "62", "loss": "1", "state": "active", "adr": "10.0.0.3:27005",
+			},
+		}
+		assert.Equal(t, expected, p.Parse(status))
+	})
+
+	t.Run("fixed width and rest of row", func(t *testing.T) {
+		p, err := parser.NewTable(parser.Table{
+			Header:  `^ID`,
+			Columns: []parser.Column{{Name: "id", Width: 4}, {Name: "name", Width: 12}, {Name: "note"}},
+		})
+		assert.NoError(t, err)
+
+		expected := []map[string]string{
+			{"id": "1", "name": "Alice Smith", "note": "admin of the server"},
+			{"id": "22", "name": "Бо", "note": ""},
+		}
+		assert.Equal(t, expected, p.Parse("ID  Name        Note\n1   Alice Smith admin of the server\n\n22  Бо"))
+	})
+
+	t.Run("header is not found", func(t *testing.T) {
+		p, err := parser.NewTable(parser.Table{Header: `^ID`, Columns: []parser.Column{{Name: "id"}}})
+		assert.NoError(t, err)
+		assert.Empty(t, p.Parse(status))
+	})
+
+	t.Run("invalid table", func(t *testing.T) {
+		_, err := parser.NewTable(parser.Table{})
+		assert.ErrorIs(t, err, parser.ErrNoColumns)
+
+		_, err = parser.NewTable(parser.Table{Columns: []parser.Column{{Name: "id"}, {Width: 4}}})
+		assert.ErrorIs(t, err, parser.ErrInvalidColumn)
+		assert.EqualError(t, err, "invalid column 2: name is required and width can not be negative")
+
+		_, err = parser.NewTable(parser.Table{Row: `(`, Columns: []parser.Column{{Name: "id"}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestDefinition(t *testing.T) {
+	expected := map[string]parser.Definition{
+		"players": {Pattern: `^-(?P<name>.+)$`},
+		"status": {Table: &parser.Table{
+			Header:  "^# userid",
+			Columns: []parser.Column{{Name: "-"}, {Name: "userid"}, {Name: "name", Width: 20}},
+		}},
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		var definitions map[string]parser.Definition
+
+		err := yaml.Unmarshal([]byte("players: '^-(?P<name>.+)$'\nstatus:\n  header: '^# userid'\n"+
+			"  columns: ['-', userid, {name: name, width: 20}]\n"), &definitions)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, definitions)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var definitions map[string]parser.Definition
+
+		err := json.Unmarshal([]byte(`{"players": "^-(?P<name>.+)$", "status": {"header": "^# userid", `+
+			`"columns": ["-", "userid", {"name": "name", "width": 20}]}}`), &definitions)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, definitions)
+	})
+
+	t.Run("compile", func(t *testing.T) {
+		p, err := expected["status"].Compile()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"userid", "name"}, p.Fields())
+
+		p, err = expected["players"].Compile()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name"}, p.Fields())
+	})
+}
@@ -0,0 +1,258 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SkipColumn is the name of the column which is not added to records.
+const SkipColumn = "-"
+
+var (
+	// ErrNoColumns is returned when the table has no columns.
+	ErrNoColumns = errors.New("table has no columns")
+
+	// ErrInvalidColumn is returned when the column has no name or negative
+	// width.
+	ErrInvalidColumn = errors.New("invalid column")
+)
+
+// Definition is the parser from the config. It is set as a single string
+// of the regular expression or as a map of the table.
+type Definition struct {
+	Pattern string
+	Table   *Table
+}
+
+// Table describes column-aligned responses like the player list of Source
+// status command. Each row after the header is a record.
+type Table struct {
+	// Header is the regular expression of the header line. Lines before it
+	// and the header are skipped. All lines are rows if it is empty.
+	Header string `json:"header" yaml:"header"`
+	// Row is the regular expression which rows must match, other lines are
+	// skipped. Blank lines are always skipped.
+	Row string `json:"row" yaml:"row"`
+	// Columns are fields of records in order of the columns in rows.
+	Columns []Column `json:"columns" yaml:"columns"`
+}
+
+// Column is the column of the table. In the config column can be set as a
+// single string of the name or as a map with the name and the width.
+//
+// Column with width is cut from the row by the number of characters. Column
+// without width is the next word of the row or the text in double quotes,
+// the last column without width takes the rest of the row.
+type Column struct {
+	Name  string `json:"name" yaml:"name"`
+	Width int    `json:"width" yaml:"width"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Definition) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*d = Definition{Pattern: node.Value}
+
+		return nil
+	}
+
+	var table Table
+	if err := node.Decode(&table); err != nil {
+		return err
+	}
+
+	*d = Definition{Table: &table}
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Definition) UnmarshalJSON(data []byte) error {
+	var pattern string
+	if err := json.Unmarshal(data, &pattern); err == nil {
+		*d = Definition{Pattern: pattern}
+
+		return nil
+	}
+
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return err
+	}
+
+	*d = Definition{Table: &table}
+
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (c *Column) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = Column{Name: node.Value}
+
+		return nil
+	}
+
+	type column Column
+
+	return node.Decode((*column)(c))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Column) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*c = Column{Name: name}
+
+		return nil
+	}
+
+	type column Column
+
+	return json.Unmarshal(data, (*column)(c))
+}
+
+// Compile returns the parser of the definition.
+func (d Definition) Compile() (*Parser, error) {
+	if d.Table == nil {
+		return New(d.Pattern)
+	}
+
+	return NewTable(*d.Table)
+}
+
+// table is the compiled Table.
+type table struct {
+	header  *regexp.Regexp
+	row     *regexp.Regexp
+	columns []Column
+}
+
+// NewTable returns a new Parser of column-aligned responses. Columns named
+// SkipColumn are not added to records.
+func NewTable(t Table) (*Parser, error) {
+	if len(t.Columns) == 0 {
+		return nil, ErrNoColumns
+	}
+
+	compiled := &table{columns: t.Columns}
+
+	var err error
+
+	if t.Header != "" {
+		if compiled.header, err = regexp.Compile(t.Header); err != nil {
+			return nil, fmt.Errorf("compile header: %w", err)
+		}
+	}
+
+	if t.Row != "" {
+		if compiled.row, err = regexp.Compile(t.Row); err != nil {
+			return nil, fmt.Errorf("compile row: %w", err)
+		}
+	}
+
+	fields := make([]string, 0, len(t.Columns))
+
+	for i, column := range t.Columns {
+		if column.Name == "" || column.Width < 0 {
+			return nil, fmt.Errorf("%w %d: name is required and width can not be negative", ErrInvalidColumn, i+1)
+		}
+
+		if column.Name != SkipColumn {
+			fields = append(fields, column.Name)
+		}
+	}
+
+	return &Parser{table: compiled, fields: fields}, nil
+}
+
+// parse returns one record per row of the table in text.
+func (t *table) parse(text string) []map[string]string {
+	lines := strings.Split(text, "\n")
+
+	if t.header != nil {
+		start := len(lines)
+
+		for i, line := range lines {
+			if t.header.MatchString(strings.TrimRight(line, "\r")) {
+				start = i + 1
+
+				break
+			}
+		}
+
+		lines = lines[start:]
+	}
+
+	records := make([]map[string]string, 0, len(lines))
+
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || (t.row != nil && !t.row.MatchString(line)) {
+			continue
+		}
+
+		records = append(records, t.record([]rune(line)))
+	}
+
+	return records
+}
+
+// record splits the row to values of columns.
+func (t *table) record(row []rune) map[string]string {
+	record := make(map[string]string, len(t.columns))
+
+	pos := 0
+
+	for i, column := range t.columns {
+		var value string
+
+		switch {
+		case column.Width > 0:
+			end := min(pos+column.Width, len(row))
+			value = strings.TrimSpace(string(row[pos:end]))
+			pos = end
+		case i == len(t.columns)-1:
+			value = strings.TrimSpace(string(row[pos:]))
+			pos = len(row)
+		default:
+			value, pos = word(row, pos)
+		}
+
+		if column.Name != SkipColumn {
+			record[column.Name] = value
+		}
+	}
+
+	return record
+}
+
+// word returns the next word of the row from pos or the text in double
+// quotes and the position after it.
+func word(row []rune, pos int) (string, int) {
+	for pos < len(row) && unicode.IsSpace(row[pos]) {
+		pos++
+	}
+
+	if pos < len(row) && row[pos] == '"' {
+		end := pos + 1
+		for end < len(row) && row[end] != '"' {
+			end++
+		}
+
+		return string(row[pos+1 : end]), min(end+1, len(row))
+	}
+
+	end := pos
+	for end < len(row) && !unicode.IsSpace(row[end]) {
+		end++
+	}
+
+	return string(row[pos:end]), end
+}
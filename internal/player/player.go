@@ -0,0 +1,145 @@
+// Package player finds players of the server by name in responses of player
+// listing commands.
+package player
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/dictionary"
+)
+
+// Named groups of the player list pattern.
+const (
+	GroupID   = "id"
+	GroupName = "name"
+)
+
+// Placeholders of the follow-up command which are replaced with the ID and
+// the name of the found player.
+const (
+	PlaceholderID   = "{id}"
+	PlaceholderName = "{name}"
+)
+
+var (
+	// ErrNotFound is returned when no player matches the name.
+	ErrNotFound = errors.New("player is not found")
+
+	// ErrAmbiguous is returned when several players match the name.
+	ErrAmbiguous = errors.New("several players match")
+
+	// ErrInvalidPattern is returned when the player list pattern has no name
+	// group.
+	ErrInvalidPattern = errors.New("player list pattern must have (?P<name>...) group")
+)
+
+// Player is the player of the player list.
+type Player struct {
+	ID   string
+	Name string
+}
+
+// Compile compiles the player list pattern which must have the name group.
+// The ID group is optional, the name is used as the ID without it.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	if re.SubexpIndex(GroupName) == -1 {
+		return nil, ErrInvalidPattern
+	}
+
+	return re, nil
+}
+
+// Parse returns players of the response, one per match of re.
+func Parse(re *regexp.Regexp, response string) []Player {
+	id, name := re.SubexpIndex(GroupID), re.SubexpIndex(GroupName)
+
+	matches := re.FindAllStringSubmatch(response, -1)
+	players := make([]Player, 0, len(matches))
+
+	for _, match := range matches {
+		p := Player{Name: strings.TrimSpace(match[name])}
+
+		p.ID = p.Name
+		if id != -1 {
+			p.ID = strings.TrimSpace(match[id])
+		}
+
+		players = append(players, p)
+	}
+
+	return players
+}
+
+// Find returns the player whose name matches query. Exact name, then part
+// of the name and then the name with typos are matched, case is ignored.
+// The error contains names of all matched players if there are several.
+func Find(players []Player, query string) (Player, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	matchers := []func(name string) bool{
+		func(name string) bool { return name == query },
+		func(name string) bool { return strings.Contains(name, query) },
+	}
+
+	for _, match := range matchers {
+		var found []Player
+
+		for _, p := range players {
+			if match(strings.ToLower(p.Name)) {
+				found = append(found, p)
+			}
+		}
+
+		if len(found) != 0 {
+			return single(found, query)
+		}
+	}
+
+	names := make([]string, 0, len(players))
+	for _, p := range players {
+		names = append(names, p.Name)
+	}
+
+	var found []Player
+
+	for _, name := range dictionary.Suggest(names, query) {
+		for _, p := range players {
+			if p.Name == name {
+				found = append(found, p)
+			}
+		}
+	}
+
+	return single(found, query)
+}
+
+// single returns the only found player.
+func single(found []Player, query string) (Player, error) {
+	switch len(found) {
+	case 0:
+		return Player{}, fmt.Errorf("%w: %s", ErrNotFound, query)
+	case 1:
+		return found[0], nil
+	}
+
+	names := make([]string, 0, len(found))
+	for _, p := range found {
+		names = append(names, p.Name)
+	}
+
+	return Player{}, fmt.Errorf("%w %s: %s", ErrAmbiguous, query, strings.Join(names, ", "))
+}
+
+// Command replaces placeholders of the follow-up command with the ID and the
+// name of the player.
+func Command(command string, p Player) string {
+	return strings.NewReplacer(PlaceholderID, p.ID, PlaceholderName, p.Name).Replace(command)
+}
@@ -0,0 +1,86 @@
+package player_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	_, err := player.Compile(`id=(?P<id>\d+), (?P<name>[^,]+)`)
+	assert.NoError(t, err)
+
+	_, err = player.Compile(`id=(?P<id>\d+)`)
+	assert.ErrorIs(t, err, player.ErrInvalidPattern)
+
+	_, err = player.Compile(`(?P<name>`)
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("id and name", func(t *testing.T) {
+		re, err := player.Compile(`id=(?P<id>\d+), (?P<name>[^,]+),`)
+		assert.NoError(t, err)
+
+		response := "1. id=171, Alice Smith, pos=(0.0, 0.0, 0.0)\n2. id=172, Bob, pos=(1.0, 0.0, 0.0)\nTotal of 2 in the game"
+		assert.Equal(t, []player.Player{{ID: "171", Name: "Alice Smith"}, {ID: "172", Name: "Bob"}},
+			player.Parse(re, response))
+	})
+
+	t.Run("name only", func(t *testing.T) {
+		re, err := player.Compile(`(?:: |, )(?P<name>[^,\s]+)`)
+		assert.NoError(t, err)
+
+		response := "There are 2 of a max of 20 players online: Alice, Bob"
+		assert.Equal(t, []player.Player{{ID: "Alice", Name: "Alice"}, {ID: "Bob", Name: "Bob"}},
+			player.Parse(re, response))
+	})
+}
+
+func TestFind(t *testing.T) {
+	players := []player.Player{
+		{ID: "1", Name: "Bob"},
+		{ID: "2", Name: "Bobby"},
+		{ID: "3", Name: "Alice"},
+		{ID: "4", Name: "Alicia Keys"},
+		{ID: "5", Name: "Griefer"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  player.Player
+		err   error
+		msg   string
+	}{
+		{name: "exact name wins over part", query: "bob", want: player.Player{ID: "1", Name: "Bob"}},
+		{name: "part of name", query: "keys", want: player.Player{ID: "4", Name: "Alicia Keys"}},
+		{name: "typo", query: "grefier", want: player.Player{ID: "5", Name: "Griefer"}},
+		{
+			name: "ambiguous part", query: "ali",
+			err: player.ErrAmbiguous, msg: "several players match ali: Alice, Alicia Keys",
+		},
+		{name: "not found", query: "zed", err: player.ErrNotFound, msg: "player is not found: zed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := player.Find(players, test.query)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.EqualError(t, err, test.msg)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, p)
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	p := player.Player{ID: "76561198000000000", Name: "Griefer"}
+	assert.Equal(t, `kick 76561198000000000 "Griefer was kicked"`, player.Command(`kick {id} "{name} was kicked"`, p))
+}
@@ -0,0 +1,133 @@
+// Package policy restricts commands which are allowed to be sent to
+// a remote server.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrCommandDenied is returned when command is forbidden by policy.
+var ErrCommandDenied = errors.New("command is not allowed")
+
+// Policy checks commands against allowed and denied patterns. Patterns
+// are globs matched against the whole command or its first word. Patterns
+// enclosed in slashes like `/^kick .+$/` are regular expressions. Commands
+// are matched case-insensitively without leading slash as game servers
+// ignore the case of them and accept chat command syntax.
+type Policy struct {
+	allowed []matcher
+	denied  []matcher
+}
+
+// matcher reports whether command matches the pattern.
+type matcher func(command string) bool
+
+// New creates a new Policy. Empty allowed list allows all commands which
+// are not denied.
+func New(allowed []string, denied []string) (*Policy, error) {
+	p := new(Policy)
+
+	var err error
+
+	if p.allowed, err = compile(allowed); err != nil {
+		return nil, err
+	}
+
+	if p.denied, err = compile(denied); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Check returns ErrCommandDenied if command matches any denied pattern or
+// does not match any allowed pattern.
+func (p *Policy) Check(command string) error {
+	name := normalize(command)
+
+	for _, match := range p.denied {
+		if match(name) {
+			return fmt.Errorf("%w: %s", ErrCommandDenied, command)
+		}
+	}
+
+	if len(p.allowed) == 0 {
+		return nil
+	}
+
+	for _, match := range p.allowed {
+		if match(name) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrCommandDenied, command)
+}
+
+// Match reports whether command matches any of patterns.
+func Match(patterns []string, command string) (bool, error) {
+	matchers, err := compile(patterns)
+	if err != nil {
+		return false, err
+	}
+
+	command = normalize(command)
+
+	for _, match := range matchers {
+		if match(command) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// normalize removes surrounding spaces and the leading slash of chat command
+// syntax, e.g. /kick.
+func normalize(command string) string {
+	return strings.TrimPrefix(strings.TrimSpace(command), "/")
+}
+
+func compile(patterns []string) ([]matcher, error) {
+	matchers := make([]matcher, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("compile %s: %w", pattern, err)
+			}
+
+			matchers = append(matchers, re.MatchString)
+
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("compile %s: %w", pattern, err)
+		}
+
+		glob := strings.ToLower(pattern)
+		matchers = append(matchers, func(command string) bool {
+			command = strings.ToLower(command)
+			if ok, _ := path.Match(glob, command); ok {
+				return true
+			}
+
+			fields := strings.Fields(command)
+			if len(fields) == 0 {
+				return false
+			}
+
+			ok, _ := path.Match(glob, fields[0])
+
+			return ok
+		})
+	}
+
+	return matchers, nil
+}
@@ -0,0 +1,66 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		command string
+		allow   bool
+	}{
+		{"empty policy", nil, nil, "quit", true},
+		{"allowed command name", []string{"kick", "mute*"}, nil, "kick \"admin\"", true},
+		{"allowed glob", []string{"kick", "mute*"}, nil, "muteall", true},
+		{"not allowed", []string{"kick", "mute*"}, nil, "quit", false},
+		{"denied", nil, []string{"quit", "stop"}, "stop", false},
+		{"denied wins", []string{"*"}, []string{"ban*"}, "banuser admin", false},
+		{"regexp", []string{"/^say .+$/"}, nil, "say hello", true},
+		{"regexp not matched", []string{"/^say .+$/"}, nil, "say", false},
+		{"denied in other case", nil, []string{"quit"}, "QUIT", false},
+		{"allowed regexp in other case", []string{"/^say .+$/"}, nil, "SAY hello", true},
+		{"denied with slash", nil, []string{"quit"}, "/quit", false},
+		{"allowed regexp with slash", []string{"/^kick .+$/"}, nil, "/kick admin", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := policy.New(tt.allowed, tt.denied)
+			assert.NoError(t, err)
+
+			err = p.Check(tt.command)
+			if tt.allow {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, policy.ErrCommandDenied)
+			}
+		})
+	}
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		_, err := policy.New([]string{"/(/"}, nil)
+		assert.Error(t, err)
+
+		_, err = policy.New(nil, []string{"[kick"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMatch(t *testing.T) {
+	ok, err := policy.Match([]string{"stop", "ban*"}, "banall")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = policy.Match([]string{"stop", "ban*"}, "save")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = policy.Match([]string{"[stop"}, "stop")
+	assert.Error(t, err)
+}
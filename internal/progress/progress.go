@@ -0,0 +1,89 @@
+// Package progress draws the progress line of long runs in the terminal.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Bar is the progress line which is redrawn in place with carriage return.
+// Methods of nil Bar do nothing, so callers do not check if progress is
+// enabled. It is safe for concurrent use.
+type Bar struct {
+	mu      sync.Mutex
+	w       io.Writer
+	total   int
+	done    int
+	current string
+	width   int
+}
+
+// New creates a new Bar of total units which draws to w.
+func New(w io.Writer, total int) *Bar {
+	return &Bar{w: w, total: total}
+}
+
+// Current sets the description of the current work, e.g. the command, and
+// redraws the line.
+func (b *Bar) Current(current string) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.current = current
+	b.draw()
+}
+
+// Done marks one unit as done and redraws the line.
+func (b *Bar) Done() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done++
+	b.draw()
+}
+
+// Finish erases the line, so the output after it is not mixed with the
+// progress.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.width != 0 {
+		_, _ = fmt.Fprint(b.w, "\r"+strings.Repeat(" ", b.width)+"\r")
+		b.width = 0
+	}
+}
+
+// draw redraws the line. The previous line is overwritten with spaces, so
+// terminals without ANSI escape sequences are supported too.
+func (b *Bar) draw() {
+	line := fmt.Sprintf("[%d/%d]", b.done, b.total)
+	if b.current != "" {
+		line += " " + b.current
+	}
+
+	width := utf8.RuneCountInString(line)
+
+	padding := ""
+	if width < b.width {
+		padding = strings.Repeat(" ", b.width-width)
+	}
+
+	_, _ = fmt.Fprint(b.w, "\r"+line+padding)
+	b.width = max(b.width, width)
+}
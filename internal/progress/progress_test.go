@@ -0,0 +1,35 @@
+package progress_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBar(t *testing.T) {
+	t.Run("draw", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		bar := progress.New(w, 2)
+		bar.Current("eu1: save-all")
+		bar.Done()
+		bar.Current("eu2: say")
+		bar.Finish()
+
+		assert.Equal(t, "\r[0/2] eu1: save-all"+"\r[1/2] eu1: save-all"+"\r[1/2] eu2: say     "+
+			"\r"+strings.Repeat(" ", 19)+"\r", w.String())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var bar *progress.Bar
+
+		assert.NotPanics(t, func() {
+			bar.Current("status")
+			bar.Done()
+			bar.Finish()
+		})
+	})
+}
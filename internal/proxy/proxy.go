@@ -0,0 +1,221 @@
+// Package proxy implements Source RCON server which passes commands of
+// local clients to a handler, e.g. to forward them to the upstream server.
+package proxy
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// maxBodySize is the maximum size of response body in one packet.
+const maxBodySize = int(rcon.MaxPacketSize - rcon.MinPacketSize)
+
+// DefaultIdleTimeout is the time after which clients which send nothing are
+// disconnected.
+const DefaultIdleTimeout = 5 * time.Minute
+
+var (
+	// ErrInvalidPacket is returned when client sends packet with invalid
+	// size or padding.
+	ErrInvalidPacket = errors.New("invalid packet")
+
+	// ErrEmptyPassword is returned when proxy password is not set.
+	ErrEmptyPassword = errors.New("proxy password is not set")
+)
+
+// Handler executes the command and returns the response.
+type Handler func(command string) (string, error)
+
+// Server accepts RCON clients authenticated with the password and passes
+// their commands to the handler. Handler errors are sent to clients as
+// responses.
+type Server struct {
+	// IdleTimeout is the deadline of each read from the client.
+	IdleTimeout time.Duration
+
+	password string
+	handler  Handler
+}
+
+// NewServer creates a new Server.
+func NewServer(password string, handler Handler) (*Server, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
+
+	return &Server{IdleTimeout: DefaultIdleTimeout, password: password, handler: handler}, nil
+}
+
+// idleConn sets the read deadline before each read, so clients which send
+// nothing for the timeout are disconnected.
+type idleConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+// Read implements io.Reader.
+func (c idleConn) Read(p []byte) (int, error) {
+	_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+	return c.Conn.Read(p) //nolint:wrapcheck // Connection errors are not returned to the user.
+}
+
+// validPassword compares the password of the client with the password of the
+// proxy in constant time, so the password can not be guessed by timing.
+func validPassword(got string, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// Serve accepts connections until the listener is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return fmt.Errorf("proxy: %w", err)
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+
+			_ = s.serveConn(idleConn{Conn: conn, timeout: s.IdleTimeout})
+		}()
+	}
+}
+
+// serveConn handles requests of the client until it disconnects. Client is
+// disconnected after the failed authentication like Source servers do, so
+// the password can not be guessed on one connection.
+func (s *Server) serveConn(conn io.ReadWriter) error {
+	authenticated := false
+
+	for {
+		id, typ, body, err := readPacket(conn)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case typ == rcon.SERVERDATA_AUTH:
+			authenticated = validPassword(body, s.password)
+			if !authenticated {
+				id = -1
+			}
+
+			if err = writePacket(conn, id, rcon.SERVERDATA_RESPONSE_VALUE, ""); err != nil {
+				return err
+			}
+
+			if err = writePacket(conn, id, rcon.SERVERDATA_AUTH_RESPONSE, ""); err != nil || !authenticated {
+				return err
+			}
+		case !authenticated:
+			// Unauthenticated clients are disconnected like Source servers do.
+			return nil
+		case typ == rcon.SERVERDATA_EXECCOMMAND:
+			err = s.execute(conn, id, body)
+		default:
+			// Empty SERVERDATA_RESPONSE_VALUE is used by clients to detect
+			// the end of multi-packet response, so it is mirrored.
+			err = writePacket(conn, id, rcon.SERVERDATA_RESPONSE_VALUE, "")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// execute passes the command to the handler and writes the response split
+// into packets of maximum size.
+func (s *Server) execute(w io.Writer, id int32, command string) error {
+	response, err := s.handler(command)
+	if err != nil {
+		response = err.Error()
+	}
+
+	for {
+		n := min(len(response), maxBodySize)
+		if err = writePacket(w, id, rcon.SERVERDATA_RESPONSE_VALUE, response[:n]); err != nil {
+			return err
+		}
+
+		response = response[n:]
+		if response == "" {
+			return nil
+		}
+	}
+}
+
+// Limit returns handler which calls h not more often than once per interval.
+// Excess commands wait for their turn.
+func Limit(h Handler, interval time.Duration) Handler {
+	var (
+		mu   sync.Mutex
+		next time.Time
+	)
+
+	return func(command string) (string, error) {
+		mu.Lock()
+		now := time.Now()
+		wait := next.Sub(now)
+		next = now.Add(max(wait, 0) + interval)
+		mu.Unlock()
+
+		time.Sleep(wait)
+
+		return h(command)
+	}
+}
+
+// readPacket reads the packet and validates its size and padding.
+func readPacket(r io.Reader) (int32, int32, string, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return 0, 0, "", fmt.Errorf("read size: %w", err)
+	}
+
+	if size < rcon.MinPacketSize || size > rcon.MaxPacketSize {
+		return 0, 0, "", fmt.Errorf("%w: size %d", ErrInvalidPacket, size)
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, 0, "", fmt.Errorf("read packet: %w", err)
+	}
+
+	if buf[size-2] != 0 || buf[size-1] != 0 {
+		return 0, 0, "", fmt.Errorf("%w: padding", ErrInvalidPacket)
+	}
+
+	id := int32(binary.LittleEndian.Uint32(buf[0:4]))
+	typ := int32(binary.LittleEndian.Uint32(buf[4:8]))
+
+	return id, typ, string(buf[8 : size-2]), nil
+}
+
+// writePacket writes the packet.
+func writePacket(w io.Writer, id int32, typ int32, body string) error {
+	if _, err := rcon.NewPacket(typ, id, body).WriteTo(w); err != nil {
+		return fmt.Errorf("write packet: %w", err)
+	}
+
+	return nil
+}
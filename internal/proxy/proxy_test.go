@@ -0,0 +1,223 @@
+package proxy_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/proxy"
+	"github.com/gorcon/telnet"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer(t *testing.T) {
+	_, err := proxy.NewServer("", nil)
+	assert.ErrorIs(t, err, proxy.ErrEmptyPassword)
+}
+
+func TestServer_Serve(t *testing.T) {
+	server, err := proxy.NewServer("secret", func(command string) (string, error) {
+		switch command {
+		case "fail":
+			return "", errors.New("denied")
+		case "long":
+			return strings.Repeat("a", 5000), nil
+		default:
+			return "echo " + command, nil
+		}
+	})
+	assert.NoError(t, err)
+
+	server.IdleTimeout = 500 * time.Millisecond
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, server.Serve(listener))
+	}()
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := rcon.Dial(listener.Addr().String(), "wrong")
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		// Client is disconnected after the failed attempt before idle timeout.
+		_ = conn.SetReadDeadline(time.Now().Add(server.IdleTimeout / 2))
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH, 1, "wrong").WriteTo(conn)
+
+		response, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2*(4+int(rcon.MinPacketSize)))
+	})
+
+	t.Run("idle client", func(t *testing.T) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		response, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+		assert.Empty(t, response)
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		conn, err := rcon.Dial(listener.Addr().String(), "secret")
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		response, err := conn.Execute("status")
+		assert.NoError(t, err)
+		assert.Equal(t, "echo status", response)
+
+		response, err = conn.Execute("fail")
+		assert.NoError(t, err)
+		assert.Equal(t, "denied", response)
+
+		response, err = conn.Execute("long")
+		assert.NoError(t, err)
+		assert.Len(t, response, 4096)
+	})
+
+	listener.Close()
+	wg.Wait()
+}
+
+func TestLimit(t *testing.T) {
+	calls := 0
+	h := proxy.Limit(func(string) (string, error) {
+		calls++
+
+		return "", nil
+	}, 50*time.Millisecond)
+
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		_, _ = h("status")
+	}
+
+	assert.Equal(t, 3, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestWebServer_Serve(t *testing.T) {
+	_, err := proxy.NewWebServer("", nil)
+	assert.ErrorIs(t, err, proxy.ErrEmptyPassword)
+
+	server, err := proxy.NewWebServer("secret", func(command string) (string, error) {
+		if command == "fail" {
+			return "", errors.New("denied")
+		}
+
+		return "echo " + command, nil
+	})
+	assert.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, server.Serve(listener))
+	}()
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/wrong", nil)
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/secret", nil)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		var response proxy.Message
+
+		assert.NoError(t, conn.WriteJSON(proxy.Message{Message: "status", Identifier: 42}))
+		assert.NoError(t, conn.ReadJSON(&response))
+		assert.Equal(t, proxy.Message{Message: "echo status", Identifier: 42, Type: "Generic"}, response)
+
+		assert.NoError(t, conn.WriteJSON(proxy.Message{Message: "fail", Identifier: 43}))
+		assert.NoError(t, conn.ReadJSON(&response))
+		assert.Equal(t, proxy.Message{Message: "denied", Identifier: 43, Type: "Error"}, response)
+	})
+
+	listener.Close()
+	wg.Wait()
+}
+
+func TestTelnetServer_Serve(t *testing.T) {
+	_, err := proxy.NewTelnetServer("", nil)
+	assert.ErrorIs(t, err, proxy.ErrEmptyPassword)
+
+	server, err := proxy.NewTelnetServer("secret", func(command string) (string, error) {
+		if command == "fail" {
+			return "", errors.New("denied")
+		}
+
+		return "echo\n" + command, nil
+	})
+	assert.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, server.Serve(listener))
+	}()
+
+	t.Run("wrong password", func(t *testing.T) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("a\r\nb\r\nc\r\n"))
+
+		response, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, "Please enter password:\r\n"+
+			strings.Repeat(telnet.ResponseAuthIncorrectPassword+"\r\n", 3)+
+			telnet.ResponseAuthTooManyFails+"\r\n", string(response))
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("secret\r\nstatus\r\n\r\nfail\r\nexit\r\n"))
+
+		response, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, "Please enter password:\r\n"+
+			telnet.ResponseAuthSuccess+"\r\n\r\n"+telnet.ResponseWelcome+"\r\n"+
+			"echo\r\nstatus\r\ndenied\r\n", string(response))
+	})
+
+	listener.Close()
+	wg.Wait()
+}
@@ -0,0 +1,118 @@
+package proxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorcon/telnet"
+)
+
+// maxAuthAttempts is the number of password attempts before disconnect.
+const maxAuthAttempts = 3
+
+// TelnetServer accepts telnet clients like 7 Days to Die server does. Each
+// line is a command and responses are written back line by line.
+type TelnetServer struct {
+	// IdleTimeout is the deadline of each read from the client.
+	IdleTimeout time.Duration
+
+	password string
+	handler  Handler
+}
+
+// NewTelnetServer creates a new TelnetServer.
+func NewTelnetServer(password string, handler Handler) (*TelnetServer, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
+
+	return &TelnetServer{IdleTimeout: DefaultIdleTimeout, password: password, handler: handler}, nil
+}
+
+// Serve accepts connections until the listener is closed.
+func (s *TelnetServer) Serve(listener net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return fmt.Errorf("proxy: %w", err)
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+
+			_ = s.serveConn(idleConn{Conn: conn, timeout: s.IdleTimeout})
+		}()
+	}
+}
+
+// serveConn authenticates the client and handles its commands until it
+// disconnects or sends exit command.
+func (s *TelnetServer) serveConn(conn io.ReadWriter) error {
+	scanner := bufio.NewScanner(conn)
+
+	if !s.auth(conn, scanner) {
+		return nil
+	}
+
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+
+		switch command {
+		case "":
+			continue
+		case telnet.DefaultExitCommand:
+			return nil
+		}
+
+		response, err := s.handler(command)
+		if err != nil {
+			response = err.Error()
+		}
+
+		response = strings.ReplaceAll(strings.ReplaceAll(response, "\r\n", "\n"), "\n", telnet.CRLF)
+		if _, err = io.WriteString(conn, response+telnet.CRLF); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+
+	return scanner.Err() //nolint:wrapcheck // Connection errors are not returned to the user.
+}
+
+// auth asks password until it is correct or attempts are over.
+func (s *TelnetServer) auth(w io.Writer, scanner *bufio.Scanner) bool {
+	_, _ = io.WriteString(w, telnet.ResponseEnterPassword+":"+telnet.CRLF)
+
+	for i := 0; i < maxAuthAttempts; i++ {
+		if !scanner.Scan() {
+			return false
+		}
+
+		if validPassword(strings.TrimSpace(scanner.Text()), s.password) {
+			_, _ = io.WriteString(w, telnet.ResponseAuthSuccess+telnet.CRLF+telnet.CRLF+telnet.ResponseWelcome+telnet.CRLF)
+
+			return true
+		}
+
+		_, _ = io.WriteString(w, telnet.ResponseAuthIncorrectPassword+telnet.CRLF)
+	}
+
+	_, _ = io.WriteString(w, telnet.ResponseAuthTooManyFails+telnet.CRLF)
+
+	return false
+}
@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// readHeaderTimeout limits time to read HTTP request of WebSocket handshake.
+const readHeaderTimeout = 10 * time.Second
+
+// Message is WebRCON request and response. Requests have only Message and
+// Identifier fields set.
+type Message struct {
+	Message    string `json:"Message"`
+	Identifier int    `json:"Identifier"`
+	Type       string `json:"Type"`
+	Stacktrace string `json:"Stacktrace"`
+}
+
+// WebServer accepts WebRCON clients like Rust server does. The password is
+// passed in the path of WebSocket URL.
+type WebServer struct {
+	// IdleTimeout is the deadline of each read from the client.
+	IdleTimeout time.Duration
+
+	password string
+	handler  Handler
+	upgrader websocket.Upgrader
+}
+
+// NewWebServer creates a new WebServer.
+func NewWebServer(password string, handler Handler) (*WebServer, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
+
+	// Web panels are served from other origins, so origin is not checked.
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+	return &WebServer{IdleTimeout: DefaultIdleTimeout, password: password, handler: handler, upgrader: upgrader}, nil
+}
+
+// Serve accepts connections until the listener is closed.
+func (s *WebServer) Serve(listener net.Listener) error {
+	server := http.Server{Handler: s, ReadHeaderTimeout: readHeaderTimeout}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("proxy: %w", err)
+	}
+
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	password, _ := strings.CutPrefix(r.URL.Path, "/")
+	if !validPassword(password, s.password) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(s.IdleTimeout))
+
+		var request Message
+		if err = conn.ReadJSON(&request); err != nil {
+			return
+		}
+
+		response := Message{Identifier: request.Identifier, Type: "Generic"}
+		if response.Message, err = s.handler(request.Message); err != nil {
+			response.Message = err.Error()
+			response.Type = "Error"
+		}
+
+		if err = conn.WriteJSON(response); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package pushgateway pushes metrics of one-shot runs to Prometheus
+// Pushgateway.
+package pushgateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultJob is the job name used if the URL has no grouping path.
+const DefaultJob = "rcon"
+
+// ErrPush is returned when Pushgateway rejects metrics.
+var ErrPush = errors.New("push metrics")
+
+// URL returns the push URL. If the URL has no path, metrics are grouped
+// by the default job: http://gateway:9091/metrics/job/rcon.
+func URL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrPush, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: unsupported URL scheme %q", ErrPush, u.Scheme)
+	}
+
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = "/metrics/job/" + DefaultJob
+	}
+
+	return u.String(), nil
+}
+
+// Push sends metrics in Prometheus text format with POST request, so only
+// metrics with the same names are replaced in the group.
+func Push(rawURL string, metrics []byte, timeout time.Duration) error {
+	pushURL, err := URL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, pushURL, bytes.NewReader(metrics))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPush, err)
+	}
+
+	request.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPush, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 { //nolint:gomnd // Any 2xx status is success.
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512)) //nolint:gomnd // Error message is short.
+
+		return fmt.Errorf("%w: %s: %s", ErrPush, response.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
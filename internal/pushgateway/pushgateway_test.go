@@ -0,0 +1,50 @@
+package pushgateway_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/pushgateway"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	u, err := pushgateway.URL("http://gateway:9091")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://gateway:9091/metrics/job/rcon", u)
+
+	u, err = pushgateway.URL("http://gateway:9091/metrics/job/backup/instance/eu1")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://gateway:9091/metrics/job/backup/instance/eu1", u)
+
+	_, err = pushgateway.URL("gateway:9091")
+	assert.ErrorIs(t, err, pushgateway.ErrPush)
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+
+		if r.URL.Path == "/metrics/job/broken" {
+			http.Error(w, "invalid metric", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	err := pushgateway.Push(server.URL, []byte("rcon_run_success 1\n"), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/metrics/job/rcon", path)
+	assert.Equal(t, "rcon_run_success 1\n", body)
+
+	err = pushgateway.Push(server.URL+"/metrics/job/broken", []byte("rcon_run_success 1\n"), time.Second)
+	assert.ErrorIs(t, err, pushgateway.ErrPush)
+	assert.ErrorContains(t, err, "400 Bad Request: invalid metric")
+}
@@ -0,0 +1,90 @@
+// Package redact removes passwords from text which is printed to the user,
+// e.g. error messages, panics, debug output and traces.
+//
+// Passwords are registered with Add when they are received from flags,
+// environment variables, the config or prompts. Secrets shorter than
+// MinLength are not registered, so short passwords do not mask every
+// occurrence of common letters and digits.
+package redact
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// Mask replaces secrets in text.
+const Mask = "********"
+
+// MinLength is the minimum length of registered secrets.
+const MinLength = 3
+
+//nolint:gochecknoglobals // Secrets are registered by the whole process.
+var (
+	mu      sync.RWMutex
+	secrets []string
+)
+
+// Add registers the secret which is removed from text.
+func Add(secret string) {
+	if len(secret) < MinLength {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+
+	secrets = append(secrets, secret)
+}
+
+// Reset forgets all registered secrets.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	secrets = nil
+}
+
+// String replaces registered secrets in s with Mask.
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+
+	return s
+}
+
+// Bytes returns copy of b in which registered secrets are replaced with
+// asterisks of the same length, so sizes of binary packets stay valid.
+func Bytes(b []byte) []byte {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, secret := range secrets {
+		if !bytes.Contains(b, []byte(secret)) {
+			continue
+		}
+
+		b = bytes.ReplaceAll(b, []byte(secret), bytes.Repeat([]byte("*"), len(secret)))
+	}
+
+	return b
+}
+
+// Error returns the message of err with registered secrets replaced.
+func Error(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return String(err.Error())
+}
@@ -0,0 +1,39 @@
+package redact_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	t.Cleanup(redact.Reset)
+
+	redact.Add("s3cr3t")
+	redact.Add("s3cr3t")
+	redact.Add("ab")
+
+	t.Run("string", func(t *testing.T) {
+		assert.Equal(t, "dial ws://127.0.0.1:28016/"+redact.Mask+": refused",
+			redact.String("dial ws://127.0.0.1:28016/s3cr3t: refused"))
+		assert.Equal(t, "abc", redact.String("abc"))
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		data := []byte("GET /s3cr3t HTTP/1.1")
+		assert.Equal(t, []byte("GET /****** HTTP/1.1"), redact.Bytes(data))
+		assert.Equal(t, []byte("GET /s3cr3t HTTP/1.1"), data)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		assert.Equal(t, "auth "+redact.Mask, redact.Error(errors.New("auth s3cr3t")))
+		assert.Equal(t, "", redact.Error(nil))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		redact.Reset()
+		assert.Equal(t, "s3cr3t", redact.String("s3cr3t"))
+	})
+}
@@ -0,0 +1,72 @@
+// Package scheduler runs recurring jobs by crontab schedules.
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/cron"
+)
+
+// Job is the recurring job.
+type Job struct {
+	// Schedule is the crontab schedule of the job.
+	Schedule *cron.Schedule
+	// Jitter delays each run for random duration up to jitter.
+	Jitter time.Duration
+	// Run is called at scheduled times. Runs of the job do not overlap.
+	Run func()
+}
+
+// Next returns the time of the next run after now: the next minute of the
+// schedule delayed for random duration up to jitter. Returns zero time if
+// the schedule never matches.
+func Next(schedule *cron.Schedule, jitter time.Duration, now time.Time) time.Time {
+	next := schedule.Next(now)
+	if next.IsZero() || jitter <= 0 {
+		return next
+	}
+
+	return next.Add(time.Duration(rand.Int63n(int64(jitter)))) //nolint:gosec // Jitter is not a secret.
+}
+
+// Run runs jobs until ctx is done. Each job waits for its next run
+// independently, so slow jobs do not delay others.
+func Run(ctx context.Context, jobs []Job) {
+	var wg sync.WaitGroup
+
+	for _, job := range jobs {
+		wg.Add(1)
+
+		go func(job Job) {
+			defer wg.Done()
+
+			run(ctx, job)
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+// run runs the job at scheduled times until ctx is done.
+func run(ctx context.Context, job Job) {
+	for {
+		next := Next(job.Schedule, job.Jitter, time.Now())
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+			job.Run()
+		}
+	}
+}
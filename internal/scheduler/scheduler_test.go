@@ -0,0 +1,53 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/cron"
+	"github.com/gorcon/rcon-cli/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNext(t *testing.T) {
+	schedule, err := cron.Parse("0 */2 * * *")
+	assert.NoError(t, err)
+
+	now := time.Date(2024, 1, 8, 3, 15, 0, 0, time.UTC)
+	base := time.Date(2024, 1, 8, 4, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, base, scheduler.Next(schedule, 0, now))
+
+	for i := 0; i < 100; i++ {
+		next := scheduler.Next(schedule, 30*time.Second, now)
+		assert.False(t, next.Before(base))
+		assert.True(t, next.Before(base.Add(30*time.Second)))
+	}
+
+	never, err := cron.Parse("0 0 30 2 *")
+	assert.NoError(t, err)
+	assert.True(t, scheduler.Next(never, time.Minute, now).IsZero())
+}
+
+func TestRun(t *testing.T) {
+	schedule, err := cron.Parse("* * * * *")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		scheduler.Run(ctx, []scheduler.Job{{Schedule: schedule, Run: func() {}}})
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler is not stopped")
+	}
+}
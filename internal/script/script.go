@@ -0,0 +1,261 @@
+// Package script runs Lua scripts with API for executing commands on remote
+// servers.
+package script
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/extract"
+	"github.com/gorcon/rcon-cli/internal/parser"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ErrInvalidDuration is returned when sleep is called with wrong argument.
+var ErrInvalidDuration = errors.New("invalid duration")
+
+// ExecuteFunc sends command to the server from config environment env and
+// returns the response.
+type ExecuteFunc func(env string, command string) (string, error)
+
+// ExpectFunc sends command to the server from config environment env and
+// waits until its response or the console output has the line matching
+// pattern. It returns the matched line. Zero timeout means the default one.
+type ExpectFunc func(env, command, pattern string, timeout time.Duration) (string, error)
+
+// Script is Lua script runner.
+type Script struct {
+	w       io.Writer
+	execute ExecuteFunc
+	expect  ExpectFunc
+	parsers map[string]parser.Definition
+}
+
+// New creates a new Script. Responses of execute and expect are available
+// in scripts, print writes to w and parsers are used by name in parse
+// function.
+func New(w io.Writer, execute ExecuteFunc, expect ExpectFunc, parsers map[string]parser.Definition) *Script {
+	return &Script{w: w, execute: execute, expect: expect, parsers: parsers}
+}
+
+// RunFile executes Lua script from the file.
+func (s *Script) RunFile(name string) error {
+	state := s.state()
+	defer state.Close()
+
+	if err := state.DoFile(name); err != nil {
+		return fmt.Errorf("script: %w", err)
+	}
+
+	return nil
+}
+
+// RunString executes Lua script from the string.
+func (s *Script) RunString(source string) error {
+	state := s.state()
+	defer state.Close()
+
+	if err := state.DoString(source); err != nil {
+		return fmt.Errorf("script: %w", err)
+	}
+
+	return nil
+}
+
+// state creates Lua state with registered API functions.
+func (s *Script) state() *lua.LState {
+	state := lua.NewState()
+
+	state.SetGlobal("execute", state.NewFunction(s.luaExecute))
+	state.SetGlobal("expect", state.NewFunction(s.luaExpect))
+	state.SetGlobal("sleep", state.NewFunction(s.luaSleep))
+	state.SetGlobal("print", state.NewFunction(s.luaPrint))
+	state.SetGlobal("parse", state.NewFunction(s.luaParse))
+	state.SetGlobal("capture", state.NewFunction(s.luaCapture))
+	state.SetGlobal("extract", state.NewFunction(s.luaExtract))
+
+	return state
+}
+
+// luaExecute implements `response, err = execute(env, command)`. Empty env
+// means the environment chosen at the start.
+func (s *Script) luaExecute(state *lua.LState) int {
+	env := state.CheckString(1)
+	command := state.CheckString(2)
+
+	response, err := s.execute(env, command)
+	if err != nil {
+		state.Push(lua.LString(response))
+		state.Push(lua.LString(err.Error()))
+
+		return 2
+	}
+
+	state.Push(lua.LString(response))
+	state.Push(lua.LNil)
+
+	return 2
+}
+
+// luaExpect implements `line, err = expect(env, command, pattern, timeout)`.
+// Timeout is optional, it is the number of seconds or the duration string.
+func (s *Script) luaExpect(state *lua.LState) int {
+	env := state.CheckString(1)
+	command := state.CheckString(2)
+	pattern := state.CheckString(3)
+
+	var timeout time.Duration
+	if state.GetTop() > 3 { //nolint:gomnd // Timeout is the fourth argument.
+		var ok bool
+		if timeout, ok = duration(state, 4); !ok { //nolint:gomnd // Timeout is the fourth argument.
+			return 0
+		}
+	}
+
+	line, err := s.expect(env, command, pattern, timeout)
+	if err != nil {
+		state.Push(lua.LNil)
+		state.Push(lua.LString(err.Error()))
+
+		return 2
+	}
+
+	state.Push(lua.LString(line))
+	state.Push(lua.LNil)
+
+	return 2
+}
+
+// luaSleep implements `sleep(seconds)` and `sleep("1m30s")`.
+func (s *Script) luaSleep(state *lua.LState) int {
+	if d, ok := duration(state, 1); ok {
+		time.Sleep(d)
+	}
+
+	return 0
+}
+
+// duration returns argument n which is the number of seconds or the duration
+// string. Invalid argument raises the error and returns false.
+func duration(state *lua.LState, n int) (time.Duration, bool) {
+	switch value := state.Get(n).(type) {
+	case lua.LNumber:
+		return time.Duration(float64(value) * float64(time.Second)), true
+	case lua.LString:
+		d, err := time.ParseDuration(string(value))
+		if err != nil {
+			state.RaiseError("%s: %s", ErrInvalidDuration, err)
+
+			return 0, false
+		}
+
+		return d, true
+	default:
+		state.RaiseError("%s: %s", ErrInvalidDuration, value.String())
+
+		return 0, false
+	}
+}
+
+// luaPrint implements `print(...)`. Arguments are separated by tab.
+func (s *Script) luaPrint(state *lua.LState) int {
+	args := make([]string, 0, state.GetTop())
+	for i := 1; i <= state.GetTop(); i++ {
+		args = append(args, state.ToStringMeta(state.Get(i)).String())
+	}
+
+	_, _ = fmt.Fprintln(s.w, strings.Join(args, "\t"))
+
+	return 0
+}
+
+// luaParse implements `records = parse(parser, text)`. Parser is the name
+// from the config parsers section or a regular expression.
+func (s *Script) luaParse(state *lua.LState) int {
+	pattern := state.CheckString(1)
+	text := state.CheckString(2)
+
+	definition, ok := s.parsers[pattern]
+	if !ok {
+		definition = parser.Definition{Pattern: pattern}
+	}
+
+	p, err := definition.Compile()
+	if err != nil {
+		state.RaiseError("parse: %s", err)
+
+		return 0
+	}
+
+	records := state.NewTable()
+
+	for _, record := range p.Parse(text) {
+		row := state.NewTable()
+		for field, value := range record {
+			row.RawSetString(field, lua.LString(value))
+		}
+
+		records.Append(row)
+	}
+
+	state.Push(records)
+
+	return 1
+}
+
+// luaCapture implements `vars = capture(pattern, text)`. Named groups of the
+// first match of the regular expression are fields of vars, vars is nil if
+// text does not match.
+func (s *Script) luaCapture(state *lua.LState) int {
+	pattern := state.CheckString(1)
+	text := state.CheckString(2)
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		state.RaiseError("capture: %s", err)
+
+		return 0
+	}
+
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		state.Push(lua.LNil)
+
+		return 1
+	}
+
+	vars := state.NewTable()
+
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			vars.RawSetString(name, lua.LString(match[i]))
+		}
+	}
+
+	state.Push(vars)
+
+	return 1
+}
+
+// luaExtract implements `value, err = extract(json, path)`.
+func (s *Script) luaExtract(state *lua.LState) int {
+	data := state.CheckString(1)
+	path := state.CheckString(2)
+
+	value, err := extract.Extract(data, path)
+	if err != nil {
+		state.Push(lua.LNil)
+		state.Push(lua.LString(err.Error()))
+
+		return 2
+	}
+
+	state.Push(lua.LString(value))
+	state.Push(lua.LNil)
+
+	return 2
+}
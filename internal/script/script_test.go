@@ -0,0 +1,136 @@
+package script_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/parser"
+	"github.com/gorcon/rcon-cli/internal/script"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+func execute(env string, command string) (string, error) {
+	switch command {
+	case "players":
+		return "Players connected (2):\n-admin\n-guest", nil
+	case "serverinfo":
+		return `{"Hostname": "Rust Server", "Players": 2}`, nil
+	case "env":
+		return env, nil
+	default:
+		return "", errUnknownCommand
+	}
+}
+
+func expect(env, command, pattern string, timeout time.Duration) (string, error) {
+	if command != "save" || pattern != "Saved" {
+		return "", errUnknownCommand
+	}
+
+	return fmt.Sprintf("Saved %s in %s", env, timeout), nil
+}
+
+func TestScript(t *testing.T) {
+	parsers := map[string]parser.Definition{"players": {Pattern: `(?m)^-(?P<name>.+)$`}}
+
+	t.Run("execute and print", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunString(`
+			local response, err = execute("rust", "env")
+			print(response, err)
+			response, err = execute("", "unknown")
+			print(err)
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "rust\tnil\nunknown command\n", w.String())
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunString(`
+			local players = parse("players", execute("", "players"))
+			for i, player in ipairs(players) do
+				print(i, player.name)
+			end
+			local words = parse("(?P<word>Players) (\\w+)", execute("", "players"))
+			print(words[1].word, words[1]["2"])
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "1\tadmin\n2\tguest\nPlayers\tconnected\n", w.String())
+	})
+
+	t.Run("capture", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunString(`
+			local vars = capture("-(?P<first>\\w+)\n-(?P<second>\\w+)", execute("", "players"))
+			print(execute(vars.first, "env"), vars.second)
+			print(capture("(?P<banned>banned)", execute("", "players")))
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "admin\tguest\nnil\n", w.String())
+
+		err = script.New(&w, execute, expect, parsers).RunString(`capture("(", "text")`)
+		assert.Error(t, err)
+	})
+
+	t.Run("extract", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunString(`
+			print(extract(execute("", "serverinfo"), ".Hostname"))
+			local _, err = extract("text", ".Hostname")
+			print(err ~= nil)
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Rust Server\tnil\ntrue\n", w.String())
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunString(`
+			print(expect("rust", "save", "Saved", "1m"))
+			print(expect("", "save", "Saved", 2))
+			print(expect("", "save", "Saving"))
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Saved rust in 1m0s\tnil\nSaved  in 2s\tnil\nnil\tunknown command\n", w.String())
+
+		err = script.New(&w, execute, expect, parsers).RunString(`expect("", "save", "Saved", "soon")`)
+		assert.ErrorContains(t, err, script.ErrInvalidDuration.Error())
+	})
+
+	t.Run("sleep", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunString(`sleep(0.01) sleep("10ms")`)
+		assert.NoError(t, err)
+
+		err = script.New(&w, execute, expect, parsers).RunString(`sleep("forever")`)
+		assert.Error(t, err)
+	})
+
+	t.Run("run file", func(t *testing.T) {
+		scriptFileName := "test-script.lua"
+		assert.NoError(t, os.WriteFile(scriptFileName, []byte(`print(execute("", "env"))`), 0o600))
+		defer os.Remove(scriptFileName)
+
+		w := bytes.Buffer{}
+
+		err := script.New(&w, execute, expect, parsers).RunFile(scriptFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "\tnil\n", w.String())
+
+		err = script.New(&w, execute, expect, parsers).RunFile("nonexistent.lua")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+// Package secret resolves references to secrets of password manager CLIs
+// which are used in the config instead of plain passwords:
+//
+//	op://vault/item/field   1Password, read with `op read`;
+//	bw://item               Bitwarden, read with `bw get password`.
+//
+// The CLIs must be installed and unlocked, e.g. with `op signin` and
+// BW_SESSION variable of `bw unlock`.
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Prefixes of secret references.
+const (
+	PrefixOnePassword = "op://"
+	PrefixBitwarden   = "bw://"
+)
+
+var (
+	// ErrResolve is returned when password manager CLI can not read the
+	// secret.
+	ErrResolve = errors.New("resolve secret")
+
+	// ErrEmptyReference is returned when the reference has no item.
+	ErrEmptyReference = errors.New("empty secret reference")
+)
+
+// IsReference returns true if value is a reference to a password manager
+// secret.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, PrefixOnePassword) || strings.HasPrefix(value, PrefixBitwarden)
+}
+
+// Resolve returns the secret which value references. Values which are not
+// references are returned as is.
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, PrefixOnePassword):
+		if value == PrefixOnePassword {
+			return "", fmt.Errorf("%w: %s", ErrEmptyReference, value)
+		}
+
+		return read("op", "read", "--no-newline", value)
+	case strings.HasPrefix(value, PrefixBitwarden):
+		item := strings.TrimPrefix(value, PrefixBitwarden)
+		if item == "" {
+			return "", fmt.Errorf("%w: %s", ErrEmptyReference, value)
+		}
+
+		return read("bw", "get", "password", item)
+	default:
+		return value, nil
+	}
+}
+
+// read runs password manager CLI and returns its output. The output of the
+// CLI is not included in errors, only its stderr.
+func read(name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("%w with %s: %s", ErrResolve, name, detail)
+		}
+
+		return "", fmt.Errorf("%w with %s: %s", ErrResolve, name, err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
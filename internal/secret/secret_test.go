@@ -0,0 +1,64 @@
+package secret_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCLI creates program which prints its arguments or fails with the
+// message to stderr if arguments contain fail.
+func fakeCLI(t *testing.T, name string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncase \"$*\" in *fail*) echo \"item not found\" >&2; exit 1;; esac\necho \"$*\"\n"
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0o700)) //nolint:gosec // Test program.
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		value, err := secret.Resolve("password")
+		assert.NoError(t, err)
+		assert.Equal(t, "password", value)
+		assert.False(t, secret.IsReference("password"))
+	})
+
+	t.Run("1password", func(t *testing.T) {
+		fakeCLI(t, "op")
+
+		value, err := secret.Resolve("op://servers/rust/password")
+		assert.NoError(t, err)
+		assert.Equal(t, "read --no-newline op://servers/rust/password", value)
+		assert.True(t, secret.IsReference("op://servers/rust/password"))
+	})
+
+	t.Run("bitwarden", func(t *testing.T) {
+		fakeCLI(t, "bw")
+
+		value, err := secret.Resolve("bw://rust-rcon")
+		assert.NoError(t, err)
+		assert.Equal(t, "get password rust-rcon", value)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		fakeCLI(t, "bw")
+
+		_, err := secret.Resolve("bw://fail")
+		assert.ErrorIs(t, err, secret.ErrResolve)
+		assert.EqualError(t, err, "resolve secret with bw: item not found")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := secret.Resolve("op://")
+		assert.ErrorIs(t, err, secret.ErrEmptyReference)
+
+		_, err = secret.Resolve("bw://")
+		assert.ErrorIs(t, err, secret.ErrEmptyReference)
+	})
+}
@@ -0,0 +1,72 @@
+// Package statsd sends metrics to StatsD or DogStatsD server over UDP.
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/output"
+)
+
+// DefaultPrefix is the prefix of metric names.
+const DefaultPrefix = "rcon"
+
+// tagReplacer replaces characters which separate fields and tags of
+// DogStatsD datagrams.
+//
+//nolint:gochecknoglobals // Replacer is immutable.
+var tagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+
+// Client sends metrics. It is safe for concurrent use.
+type Client struct {
+	conn   net.Conn
+	prefix string
+	tags   bool
+}
+
+// New creates a new Client. If tags is true, tags are sent in DogStatsD
+// format, otherwise they are dropped because plain StatsD does not support
+// them.
+func New(address string, prefix string, tags bool) (*Client, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("statsd: %w", err)
+	}
+
+	return &Client{conn: conn, prefix: prefix, tags: tags}, nil
+}
+
+// Timing sends the duration in milliseconds.
+func (c *Client) Timing(name string, d time.Duration, tags ...string) {
+	c.send(name, strconv.FormatFloat(output.Milliseconds(d), 'f', -1, 64), "ms", tags)
+}
+
+// Count increments the counter by n.
+func (c *Client) Count(name string, n int, tags ...string) {
+	c.send(name, strconv.Itoa(n), "c", tags)
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close() //nolint:wrapcheck // Close error is not returned to the user.
+}
+
+// send writes the datagram. Errors are ignored, metrics must not break
+// commands.
+func (c *Client) send(name string, value string, typ string, tags []string) {
+	datagram := c.prefix + "." + name + ":" + value + "|" + typ
+
+	if c.tags && len(tags) != 0 {
+		replaced := make([]string, len(tags))
+		for i, tag := range tags {
+			replaced[i] = tagReplacer.Replace(tag)
+		}
+
+		datagram += "|#" + strings.Join(replaced, ",")
+	}
+
+	_, _ = c.conn.Write([]byte(datagram))
+}
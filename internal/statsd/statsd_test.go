@@ -0,0 +1,56 @@
+package statsd_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/statsd"
+	"github.com/stretchr/testify/assert"
+)
+
+// listen returns UDP server and function which reads the next datagram.
+func listen(t *testing.T) (string, func() string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn.LocalAddr().String(), func() string {
+		buf := make([]byte, 512)
+
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, _ := conn.ReadFrom(buf)
+
+		return string(buf[:n])
+	}
+}
+
+func TestClient(t *testing.T) {
+	t.Run("statsd", func(t *testing.T) {
+		address, read := listen(t)
+
+		client, err := statsd.New(address, statsd.DefaultPrefix, false)
+		assert.NoError(t, err)
+		defer client.Close()
+
+		client.Timing("command.duration", 1500*time.Microsecond, "command:status")
+		assert.Equal(t, "rcon.command.duration:1.5|ms", read())
+
+		client.Count("command.errors", 1, "command:status")
+		assert.Equal(t, "rcon.command.errors:1|c", read())
+	})
+
+	t.Run("dogstatsd", func(t *testing.T) {
+		address, read := listen(t)
+
+		client, err := statsd.New(address, "game", true)
+		assert.NoError(t, err)
+		defer client.Close()
+
+		client.Count("command.count", 1, "address:127.0.0.1:16260", "command:say|hi,all")
+		assert.Equal(t, "game.command.count:1|c|#address:127.0.0.1:16260,command:say_hi_all", read())
+	})
+}
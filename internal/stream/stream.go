@@ -0,0 +1,99 @@
+// Package stream merges output of concurrent streams line by line.
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gorcon/rcon-cli/internal/terminal"
+)
+
+// colors contains ANSI color codes for stream prefixes.
+var colors = []int{36, 33, 32, 35, 34, 31}
+
+// Mux writes lines of several streams to one output. Each line is prefixed
+// with the stream name and is written at once, so lines of different streams
+// do not interleave.
+type Mux struct {
+	mu    sync.Mutex
+	w     io.Writer
+	color bool
+	count int
+}
+
+// NewMux creates a new Mux. Prefixes are colorized if w is a terminal.
+func NewMux(w io.Writer) *Mux {
+	return &Mux{w: w, color: terminal.UseColor(w)}
+}
+
+// Writer returns writer of the stream name. Writer must be closed to flush
+// the last line without line break.
+func (m *Mux) Writer(name string) io.WriteCloser {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := name + " | "
+	if m.color {
+		prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m | ", colors[m.count%len(colors)], name)
+	}
+
+	m.count++
+
+	return &writer{mux: m, prefix: []byte(prefix)}
+}
+
+// writeLine writes the complete line with prefix.
+func (m *Mux) writeLine(prefix []byte, line []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	buf := make([]byte, 0, len(prefix)+len(line))
+	buf = append(buf, prefix...)
+	buf = append(buf, line...)
+
+	_, err := m.w.Write(buf)
+
+	return err
+}
+
+// writer buffers partial lines of the stream.
+type writer struct {
+	mux    *Mux
+	prefix []byte
+	buf    []byte
+}
+
+// Write implements io.Writer.
+func (w *writer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := w.mux.writeLine(w.prefix, w.buf[:i+1]); err != nil {
+			return len(p), err
+		}
+
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Close writes the rest of buffered data as the last line.
+func (w *writer) Close() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	w.buf = append(w.buf, '\n')
+	line := w.buf
+	w.buf = nil
+
+	return w.mux.writeLine(w.prefix, line)
+}
@@ -0,0 +1,64 @@
+package stream_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMux(t *testing.T) {
+	t.Run("prefixed lines", func(t *testing.T) {
+		out := bytes.Buffer{}
+		mux := stream.NewMux(&out)
+
+		eu := mux.Writer("eu")
+		us := mux.Writer("us")
+
+		_, _ = eu.Write([]byte("first "))
+		_, _ = us.Write([]byte("hello\nworld"))
+		_, _ = eu.Write([]byte("line\n"))
+
+		assert.NoError(t, us.Close())
+		assert.NoError(t, eu.Close())
+
+		assert.Equal(t, "us | hello\neu | first line\nus | world\n", out.String())
+	})
+
+	t.Run("concurrent writes", func(t *testing.T) {
+		out := bytes.Buffer{}
+		mux := stream.NewMux(&out)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+
+			go func(name string) {
+				defer wg.Done()
+
+				w := mux.Writer(name)
+				defer w.Close()
+
+				for j := 0; j < 100; j++ {
+					_, _ = fmt.Fprintf(w, "line %d", j)
+					_, _ = w.Write([]byte(" of " + name + "\n"))
+				}
+			}(fmt.Sprintf("s%d", i))
+		}
+
+		wg.Wait()
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		assert.Len(t, lines, 400)
+
+		for _, line := range lines {
+			name, text, _ := strings.Cut(line, " | ")
+			assert.True(t, strings.HasSuffix(text, " of "+name), line)
+		}
+	})
+}
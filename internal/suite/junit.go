@@ -0,0 +1,132 @@
+package suite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// junitSuites is the root element of JUnit XML report.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite contains test cases of one environment.
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+// junitCase is the result of one test.
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the failure or the error of the test case.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML which is understood by CI
+// systems. Each environment is a test suite, responses are added as the
+// output of test cases.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	root := junitSuites{Name: r.Name}
+	index := make(map[string]int)
+	var durations []time.Duration
+
+	for _, result := range r.Results {
+		i, ok := index[result.Env]
+		if !ok {
+			i = len(root.Suites)
+			index[result.Env] = i
+			root.Suites = append(root.Suites, junitSuite{Name: result.Env})
+			durations = append(durations, 0)
+		}
+
+		durations[i] += result.Duration
+
+		tc := junitCase{
+			Name:      result.Name,
+			Classname: result.Env,
+			Time:      seconds(result.Duration),
+			SystemOut: result.Response,
+		}
+
+		s := &root.Suites[i]
+		s.Tests++
+
+		switch {
+		case result.Error != "":
+			tc.Error = &junitMessage{Message: result.Error, Text: result.Command}
+			s.Errors++
+		case result.Failure != "":
+			tc.Failure = &junitMessage{Message: result.Failure, Text: result.Command}
+			s.Failures++
+		}
+
+		s.Cases = append(s.Cases, tc)
+	}
+
+	var total time.Duration
+
+	for i := range root.Suites {
+		root.Suites[i].Time = seconds(durations[i])
+		root.Tests += root.Suites[i].Tests
+		root.Failures += root.Suites[i].Failures
+		root.Errors += root.Suites[i].Errors
+		total += durations[i]
+	}
+
+	root.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("junit: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("junit: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err //nolint:wrapcheck // Only the line break is written.
+}
+
+// WriteJUnitFile writes the report as JUnit XML to the file. The file is
+// truncated if it exists.
+func (r *Report) WriteJUnitFile(name string) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("junit: %w", err)
+	}
+	defer file.Close()
+
+	return r.WriteJUnit(file)
+}
+
+// seconds formats the duration in seconds as JUnit expects.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
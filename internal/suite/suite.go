@@ -0,0 +1,192 @@
+// Package suite runs declarative test suites: lists of commands with
+// matchers of expected responses, e.g. for plugin developers validating
+// server builds.
+package suite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrNoTests is returned when the suite has no tests.
+	ErrNoTests = errors.New("suite has no tests")
+
+	// ErrEmptyCommand is returned when the test has no command.
+	ErrEmptyCommand = errors.New("command is empty")
+
+	// ErrMismatch is returned when the response does not satisfy matchers
+	// of the test.
+	ErrMismatch = errors.New("unexpected response")
+)
+
+// Suite is the list of tests which are executed on each environment.
+type Suite struct {
+	Name string `yaml:"name"`
+	// Envs are config environments to run tests on. The environment from
+	// --env flag is used if it is empty.
+	Envs  []string `yaml:"envs"`
+	Tests []Test   `yaml:"tests"`
+}
+
+// Test is the command with matchers of the response. Empty matchers are not
+// checked. Name is the command if it is not set.
+type Test struct {
+	Name        string `yaml:"name"`
+	Command     string `yaml:"command"`
+	Equals      string `yaml:"equals"`
+	Contains    string `yaml:"contains"`
+	NotContains string `yaml:"not_contains"`
+	Match       string `yaml:"match"`
+	NotMatch    string `yaml:"not_match"`
+
+	match    *regexp.Regexp
+	notMatch *regexp.Regexp
+}
+
+// Load reads the suite from YAML file and compiles matchers of tests.
+func Load(name string) (*Suite, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("suite: %w", err)
+	}
+
+	var s Suite
+	if err = yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("suite: %w", err)
+	}
+
+	if err = s.compile(); err != nil {
+		return nil, fmt.Errorf("suite: %w", err)
+	}
+
+	return &s, nil
+}
+
+// compile validates tests and compiles regular expressions of matchers.
+func (s *Suite) compile() error {
+	if len(s.Tests) == 0 {
+		return ErrNoTests
+	}
+
+	for i := range s.Tests {
+		t := &s.Tests[i]
+
+		if strings.TrimSpace(t.Command) == "" {
+			return fmt.Errorf("test %d: %w", i+1, ErrEmptyCommand)
+		}
+
+		if t.Name == "" {
+			t.Name = t.Command
+		}
+
+		var err error
+
+		if t.Match != "" {
+			if t.match, err = regexp.Compile(t.Match); err != nil {
+				return fmt.Errorf("%s: match: %w", t.Name, err)
+			}
+		}
+
+		if t.NotMatch != "" {
+			if t.notMatch, err = regexp.Compile(t.NotMatch); err != nil {
+				return fmt.Errorf("%s: not match: %w", t.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check returns ErrMismatch with the reason if the response does not
+// satisfy matchers of the test. Equals ignores surrounding whitespace.
+func (t *Test) Check(response string) error {
+	switch {
+	case t.Equals != "" && strings.TrimSpace(response) != strings.TrimSpace(t.Equals):
+		return fmt.Errorf("%w: response is not equal to %q", ErrMismatch, t.Equals)
+	case t.Contains != "" && !strings.Contains(response, t.Contains):
+		return fmt.Errorf("%w: response does not contain %q", ErrMismatch, t.Contains)
+	case t.NotContains != "" && strings.Contains(response, t.NotContains):
+		return fmt.Errorf("%w: response contains %q", ErrMismatch, t.NotContains)
+	case t.match != nil && !t.match.MatchString(response):
+		return fmt.Errorf("%w: response does not match %q", ErrMismatch, t.Match)
+	case t.notMatch != nil && t.notMatch.MatchString(response):
+		return fmt.Errorf("%w: response matches %q", ErrMismatch, t.NotMatch)
+	}
+
+	return nil
+}
+
+// Result is the outcome of the test on one environment. Failure is set if
+// the response does not satisfy matchers and Error is set if the command
+// is not executed.
+type Result struct {
+	Env      string
+	Name     string
+	Command  string
+	Response string
+	Duration time.Duration
+	Failure  string
+	Error    string
+}
+
+// Passed returns true if the test neither failed nor errored.
+func (r Result) Passed() bool {
+	return r.Failure == "" && r.Error == ""
+}
+
+// String returns the result in one line.
+func (r Result) String() string {
+	switch {
+	case r.Error != "":
+		return fmt.Sprintf("ERROR %s: %s: %s", r.Env, r.Name, r.Error)
+	case r.Failure != "":
+		return fmt.Sprintf("FAIL  %s: %s: %s", r.Env, r.Name, r.Failure)
+	}
+
+	return fmt.Sprintf("PASS  %s: %s (%s)", r.Env, r.Name, r.Duration.Round(time.Millisecond))
+}
+
+// Report is the list of results of the suite run.
+type Report struct {
+	Name    string
+	Started time.Time
+	Results []Result
+}
+
+// NewReport creates a new Report of the suite run started now.
+func NewReport(name string) *Report {
+	return &Report{Name: name, Started: time.Now()}
+}
+
+// Add adds the result of the test.
+func (r *Report) Add(result Result) {
+	r.Results = append(r.Results, result)
+}
+
+// Failed returns the number of tests which failed or errored.
+func (r *Report) Failed() int {
+	failed := 0
+
+	for _, result := range r.Results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// String returns the overview of the run in one line.
+func (r *Report) String() string {
+	failed := r.Failed()
+
+	return fmt.Sprintf("Tests: %d total, %d passed, %d failed in %s",
+		len(r.Results), len(r.Results)-failed, failed, time.Since(r.Started).Round(time.Millisecond))
+}
@@ -0,0 +1,143 @@
+package suite_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/suite"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSuite writes the suite to the temporary file and returns its name.
+func writeSuite(t *testing.T, content string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "suite.yaml")
+	assert.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+
+	return name
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := suite.Load(writeSuite(t, `
+name: smoke
+envs: [staging]
+tests:
+  - name: plugin is loaded
+    command: oxide.plugins
+    contains: MyPlugin
+  - command: status
+    match: 'players\s*:\s*\d+'
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, "smoke", s.Name)
+		assert.Equal(t, []string{"staging"}, s.Envs)
+		assert.Len(t, s.Tests, 2)
+		assert.Equal(t, "status", s.Tests[1].Name)
+	})
+
+	t.Run("no tests", func(t *testing.T) {
+		_, err := suite.Load(writeSuite(t, "name: empty\n"))
+		assert.ErrorIs(t, err, suite.ErrNoTests)
+	})
+
+	t.Run("empty command", func(t *testing.T) {
+		_, err := suite.Load(writeSuite(t, "tests:\n  - name: nothing\n"))
+		assert.ErrorIs(t, err, suite.ErrEmptyCommand)
+		assert.EqualError(t, err, "suite: test 1: command is empty")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := suite.Load(writeSuite(t, "tests:\n  - command: status\n    match: '(['\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		_, err := suite.Load(filepath.Join(t.TempDir(), "suite.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestTest_Check(t *testing.T) {
+	s, err := suite.Load(writeSuite(t, `
+tests:
+  - command: version
+    equals: "1.2.3"
+  - command: status
+    contains: "map: forest"
+    not_contains: error
+  - command: players
+    match: 'players\s*:\s*\d+'
+    not_match: 'players\s*:\s*0\b'
+`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		test     int
+		response string
+		msg      string
+	}{
+		{name: "equals", test: 0, response: "1.2.3\n"},
+		{name: "not equals", test: 0, response: "1.2.4", msg: `unexpected response: response is not equal to "1.2.3"`},
+		{name: "contains", test: 1, response: "hostname: test\nmap: forest"},
+		{name: "not contains", test: 1, response: "map: desert", msg: `unexpected response: response does not contain "map: forest"`},
+		{name: "contains unexpected", test: 1, response: "map: forest\nerror", msg: `unexpected response: response contains "error"`},
+		{name: "match", test: 2, response: "players : 12"},
+		{name: "not match", test: 2, response: "no players", msg: `unexpected response: response does not match "players\\s*:\\s*\\d+"`},
+		{name: "match unexpected", test: 2, response: "players: 0", msg: `unexpected response: response matches "players\\s*:\\s*0\\b"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := s.Tests[test.test].Check(test.response)
+			if test.msg == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, suite.ErrMismatch)
+			assert.EqualError(t, err, test.msg)
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := suite.NewReport("smoke")
+	report.Add(suite.Result{Env: "eu", Name: "status", Command: "status", Response: "ok", Duration: 1500 * time.Millisecond})
+	report.Add(suite.Result{Env: "eu", Name: "version", Command: "version", Failure: "unexpected response"})
+	report.Add(suite.Result{Env: "us", Name: "status", Command: "status", Error: "connection refused"})
+
+	assert.Equal(t, 2, report.Failed())
+	assert.Contains(t, report.String(), "Tests: 3 total, 1 passed, 2 failed in ")
+	assert.Equal(t, "PASS  eu: status (1.5s)", report.Results[0].String())
+	assert.Equal(t, "FAIL  eu: version: unexpected response", report.Results[1].String())
+	assert.Equal(t, "ERROR us: status: connection refused", report.Results[2].String())
+
+	t.Run("junit", func(t *testing.T) {
+		w := bytes.Buffer{}
+		assert.NoError(t, report.WriteJUnit(&w))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="smoke" tests="3" failures="1" errors="1" time="1.500">
+  <testsuite name="eu" tests="2" failures="1" errors="0" time="1.500">
+    <testcase name="status" classname="eu" time="1.500">
+      <system-out>ok</system-out>
+    </testcase>
+    <testcase name="version" classname="eu" time="0.000">
+      <failure message="unexpected response">version</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="us" tests="1" failures="0" errors="1" time="0.000">
+    <testcase name="status" classname="us" time="0.000">
+      <error message="connection refused">status</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, w.String())
+	})
+}
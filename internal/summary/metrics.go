@@ -0,0 +1,125 @@
+package summary
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// millisecondsInSecond converts durations of entries to seconds.
+const millisecondsInSecond = 1000
+
+// labelReplacer escapes label values in Prometheus text format.
+//
+//nolint:gochecknoglobals // Replacer is immutable.
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sample is a value of the metric with labels in text format.
+type sample struct {
+	labels string
+	value  float64
+}
+
+// WriteMetrics writes the summary as gauges in Prometheus text format. Last
+// success timestamp is written only if the run succeeded, so Pushgateway
+// keeps the previous value when the run fails.
+func (s *Summary) WriteMetrics(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := &strings.Builder{}
+
+	success := 0.0
+	if s.Status == StatusOK {
+		success = 1
+	}
+
+	family(b, "rcon_run_success", "Whether the last run succeeded.", sample{value: success})
+	family(b, "rcon_run_duration_seconds", "Duration of the last run.",
+		sample{value: s.Finished.Sub(s.Started).Seconds()})
+
+	if s.Status == StatusOK {
+		family(b, "rcon_last_success_timestamp_seconds", "Finish time of the last successful run.",
+			sample{value: float64(s.Finished.Unix())})
+	}
+
+	var commands, failed, durations []sample
+
+	for _, address := range s.addresses() {
+		labels := `address="` + labelReplacer.Replace(address) + `"`
+		count, fails, duration := s.count(address)
+
+		commands = append(commands, sample{labels: labels, value: count})
+		failed = append(failed, sample{labels: labels, value: fails})
+		durations = append(durations, sample{labels: labels, value: duration})
+	}
+
+	family(b, "rcon_commands", "Number of commands of the last run.", commands...)
+	family(b, "rcon_commands_failed", "Number of failed commands of the last run.", failed...)
+	family(b, "rcon_commands_duration_seconds", "Total duration of commands of the last run.", durations...)
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("metrics: %w", err)
+	}
+
+	return nil
+}
+
+// addresses returns sorted addresses of servers of the run.
+func (s *Summary) addresses() []string {
+	seen := make(map[string]bool)
+	addresses := make([]string, 0)
+
+	for _, entry := range s.Commands {
+		if !seen[entry.Address] {
+			seen[entry.Address] = true
+			addresses = append(addresses, entry.Address)
+		}
+	}
+
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+// count returns number of commands, failed commands and total duration in
+// seconds of the server.
+func (s *Summary) count(address string) (float64, float64, float64) {
+	var commands, failed, duration float64
+
+	for _, entry := range s.Commands {
+		if entry.Address != address {
+			continue
+		}
+
+		commands++
+		duration += entry.Duration / millisecondsInSecond
+
+		if entry.Status != StatusOK {
+			failed++
+		}
+	}
+
+	return commands, failed, duration
+}
+
+// family writes HELP and TYPE lines of the gauge and its samples. Nothing is
+// written if there are no samples.
+func family(b *strings.Builder, name string, help string, samples ...sample) {
+	if len(samples) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+
+	for _, smp := range samples {
+		labels := ""
+		if smp.labels != "" {
+			labels = "{" + smp.labels + "}"
+		}
+
+		_, _ = fmt.Fprintf(b, "%s%s %s\n", name, labels, strconv.FormatFloat(smp.value, 'g', -1, 64))
+	}
+}
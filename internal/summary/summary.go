@@ -0,0 +1,182 @@
+// Package summary collects outcomes of executed commands and writes them as
+// a machine-readable report, e.g. for CI jobs.
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/output"
+)
+
+// Statuses of commands and runs.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Entry is the outcome of one command on one server.
+type Entry struct {
+	Address  string  `json:"address"`
+	Command  string  `json:"command"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration_ms"`
+	Bytes    int     `json:"bytes"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Summary is the report of the run. It is safe for concurrent use.
+type Summary struct {
+	mu sync.Mutex
+
+	Status   string    `json:"status"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Total    int       `json:"total"`
+	Failed   int       `json:"failed"`
+	Commands []Entry   `json:"commands"`
+
+	// Succeeded, Duration and Slowest are the overview of the run which is
+	// set by Finish.
+	Succeeded int     `json:"succeeded"`
+	Duration  float64 `json:"duration_ms"`
+	Slowest   *Server `json:"slowest,omitempty"`
+}
+
+// Server is the total duration of commands on one server.
+type Server struct {
+	Address  string  `json:"address"`
+	Duration float64 `json:"duration_ms"`
+}
+
+// New creates a new Summary of the run started now.
+func New() *Summary {
+	return &Summary{Status: StatusOK, Started: time.Now(), Commands: []Entry{}}
+}
+
+// Add adds outcome of the command. The entry is failed if failure message
+// is not empty.
+func (s *Summary) Add(address string, command string, response string, duration time.Duration, failure string) {
+	entry := Entry{
+		Address:  address,
+		Command:  command,
+		Status:   StatusOK,
+		Duration: output.Milliseconds(duration),
+		Bytes:    len(response),
+	}
+
+	if failure != "" {
+		entry.Status = StatusError
+		entry.Error = failure
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Commands = append(s.Commands, entry)
+	s.Total++
+
+	if entry.Status != StatusOK {
+		s.Failed++
+		s.Status = StatusError
+	}
+}
+
+// Fail marks the run as failed by the error which is not related to one
+// command, e.g. config error.
+func (s *Summary) Fail() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Status = StatusError
+}
+
+// Finish sets the finish time of the run and the overview: number of
+// succeeded commands, duration of the run and the slowest server.
+func (s *Summary) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Finished = time.Now()
+	s.Succeeded = s.Total - s.Failed
+	s.Duration = output.Milliseconds(s.Finished.Sub(s.Started))
+	s.Slowest = nil
+
+	durations := make(map[string]float64)
+	for _, entry := range s.Commands {
+		durations[entry.Address] += entry.Duration
+	}
+
+	for _, address := range s.addresses() {
+		if s.Slowest == nil || durations[address] > s.Slowest.Duration {
+			s.Slowest = &Server{Address: address, Duration: durations[address]}
+		}
+	}
+}
+
+// Overview is the summary of the run without outcomes of commands.
+type Overview struct {
+	Status    string  `json:"status"`
+	Total     int     `json:"total"`
+	Succeeded int     `json:"succeeded"`
+	Failed    int     `json:"failed"`
+	Duration  float64 `json:"duration_ms"`
+	Slowest   *Server `json:"slowest,omitempty"`
+}
+
+// Overview returns the overview of the finished run.
+func (s *Summary) Overview() Overview {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Overview{
+		Status:    s.Status,
+		Total:     s.Total,
+		Succeeded: s.Succeeded,
+		Failed:    s.Failed,
+		Duration:  s.Duration,
+		Slowest:   s.Slowest,
+	}
+}
+
+// String returns the overview in one line.
+func (o Overview) String() string {
+	line := fmt.Sprintf("Summary: %d commands, %d succeeded, %d failed in %s",
+		o.Total, o.Succeeded, o.Failed, milliseconds(o.Duration))
+
+	if o.Slowest != nil {
+		line += fmt.Sprintf(", slowest server %s (%s)", o.Slowest.Address, milliseconds(o.Slowest.Duration))
+	}
+
+	return line
+}
+
+// milliseconds returns the duration in milliseconds rounded for humans.
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Millisecond)
+}
+
+// WriteFile writes the summary as JSON to the file. The file is truncated
+// if it exists.
+func (s *Summary) WriteFile(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("summary: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(s); err != nil {
+		return fmt.Errorf("summary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package summary_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/summary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		s := summary.New()
+		s.Add("127.0.0.1:16260", "status", "Players: 0", 1500*time.Microsecond, "")
+
+		assert.Equal(t, summary.StatusOK, s.Status)
+		assert.Equal(t, []summary.Entry{
+			{Address: "127.0.0.1:16260", Command: "status", Status: summary.StatusOK, Duration: 1.5, Bytes: 10},
+		}, s.Commands)
+	})
+
+	t.Run("failed command", func(t *testing.T) {
+		s := summary.New()
+		s.Add("127.0.0.1:16260", "status", "", time.Millisecond, "")
+		s.Add("127.0.0.1:16260", "kick", "", time.Millisecond, "denied")
+
+		assert.Equal(t, summary.StatusError, s.Status)
+		assert.Equal(t, 2, s.Total)
+		assert.Equal(t, 1, s.Failed)
+		assert.Equal(t, "denied", s.Commands[1].Error)
+	})
+
+	t.Run("finish", func(t *testing.T) {
+		s := summary.New()
+		s.Started = time.Now().Add(-1500 * time.Millisecond)
+		s.Add("10.0.0.1:16260", "status", "", 250*time.Millisecond, "")
+		s.Add("10.0.0.2:16260", "status", "", 800*time.Millisecond, "")
+		s.Add("10.0.0.1:16260", "kick", "", 250*time.Millisecond, "denied")
+		s.Finish()
+
+		assert.Equal(t, 2, s.Succeeded)
+		assert.Equal(t, &summary.Server{Address: "10.0.0.2:16260", Duration: 800}, s.Slowest)
+		assert.InDelta(t, 1500, s.Duration, 100)
+		assert.True(t, strings.HasPrefix(s.Overview().String(), "Summary: 3 commands, 2 succeeded, 1 failed in 1.5"))
+		assert.True(t, strings.HasSuffix(s.Overview().String(), ", slowest server 10.0.0.2:16260 (800ms)"))
+
+		s = summary.New()
+		s.Finish()
+		assert.Nil(t, s.Slowest)
+		assert.NotContains(t, s.Overview().String(), "slowest")
+	})
+
+	t.Run("write file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "summary.json")
+
+		s := summary.New()
+		s.Fail()
+		assert.NoError(t, s.WriteFile(name))
+
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+
+		var report map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, summary.StatusError, report["status"])
+		assert.Equal(t, []interface{}{}, report["commands"])
+		assert.Contains(t, report, "finished")
+	})
+}
+
+func TestSummary_WriteMetrics(t *testing.T) {
+	s := summary.New()
+	s.Started = time.Unix(1700000000, 0)
+	s.Add("10.0.0.2:16260", "status", "", 500*time.Millisecond, "")
+	s.Add("10.0.0.1:16260", "status", "", 250*time.Millisecond, "")
+	s.Add("10.0.0.1:16260", "kick", "", 250*time.Millisecond, "denied")
+	s.Finished = time.Unix(1700000002, 0)
+
+	b := &strings.Builder{}
+	assert.NoError(t, s.WriteMetrics(b))
+	assert.Equal(t, `# HELP rcon_run_success Whether the last run succeeded.
+# TYPE rcon_run_success gauge
+rcon_run_success 0
+# HELP rcon_run_duration_seconds Duration of the last run.
+# TYPE rcon_run_duration_seconds gauge
+rcon_run_duration_seconds 2
+# HELP rcon_commands Number of commands of the last run.
+# TYPE rcon_commands gauge
+rcon_commands{address="10.0.0.1:16260"} 2
+rcon_commands{address="10.0.0.2:16260"} 1
+# HELP rcon_commands_failed Number of failed commands of the last run.
+# TYPE rcon_commands_failed gauge
+rcon_commands_failed{address="10.0.0.1:16260"} 1
+rcon_commands_failed{address="10.0.0.2:16260"} 0
+# HELP rcon_commands_duration_seconds Total duration of commands of the last run.
+# TYPE rcon_commands_duration_seconds gauge
+rcon_commands_duration_seconds{address="10.0.0.1:16260"} 0.5
+rcon_commands_duration_seconds{address="10.0.0.2:16260"} 0.5
+`, b.String())
+
+	s = summary.New()
+	s.Finished = time.Unix(1700000002, 0)
+
+	b.Reset()
+	assert.NoError(t, s.WriteMetrics(b))
+	assert.Contains(t, b.String(), "rcon_run_success 1\n")
+	assert.Contains(t, b.String(), "rcon_last_success_timestamp_seconds 1.700000002e+09\n")
+	assert.NotContains(t, b.String(), "rcon_commands")
+}
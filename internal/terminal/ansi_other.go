@@ -0,0 +1,9 @@
+//go:build !windows
+
+package terminal
+
+// enableANSI does nothing, terminals on this platform interpret ANSI escape
+// sequences natively.
+func enableANSI(_ uintptr) bool {
+	return true
+}
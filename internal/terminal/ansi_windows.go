@@ -0,0 +1,33 @@
+//go:build windows
+
+package terminal
+
+import "syscall"
+
+// enableVirtualTerminalProcessing is the console mode flag which makes
+// the console host interpret ANSI escape sequences.
+const enableVirtualTerminalProcessing = 0x0004
+
+//nolint:gochecknoglobals // Lazy loaded system procedures.
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+// enableANSI turns on virtual terminal processing for the console attached
+// to fd. It returns false on consoles which do not support it, such as
+// cmd.exe before Windows 10.
+func enableANSI(fd uintptr) bool {
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &mode); err != nil {
+		return false
+	}
+
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+
+	ret, _, _ := procSetConsoleMode.Call(fd, uintptr(mode|enableVirtualTerminalProcessing))
+
+	return ret != 0
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package terminal
+
+// rows is not supported on this platform.
+func rows(_ uintptr) int {
+	return 0
+}
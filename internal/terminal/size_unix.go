@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package terminal
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// rows requests window size of the terminal from the kernel.
+func rows(fd uintptr) int {
+	var ws struct {
+		Row, Col, X, Y uint16
+	}
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+
+	return int(ws.Row)
+}
@@ -0,0 +1,62 @@
+// Package terminal contains helpers for working with the user's terminal.
+package terminal
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// DefaultHeight is the terminal height which is used when the real height
+// can not be determined.
+const DefaultHeight = 24
+
+// IsTerminal returns true if w is a file attached to a character device.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// UseColor returns true if ANSI colors can be written to w. Colors are
+// disabled if w is not a terminal or NO_COLOR environment variable is set.
+// On Windows it also requires the console to support ANSI escape sequences.
+func UseColor(w io.Writer) bool {
+	return os.Getenv("NO_COLOR") == "" && IsTerminal(w) && EnableANSI(w)
+}
+
+// EnableANSI prepares the console attached to w for ANSI escape sequences
+// and reports whether they are supported. On Windows it turns on virtual
+// terminal processing, so colors work in cmd.exe and older PowerShell too.
+func EnableANSI(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return enableANSI(file.Fd())
+}
+
+// Height returns the number of rows of the terminal attached to w.
+// The LINES environment variable takes precedence over the real size.
+func Height(w io.Writer) int {
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+
+	if file, ok := w.(*os.File); ok {
+		if rows := rows(file.Fd()); rows > 0 {
+			return rows
+		}
+	}
+
+	return DefaultHeight
+}
@@ -0,0 +1,50 @@
+package terminal_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/terminal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTerminal(t *testing.T) {
+	t.Run("buffer", func(t *testing.T) {
+		assert.False(t, terminal.IsTerminal(&bytes.Buffer{}))
+	})
+
+	t.Run("regular file", func(t *testing.T) {
+		file, err := os.CreateTemp("", "terminal")
+		assert.NoError(t, err)
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		assert.False(t, terminal.IsTerminal(file))
+	})
+}
+
+func TestUseColor(t *testing.T) {
+	t.Run("buffer", func(t *testing.T) {
+		assert.False(t, terminal.UseColor(&bytes.Buffer{}))
+		assert.False(t, terminal.EnableANSI(&bytes.Buffer{}))
+	})
+
+	t.Run("no color", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+
+		assert.False(t, terminal.UseColor(os.Stdout))
+	})
+}
+
+func TestHeight(t *testing.T) {
+	t.Run("lines env", func(t *testing.T) {
+		t.Setenv("LINES", "42")
+		assert.Equal(t, 42, terminal.Height(&bytes.Buffer{}))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("LINES", "")
+		assert.Equal(t, terminal.DefaultHeight, terminal.Height(&bytes.Buffer{}))
+	})
+}
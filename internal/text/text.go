@@ -0,0 +1,48 @@
+// Package text contains helpers for safe output of server responses which
+// can contain invalid UTF-8, wide characters and color codes.
+package text
+
+import (
+	"strings"
+
+	"github.com/gorcon/rcon-cli/internal/ansi"
+	"github.com/mattn/go-runewidth"
+)
+
+// Ellipsis is appended to truncated text.
+const Ellipsis = "…"
+
+// Sanitize replaces invalid UTF-8 sequences with the replacement character
+// and removes NUL bytes which some servers append to responses.
+func Sanitize(s string) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(s, "�"), "\x00", "")
+}
+
+// Width returns the number of terminal cells which s occupies. CJK and emoji
+// take two cells, escape sequences and color codes take none.
+func Width(s string) int {
+	return runewidth.StringWidth(ansi.Strip(s))
+}
+
+// Pad appends spaces to s up to width cells.
+func Pad(s string, width int) string {
+	if w := Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+
+	return s
+}
+
+// Truncate cuts s to width cells and appends Ellipsis if s was cut.
+func Truncate(s string, width int) string {
+	return runewidth.Truncate(s, width, Ellipsis)
+}
+
+// PrefixLines inserts prefix at the beginning of each line of s.
+func PrefixLines(s string, prefix string) string {
+	if s == "" {
+		return s
+	}
+
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
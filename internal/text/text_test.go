@@ -0,0 +1,49 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "players", text.Sanitize("players"))
+	assert.Equal(t, "玩家 �", text.Sanitize("玩家 \xff\xfe"))
+	assert.Equal(t, "help", text.Sanitize("help\x00\x00"))
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{"ascii", "admin", 5},
+		{"cyrillic", "админ", 5},
+		{"cjk", "玩家", 4},
+		{"emoji", "🎮", 2},
+		{"colors", "\x1b[31m玩家\x1b[0m §6ok", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, text.Width(tt.text))
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "玩家  |", text.Pad("玩家", 6)+"|")
+	assert.Equal(t, "admin|", text.Pad("admin", 3)+"|")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "玩家…", text.Truncate("玩家玩家", 5))
+	assert.Equal(t, "admin", text.Truncate("admin", 5))
+}
+
+func TestPrefixLines(t *testing.T) {
+	assert.Equal(t, "> a\n> b", text.PrefixLines("a\nb", "> "))
+	assert.Equal(t, "", text.PrefixLines("", "> "))
+}
@@ -0,0 +1,84 @@
+// Package tlspin verifies TLS servers by SHA-256 fingerprint of their
+// certificate instead of certificate authorities, e.g. self-signed Web RCON
+// servers.
+//
+// Fingerprint is written as "sha256:" followed by 64 hex digits which can be
+// separated with colons like in the output of openssl:
+//
+//	sha256:ab12...
+//	sha256:AB:12:...
+package tlspin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix is the prefix of SHA-256 fingerprints.
+const Prefix = "sha256:"
+
+var (
+	// ErrInvalidFingerprint is returned when fingerprint is not SHA-256
+	// fingerprint with Prefix.
+	ErrInvalidFingerprint = errors.New("invalid tls fingerprint")
+
+	// ErrMismatch is returned when certificate of the server does not match
+	// the pinned fingerprint.
+	ErrMismatch = errors.New("tls certificate does not match pinned fingerprint")
+)
+
+// Parse returns the SHA-256 hash of the fingerprint.
+func Parse(fingerprint string) ([]byte, error) {
+	digits, ok := strings.CutPrefix(strings.ToLower(fingerprint), Prefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s prefix is required", ErrInvalidFingerprint, Prefix)
+	}
+
+	hash, err := hex.DecodeString(strings.ReplaceAll(digits, ":", ""))
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf("%w: %d hex digits are required", ErrInvalidFingerprint, sha256.Size*2) //nolint:gomnd // Digits of byte.
+	}
+
+	return hash, nil
+}
+
+// Fingerprint returns the fingerprint of DER encoded certificate.
+func Fingerprint(der []byte) string {
+	hash := sha256.Sum256(der)
+
+	return Prefix + hex.EncodeToString(hash[:])
+}
+
+// Config returns TLS config which accepts the server only if its leaf
+// certificate matches the fingerprint. Certificate authorities, host name
+// and expiration are not checked, the pin replaces them.
+func Config(fingerprint string) (*tls.Config, error) {
+	hash, err := Parse(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Chain is not verified, the certificate is checked by the pin in
+		// VerifyConnection.
+		InsecureSkipVerify: true, //nolint:gosec // Verified by the pin.
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return ErrMismatch
+			}
+
+			der := state.PeerCertificates[0].Raw
+			if sum := sha256.Sum256(der); !bytes.Equal(sum[:], hash) {
+				return fmt.Errorf("%w: got %s", ErrMismatch, Fingerprint(der))
+			}
+
+			return nil
+		},
+	}, nil
+}
@@ -0,0 +1,59 @@
+package tlspin_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/tlspin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	hex := strings.Repeat("ab", 32)
+
+	hash, err := tlspin.Parse("sha256:" + hex)
+	assert.NoError(t, err)
+	assert.Len(t, hash, 32)
+
+	colons, err := tlspin.Parse("SHA256:" + strings.TrimSuffix(strings.Repeat("AB:", 32), ":"))
+	assert.NoError(t, err)
+	assert.Equal(t, hash, colons)
+
+	_, err = tlspin.Parse(hex)
+	assert.ErrorIs(t, err, tlspin.ErrInvalidFingerprint)
+
+	_, err = tlspin.Parse("sha256:abcd")
+	assert.ErrorIs(t, err, tlspin.ErrInvalidFingerprint)
+
+	_, err = tlspin.Parse("sha256:" + strings.Repeat("zz", 32))
+	assert.ErrorIs(t, err, tlspin.ErrInvalidFingerprint)
+}
+
+func TestConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	address := server.Listener.Addr().String()
+
+	t.Run("pinned", func(t *testing.T) {
+		config, err := tlspin.Config(tlspin.Fingerprint(server.Certificate().Raw))
+		assert.NoError(t, err)
+
+		conn, err := tls.Dial("tcp", address, config)
+		if assert.NoError(t, err) {
+			conn.Close()
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		config, err := tlspin.Config("sha256:" + strings.Repeat("00", 32))
+		assert.NoError(t, err)
+
+		_, err = tls.Dial("tcp", address, config)
+		assert.ErrorIs(t, err, tlspin.ErrMismatch)
+		assert.ErrorContains(t, err, tlspin.Fingerprint(server.Certificate().Raw))
+	})
+}
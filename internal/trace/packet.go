@@ -0,0 +1,163 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/config"
+)
+
+// packet is a piece of traffic with decoded header fields.
+type packet struct {
+	data   []byte
+	header string
+}
+
+// splitter splits the stream of one direction into packets.
+type splitter struct {
+	buf    []byte
+	frame  func(buf []byte) (int, string)
+	opened bool
+}
+
+// newSplitter returns splitter for the protocol. Telnet has no framing, so
+// each chunk of data is a packet.
+func newSplitter(protocol string) *splitter {
+	s := splitter{}
+
+	switch protocol {
+	case config.ProtocolTELNET:
+		s.frame = func(buf []byte) (int, string) { return len(buf), "" }
+	case config.ProtocolWebRCON:
+		s.frame = s.websocket
+	default:
+		s.frame = rconFrame
+	}
+
+	return &s
+}
+
+// split appends data to the buffer and returns complete packets.
+func (s *splitter) split(data []byte) []packet {
+	s.buf = append(s.buf, data...)
+
+	var packets []packet
+
+	for len(s.buf) != 0 {
+		n, header := s.frame(s.buf)
+		if n == 0 {
+			break
+		}
+
+		packets = append(packets, packet{data: append([]byte(nil), s.buf[:n]...), header: header})
+		s.buf = s.buf[n:]
+	}
+
+	return packets
+}
+
+// flush returns incomplete data as the last packet.
+func (s *splitter) flush() []packet {
+	if len(s.buf) == 0 {
+		return nil
+	}
+
+	p := packet{data: s.buf, header: "incomplete"}
+	s.buf = nil
+
+	return []packet{p}
+}
+
+// rconFrame returns length and header fields of Source RCON packet.
+// Packets with invalid size are returned as is.
+func rconFrame(buf []byte) (int, string) {
+	const headerSize = 12
+
+	if len(buf) < headerSize {
+		return 0, ""
+	}
+
+	size := int32(binary.LittleEndian.Uint32(buf[0:4]))
+	id := int32(binary.LittleEndian.Uint32(buf[4:8]))
+	typ := int32(binary.LittleEndian.Uint32(buf[8:12]))
+
+	if size < rcon.MinPacketSize || size > rcon.MaxPacketSize {
+		return len(buf), fmt.Sprintf("invalid size=%d", size)
+	}
+
+	total := int(size) + 4 //nolint:gomnd // Size field.
+	if len(buf) < total {
+		return 0, ""
+	}
+
+	return total, fmt.Sprintf("size=%d id=%d type=%d", size, id, typ)
+}
+
+// websocket returns length and header fields of HTTP handshake or
+// WebSocket frame.
+func (s *splitter) websocket(buf []byte) (int, string) {
+	if !s.opened {
+		i := bytes.Index(buf, []byte("\r\n\r\n"))
+		if i < 0 {
+			return 0, ""
+		}
+
+		s.opened = true
+		line, _, _ := bytes.Cut(buf, []byte("\r\n"))
+
+		return i + 4, fmt.Sprintf("http %q", line) //nolint:gomnd // Length of separator.
+	}
+
+	return websocketFrame(buf)
+}
+
+// websocketFrame returns length and header fields of WebSocket frame.
+func websocketFrame(buf []byte) (int, string) {
+	const (
+		finBit      = 0x80
+		opcodeMask  = 0x0f
+		maskBit     = 0x80
+		lengthMask  = 0x7f
+		length16    = 126
+		length64    = 127
+		maskKeySize = 4
+	)
+
+	if len(buf) < 2 { //nolint:gomnd // Minimal header size.
+		return 0, ""
+	}
+
+	header := 2
+	length := uint64(buf[1] & lengthMask)
+
+	switch length {
+	case length16:
+		if len(buf) < header+2 {
+			return 0, ""
+		}
+
+		length = uint64(binary.BigEndian.Uint16(buf[header:]))
+		header += 2
+	case length64:
+		if len(buf) < header+8 {
+			return 0, ""
+		}
+
+		length = binary.BigEndian.Uint64(buf[header:])
+		header += 8
+	}
+
+	masked := buf[1]&maskBit != 0
+	if masked {
+		header += maskKeySize
+	}
+
+	if uint64(len(buf)) < uint64(header)+length {
+		return 0, ""
+	}
+
+	return header + int(length), fmt.Sprintf("fin=%t opcode=%d masked=%t length=%d",
+		buf[0]&finBit != 0, buf[0]&opcodeMask, masked, length)
+}
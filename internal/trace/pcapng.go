@@ -0,0 +1,250 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// pcapng block types and constants.
+const (
+	blockSection   uint32 = 0x0A0D0D0A
+	blockInterface uint32 = 0x00000001
+	blockPacket    uint32 = 0x00000006
+	byteOrderMagic uint32 = 0x1A2B3C4D
+
+	// linkTypeRaw means packets start with IPv4 or IPv6 header.
+	linkTypeRaw uint16 = 101
+)
+
+// TCP flags of synthetic segments.
+const (
+	flagFIN uint8 = 0x01
+	flagSYN uint8 = 0x02
+	flagPSH uint8 = 0x08
+	flagACK uint8 = 0x10
+)
+
+// Sizes of synthetic headers.
+const (
+	ipv4HeaderSize = 20
+	ipv6HeaderSize = 40
+	tcpHeaderSize  = 20
+
+	// maxSegmentSize keeps IP packets shorter than 64 KiB.
+	maxSegmentSize = 32 * 1024
+
+	protocolTCP = 6
+	ttl         = 64
+	window      = 65535
+)
+
+// pcapng writes packets as synthetic TCP segments in pcapng format. Real
+// handshake and acknowledgements are not captured, so they are synthesized
+// to let Wireshark dissectors reassemble the stream.
+type pcapng struct {
+	w io.Writer
+}
+
+// newPcapng writes section header and interface description blocks.
+func newPcapng(w io.Writer) (*pcapng, error) {
+	p := pcapng{w: w}
+
+	section := new(bytes.Buffer)
+	_ = binary.Write(section, binary.LittleEndian, byteOrderMagic)
+	_ = binary.Write(section, binary.LittleEndian, uint16(1)) // Major version.
+	_ = binary.Write(section, binary.LittleEndian, uint16(0)) // Minor version.
+	_ = binary.Write(section, binary.LittleEndian, int64(-1)) // Section length is not specified.
+
+	if err := p.block(blockSection, section.Bytes()); err != nil {
+		return nil, err
+	}
+
+	iface := new(bytes.Buffer)
+	_ = binary.Write(iface, binary.LittleEndian, linkTypeRaw)
+	_ = binary.Write(iface, binary.LittleEndian, uint16(0)) // Reserved.
+	_ = binary.Write(iface, binary.LittleEndian, uint32(0)) // Snapshot length is not limited.
+
+	if err := p.block(blockInterface, iface.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// open writes three-way handshake.
+func (p *pcapng) open(c *connection) {
+	if c.local == nil || c.remote == nil {
+		return
+	}
+
+	c.seq[Sent], c.seq[Received] = 0, 0
+
+	p.segment(c, Sent, flagSYN, nil)
+	c.seq[Sent]++
+	p.segment(c, Received, flagSYN|flagACK, nil)
+	c.seq[Received]++
+	p.segment(c, Sent, flagACK, nil)
+}
+
+// packet writes data as TCP segments.
+func (p *pcapng) packet(c *connection, direction string, pkt packet) {
+	if c.local == nil || c.remote == nil {
+		return
+	}
+
+	for data := pkt.data; len(data) != 0; {
+		n := min(len(data), maxSegmentSize)
+		p.segment(c, direction, flagPSH|flagACK, data[:n])
+		c.seq[direction] += uint32(n)
+		data = data[n:]
+	}
+}
+
+// close writes connection termination.
+func (p *pcapng) close(c *connection) {
+	if c.local == nil || c.remote == nil {
+		return
+	}
+
+	p.segment(c, Sent, flagFIN|flagACK, nil)
+	c.seq[Sent]++
+	p.segment(c, Received, flagFIN|flagACK, nil)
+	c.seq[Received]++
+	p.segment(c, Sent, flagACK, nil)
+}
+
+// fail does nothing, failed connection has no packets.
+func (p *pcapng) fail(string, error) {}
+
+// segment writes enhanced packet block with IP and TCP headers.
+func (p *pcapng) segment(c *connection, direction string, flags uint8, payload []byte) {
+	src, dst := c.local, c.remote
+	other := Received
+
+	if direction == Received {
+		src, dst = c.remote, c.local
+		other = Sent
+	}
+
+	seg := tcpSegment(src, dst, c.seq[direction], c.seq[other], flags, payload)
+	data := ipPacket(src.IP, dst.IP, seg)
+
+	now := time.Now().UnixMicro()
+
+	block := new(bytes.Buffer)
+	_ = binary.Write(block, binary.LittleEndian, uint32(0)) // Interface ID.
+	_ = binary.Write(block, binary.LittleEndian, uint32(now>>32))
+	_ = binary.Write(block, binary.LittleEndian, uint32(now))
+	_ = binary.Write(block, binary.LittleEndian, uint32(len(data))) // Captured length.
+	_ = binary.Write(block, binary.LittleEndian, uint32(len(data))) // Original length.
+	block.Write(data)
+	block.Write(make([]byte, padding(len(data))))
+
+	_ = p.block(blockPacket, block.Bytes())
+}
+
+// block writes pcapng block with the body which length is multiple of 4.
+func (p *pcapng) block(typ uint32, body []byte) error {
+	const overhead = 12 // Block type and two block total length fields.
+
+	length := uint32(len(body) + overhead)
+
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, typ)
+	_ = binary.Write(buf, binary.LittleEndian, length)
+	buf.Write(body)
+	_ = binary.Write(buf, binary.LittleEndian, length)
+
+	if _, err := p.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("pcapng: %w", err)
+	}
+
+	return nil
+}
+
+// padding returns number of bytes to align n to 32 bits.
+func padding(n int) int {
+	return (4 - n%4) % 4 //nolint:gomnd // 32 bits alignment.
+}
+
+// tcpSegment builds TCP header with payload and checksum.
+func tcpSegment(src, dst *net.TCPAddr, seq, ack uint32, flags uint8, payload []byte) []byte {
+	seg := make([]byte, tcpHeaderSize, tcpHeaderSize+len(payload))
+
+	binary.BigEndian.PutUint16(seg[0:], uint16(src.Port))
+	binary.BigEndian.PutUint16(seg[2:], uint16(dst.Port))
+	binary.BigEndian.PutUint32(seg[4:], seq)
+	binary.BigEndian.PutUint32(seg[8:], ack)
+	seg[12] = tcpHeaderSize / 4 << 4 //nolint:gomnd // Data offset in 32-bit words.
+	seg[13] = flags
+	binary.BigEndian.PutUint16(seg[14:], window)
+	seg = append(seg, payload...)
+
+	pseudo := new(bytes.Buffer)
+	if ip := src.IP.To4(); ip != nil {
+		pseudo.Write(ip)
+		pseudo.Write(dst.IP.To4())
+		pseudo.Write([]byte{0, protocolTCP})
+		_ = binary.Write(pseudo, binary.BigEndian, uint16(len(seg)))
+	} else {
+		pseudo.Write(src.IP.To16())
+		pseudo.Write(dst.IP.To16())
+		_ = binary.Write(pseudo, binary.BigEndian, uint32(len(seg)))
+		pseudo.Write([]byte{0, 0, 0, protocolTCP})
+	}
+
+	pseudo.Write(seg)
+	binary.BigEndian.PutUint16(seg[16:], checksum(pseudo.Bytes()))
+
+	return seg
+}
+
+// ipPacket builds IPv4 or IPv6 header for the segment.
+func ipPacket(src, dst net.IP, seg []byte) []byte {
+	if src4 := src.To4(); src4 != nil {
+		header := make([]byte, ipv4HeaderSize)
+		header[0] = 0x45 // Version 4, header length 5 words.
+		binary.BigEndian.PutUint16(header[2:], uint16(ipv4HeaderSize+len(seg)))
+		binary.BigEndian.PutUint16(header[6:], 0x4000) // Don't fragment.
+		header[8] = ttl
+		header[9] = protocolTCP
+		copy(header[12:], src4)
+		copy(header[16:], dst.To4())
+		binary.BigEndian.PutUint16(header[10:], checksum(header))
+
+		return append(header, seg...)
+	}
+
+	header := make([]byte, ipv6HeaderSize)
+	header[0] = 0x60 // Version 6.
+	binary.BigEndian.PutUint16(header[4:], uint16(len(seg)))
+	header[6] = protocolTCP
+	header[7] = ttl
+	copy(header[8:], src.To16())
+	copy(header[24:], dst.To16())
+
+	return append(header, seg...)
+}
+
+// checksum calculates the Internet checksum of data.
+func checksum(data []byte) uint16 {
+	var sum uint32
+
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8 //nolint:gomnd // High byte of the last word.
+	}
+
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+
+	return ^uint16(sum)
+}
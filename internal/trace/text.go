@@ -0,0 +1,47 @@
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TimeLayout is the layout of timestamps in text format.
+const TimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// text writes packets as text with hex dumps.
+type text struct {
+	w io.Writer
+}
+
+// open writes the connection event.
+func (f *text) open(c *connection) {
+	f.note(c.address, "connected")
+}
+
+// packet writes the packet record.
+func (f *text) packet(_ *connection, direction string, p packet) {
+	header := ""
+	if p.header != "" {
+		header = " " + p.header
+	}
+
+	_, _ = fmt.Fprintf(f.w, "%s %s %d bytes%s\n%s", time.Now().Format(TimeLayout), direction, len(p.data),
+		header, hex.Dump(p.data))
+}
+
+// close writes the connection event.
+func (f *text) close(c *connection) {
+	f.note(c.address, "closed")
+}
+
+// fail writes the dial error.
+func (f *text) fail(address string, err error) {
+	f.note(address, fmt.Sprintf("dial: %s", err))
+}
+
+// note writes the connection event.
+func (f *text) note(address string, event string) {
+	_, _ = fmt.Fprintf(f.w, "%s - %s %s\n", time.Now().Format(TimeLayout), address, event)
+}
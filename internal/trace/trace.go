@@ -0,0 +1,180 @@
+// Package trace records traffic between RCON clients and servers. Clients
+// connect to a local relay which forwards data to the server and records
+// each packet as text with hex dump or to pcapng file.
+package trace
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/gorcon/rcon-cli/internal/redact"
+)
+
+// Directions of packets.
+const (
+	Sent     = ">"
+	Received = "<"
+)
+
+// format writes records of relayed connections.
+type format interface {
+	open(c *connection)
+	packet(c *connection, direction string, p packet)
+	close(c *connection)
+	fail(address string, err error)
+}
+
+// connection contains addresses of relayed connection to the server.
+type connection struct {
+	address string
+	local   *net.TCPAddr
+	remote  *net.TCPAddr
+
+	// seq contains next TCP sequence numbers of sent and received data
+	// which are used in pcapng format.
+	seq map[string]uint32
+}
+
+// Tracer records packets of relayed connections.
+type Tracer struct {
+	mu        sync.Mutex
+	format    format
+	wg        sync.WaitGroup
+	listeners []net.Listener
+}
+
+// New creates a new Tracer which writes packets to w as text with
+// direction, timestamp, decoded header fields and hex dump.
+func New(w io.Writer) *Tracer {
+	return &Tracer{format: &text{w: w}}
+}
+
+// NewPcapng creates a new Tracer which writes packets to w in pcapng format
+// as a synthetic TCP stream, so they can be inspected in Wireshark.
+func NewPcapng(w io.Writer) (*Tracer, error) {
+	p, err := newPcapng(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tracer{format: p}, nil
+}
+
+// Relay starts local listener which accepts one connection and forwards it
+// to address with dialer. Packets are split and decoded according to
+// protocol. Returns the local address which the client must connect to.
+func (t *Tracer) Relay(address string, protocol string, dialer *net.Dialer) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("trace: %w", err)
+	}
+
+	t.mu.Lock()
+	t.listeners = append(t.listeners, listener)
+	t.mu.Unlock()
+
+	t.wg.Add(1)
+
+	go func() {
+		defer t.wg.Done()
+		defer listener.Close()
+
+		client, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		server, err := dialer.Dial("tcp", address)
+		if err != nil {
+			t.record(func() { t.format.fail(address, err) })
+			_ = client.Close()
+
+			return
+		}
+
+		c := connection{address: address, seq: map[string]uint32{}}
+		c.local, _ = server.LocalAddr().(*net.TCPAddr)
+		c.remote, _ = server.RemoteAddr().(*net.TCPAddr)
+
+		t.record(func() { t.format.open(&c) })
+		t.pipe(&c, client, server, protocol)
+		t.record(func() { t.format.close(&c) })
+	}()
+
+	return listener.Addr().String(), nil
+}
+
+// Close stops relays which are waiting for connection and waits until
+// established connections are closed by the client or the server.
+func (t *Tracer) Close() error {
+	t.mu.Lock()
+	for _, listener := range t.listeners {
+		_ = listener.Close()
+	}
+
+	t.listeners = nil
+	t.mu.Unlock()
+
+	t.wg.Wait()
+
+	return nil
+}
+
+// pipe copies data in both directions until one of connections is closed.
+func (t *Tracer) pipe(c *connection, client net.Conn, server net.Conn, protocol string) {
+	var wg sync.WaitGroup
+
+	wg.Add(2) //nolint:gomnd // Both directions.
+
+	copyPackets := func(dst net.Conn, src net.Conn, direction string) {
+		defer wg.Done()
+
+		s := newSplitter(protocol)
+		buf := make([]byte, 32*1024) //nolint:gomnd // Buffer size of io.Copy.
+
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				for _, p := range s.split(buf[:n]) {
+					t.record(func() { t.format.packet(c, direction, redacted(p)) })
+				}
+
+				if _, werr := dst.Write(buf[:n]); werr != nil {
+					err = werr
+				}
+			}
+
+			if err != nil {
+				for _, p := range s.flush() {
+					t.record(func() { t.format.packet(c, direction, redacted(p)) })
+				}
+
+				_ = dst.Close()
+				_ = src.Close()
+
+				return
+			}
+		}
+	}
+
+	go copyPackets(server, client, Sent)
+	go copyPackets(client, server, Received)
+
+	wg.Wait()
+}
+
+// redacted returns the packet with passwords replaced by asterisks of the
+// same length, e.g. body of auth packet or path of Web RCON upgrade request.
+func redacted(p packet) packet {
+	return packet{data: redact.Bytes(p.data), header: redact.String(p.header)}
+}
+
+// record calls fn exclusively, so records of connections do not interleave.
+func (t *Tracer) record(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fn()
+}
@@ -0,0 +1,171 @@
+package trace_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon-cli/internal/config"
+	"github.com/gorcon/rcon-cli/internal/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+// echo starts server which sends back everything it receives.
+func echo(t *testing.T) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return listener
+}
+
+// relay sends data through the relay and returns the trace.
+func relay(t *testing.T, protocol string, data []byte) string {
+	t.Helper()
+
+	w := &bytes.Buffer{}
+	send(t, trace.New(w), protocol, data)
+
+	assert.True(t, strings.HasSuffix(w.String(), " closed\n"))
+
+	return w.String()
+}
+
+// send sends data through the relay of tracer and closes it.
+func send(t *testing.T, tracer *trace.Tracer, protocol string, data []byte) {
+	t.Helper()
+
+	server := echo(t)
+	defer server.Close()
+
+	address, err := tracer.Relay(server.Addr().String(), protocol, &net.Dialer{Timeout: time.Second})
+	assert.NoError(t, err)
+
+	conn, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+
+	_, err = conn.Write(data)
+	assert.NoError(t, err)
+
+	_, err = io.ReadFull(conn, make([]byte, len(data)))
+	assert.NoError(t, err)
+	conn.Close()
+
+	assert.NoError(t, tracer.Close())
+}
+
+func TestTracer_Relay(t *testing.T) {
+	t.Run("rcon", func(t *testing.T) {
+		data := &bytes.Buffer{}
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH, 1, "").WriteTo(data)
+		_, _ = rcon.NewPacket(rcon.SERVERDATA_EXECCOMMAND, 2, "status").WriteTo(data)
+
+		result := relay(t, config.ProtocolRCON, data.Bytes())
+		assert.Contains(t, result, " connected\n")
+		assert.Contains(t, result, "> 14 bytes size=10 id=1 type=3\n00000000  0a 00 00 00 01 00 00 00")
+		assert.Contains(t, result, "> 20 bytes size=16 id=2 type=2\n")
+		assert.Contains(t, result, "< 20 bytes size=16 id=2 type=2\n")
+		assert.Contains(t, result, "|............stat|\n00000010  75 73 00 00")
+	})
+
+	t.Run("rcon incomplete", func(t *testing.T) {
+		result := relay(t, config.ProtocolRCON, []byte{0x0a, 0x00})
+		assert.Contains(t, result, "> 2 bytes incomplete\n")
+	})
+
+	t.Run("telnet", func(t *testing.T) {
+		result := relay(t, config.ProtocolTELNET, []byte("version\r\n"))
+		assert.Contains(t, result, "> 9 bytes\n")
+	})
+
+	t.Run("web", func(t *testing.T) {
+		data := []byte("GET /password HTTP/1.1\r\nHost: localhost\r\n\r\n")
+		data = append(data, 0x81, 0x82, 1, 2, 3, 4, 'h'^1, 'i'^2)
+
+		result := relay(t, config.ProtocolWebRCON, data)
+		assert.Contains(t, result, `> 43 bytes http "GET /password HTTP/1.1"`)
+		assert.Contains(t, result, "> 8 bytes fin=true opcode=1 masked=true length=2\n")
+	})
+}
+
+func TestNewPcapng(t *testing.T) {
+	w := &bytes.Buffer{}
+
+	tracer, err := trace.NewPcapng(w)
+	assert.NoError(t, err)
+
+	send(t, tracer, config.ProtocolRCON, []byte("\x0a\x00\x00\x00\x01\x00\x00\x00\x03\x00\x00\x00\x00\x00"))
+
+	data := w.Bytes()
+
+	var types []uint32
+
+	var segments [][]byte
+
+	for len(data) != 0 {
+		typ := binary.LittleEndian.Uint32(data[0:])
+		length := binary.LittleEndian.Uint32(data[4:])
+		assert.Equal(t, length, binary.LittleEndian.Uint32(data[length-4:]))
+
+		if typ == 6 {
+			captured := binary.LittleEndian.Uint32(data[20:])
+			segments = append(segments, data[28:28+captured])
+		}
+
+		types = append(types, typ)
+		data = data[length:]
+	}
+
+	assert.Equal(t, []uint32{0x0A0D0D0A, 1, 6, 6, 6, 6, 6, 6, 6, 6}, types)
+
+	// SYN, SYN-ACK, ACK, request, response, FIN-ACK, FIN-ACK, ACK.
+	flags := make([]byte, 0, len(segments))
+	for _, seg := range segments {
+		assert.Equal(t, byte(0x45), seg[0])
+		assert.Equal(t, uint16(0), checksum(seg[:20]), "ip checksum")
+
+		pseudo := append(append([]byte{}, seg[12:20]...), 0, 6, byte((len(seg)-20)>>8), byte(len(seg)-20))
+		assert.Equal(t, uint16(0), checksum(append(pseudo, seg[20:]...)), "tcp checksum")
+
+		flags = append(flags, seg[20+13])
+	}
+
+	assert.Equal(t, []byte{0x02, 0x12, 0x10, 0x18, 0x18, 0x11, 0x11, 0x10}, flags)
+	assert.Equal(t, []byte{0x0a, 0x00}, segments[3][40:42])
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(segments[3][24:]), "seq after syn")
+	assert.Equal(t, uint32(15), binary.BigEndian.Uint32(segments[5][24:]), "seq after data")
+}
+
+// checksum calculates the Internet checksum of data.
+func checksum(data []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+
+	return ^uint16(sum)
+}
@@ -0,0 +1,181 @@
+// Package webhook implements HTTP server which runs command sequences of
+// named actions on authenticated POST requests, e.g. from GitHub deploy
+// webhooks.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// readHeaderTimeout limits time to read HTTP request headers.
+	readHeaderTimeout = 10 * time.Second
+
+	// maxBodySize limits size of request body which is read to check
+	// the signature.
+	maxBodySize = 1 << 20
+
+	// SignatureHeader contains HMAC SHA256 signature of the body in GitHub
+	// format: sha256=<hex>.
+	SignatureHeader = "X-Hub-Signature-256"
+
+	// TokenHeader contains the secret of the action if signature is not used.
+	TokenHeader = "X-Webhook-Token"
+)
+
+// ErrInvalidAction is returned when action in the config is incomplete.
+var ErrInvalidAction = errors.New("invalid webhook action")
+
+// Action maps a webhook to commands executed on the server of environment.
+type Action struct {
+	// Env is the config environment of the server.
+	Env string `json:"env" yaml:"env"`
+	// Secret authenticates requests with signature, bearer or token header.
+	Secret string `json:"secret" yaml:"secret"`
+	// Commands are executed one by one, the sequence stops on error.
+	Commands []string `json:"commands" yaml:"commands"`
+}
+
+// Actions maps action names to actions.
+type Actions map[string]Action
+
+// Validate checks that all actions have environment, secret and commands.
+func (actions Actions) Validate() error {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		action := actions[name]
+
+		switch {
+		case action.Env == "":
+			return fmt.Errorf("%w: %s has no env", ErrInvalidAction, name)
+		case action.Secret == "":
+			return fmt.Errorf("%w: %s has no secret", ErrInvalidAction, name)
+		case len(action.Commands) == 0:
+			return fmt.Errorf("%w: %s has no commands", ErrInvalidAction, name)
+		}
+	}
+
+	return nil
+}
+
+// Runner executes the command on the server of the environment.
+type Runner func(env string, command string) (string, error)
+
+// Result is the outcome of one command of the action.
+type Result struct {
+	Command  string `json:"command"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Server runs actions on POST /<action> requests.
+type Server struct {
+	actions Actions
+	run     Runner
+}
+
+// NewServer creates a new Server.
+func NewServer(actions Actions, run Runner) *Server {
+	return &Server{actions: actions, run: run}
+}
+
+// Serve accepts connections until the listener is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	server := http.Server{Handler: s, ReadHeaderTimeout: readHeaderTimeout}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ServeHTTP implements http.Handler. Results of commands are written as JSON
+// array. Status is 500 if a command failed.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	action, ok := s.actions[strings.Trim(r.URL.Path, "/")]
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	if !Authenticate(r.Header, body, action.Secret) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+		return
+	}
+
+	results := make([]Result, 0, len(action.Commands))
+	status := http.StatusOK
+
+	for _, command := range action.Commands {
+		result := Result{Command: command}
+
+		if result.Response, err = s.run(action.Env, command); err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			status = http.StatusInternalServerError
+
+			break
+		}
+
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(results)
+}
+
+// Authenticate checks GitHub signature of the body, bearer token or token
+// header against the secret.
+func Authenticate(header http.Header, body []byte, secret string) bool {
+	if signature, ok := strings.CutPrefix(header.Get(SignatureHeader), "sha256="); ok {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+
+		expected := hex.EncodeToString(mac.Sum(nil))
+
+		return hmac.Equal([]byte(signature), []byte(expected))
+	}
+
+	token := header.Get(TokenHeader)
+	if bearer, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
@@ -0,0 +1,100 @@
+package webhook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorcon/rcon-cli/internal/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActions_Validate(t *testing.T) {
+	assert.NoError(t, webhook.Actions{"deploy": {Env: "prod", Secret: "s", Commands: []string{"reload"}}}.Validate())
+
+	err := webhook.Actions{"deploy": {Env: "prod", Commands: []string{"reload"}}}.Validate()
+	assert.ErrorIs(t, err, webhook.ErrInvalidAction)
+	assert.EqualError(t, err, "invalid webhook action: deploy has no secret")
+
+	err = webhook.Actions{"deploy": {Env: "prod", Secret: "s"}}.Validate()
+	assert.ErrorIs(t, err, webhook.ErrInvalidAction)
+
+	err = webhook.Actions{"deploy": {Secret: "s", Commands: []string{"reload"}}}.Validate()
+	assert.ErrorIs(t, err, webhook.ErrInvalidAction)
+}
+
+func TestAuthenticate(t *testing.T) {
+	body := []byte(`{"action":"published"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.True(t, webhook.Authenticate(http.Header{webhook.SignatureHeader: {signature}}, body, "secret"))
+	assert.False(t, webhook.Authenticate(http.Header{webhook.SignatureHeader: {signature}}, body, "other"))
+	assert.True(t, webhook.Authenticate(http.Header{"Authorization": {"Bearer secret"}}, body, "secret"))
+	assert.True(t, webhook.Authenticate(http.Header{webhook.TokenHeader: {"secret"}}, body, "secret"))
+	assert.False(t, webhook.Authenticate(http.Header{webhook.TokenHeader: {"wrong"}}, body, "secret"))
+	assert.False(t, webhook.Authenticate(http.Header{}, body, "secret"))
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	var executed []string
+
+	server := webhook.NewServer(webhook.Actions{
+		"deploy": {Env: "prod", Secret: "secret", Commands: []string{"say Deploying", "reload"}},
+		"broken": {Env: "prod", Secret: "secret", Commands: []string{"fail", "reload"}},
+	}, func(env string, command string) (string, error) {
+		executed = append(executed, env+": "+command)
+		if command == "fail" {
+			return "", errors.New("denied")
+		}
+
+		return "ok", nil
+	})
+
+	request := func(method string, path string, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		r.Header.Set(webhook.TokenHeader, token)
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, request(http.MethodGet, "/deploy", "secret").Code)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, request(http.MethodPost, "/restart", "secret").Code)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request(http.MethodPost, "/deploy", "wrong").Code)
+		assert.Empty(t, executed)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		w := request(http.MethodPost, "/deploy", "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"command":"say Deploying","response":"ok"},{"command":"reload","response":"ok"}]`,
+			w.Body.String())
+		assert.Equal(t, []string{"prod: say Deploying", "prod: reload"}, executed)
+	})
+
+	t.Run("command failed", func(t *testing.T) {
+		executed = nil
+
+		w := request(http.MethodPost, "/broken", "secret")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `[{"command":"fail","error":"denied"}]`, w.Body.String())
+		assert.Equal(t, []string{"prod: fail"}, executed)
+	})
+}
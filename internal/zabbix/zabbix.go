@@ -0,0 +1,199 @@
+// Package zabbix sends values of trapper items to Zabbix server or proxy
+// with the sender protocol.
+package zabbix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPort is the trapper port of Zabbix server which is used if the
+// address has no port.
+const DefaultPort = "10051"
+
+// DefaultPrefix is the prefix of item keys.
+const DefaultPrefix = "rcon"
+
+// header is the signature and the protocol flags of Zabbix packets.
+const header = "ZBXD\x01"
+
+// maxResponseSize limits the response of the server which is a short JSON.
+const maxResponseSize = 1 << 16
+
+var (
+	// ErrSend is returned when values can not be sent to Zabbix.
+	ErrSend = errors.New("zabbix")
+
+	// ErrRejected is returned when Zabbix did not process some values, e.g.
+	// because items with the keys do not exist on the host.
+	ErrRejected = errors.New("zabbix rejected values")
+)
+
+// infoPattern matches numbers of processed and failed values in the info of
+// the response.
+var infoPattern = regexp.MustCompile(`processed:\s*(\d+);\s*failed:\s*(\d+);\s*total:\s*(\d+)`)
+
+// Item is the value of the trapper item of the host.
+type Item struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Clock int64  `json:"clock"`
+}
+
+// Response is the result of sending reported by Zabbix.
+type Response struct {
+	Processed int
+	Failed    int
+	Total     int
+}
+
+// Batch collects values to send them at once. It is safe for concurrent use.
+type Batch struct {
+	mu    sync.Mutex
+	items []Item
+}
+
+// Add adds the value of the item with the current time.
+func (b *Batch) Add(host string, key string, value string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.items = append(b.items, Item{Host: host, Key: key, Value: value, Clock: time.Now().Unix()})
+}
+
+// Items returns collected values.
+func (b *Batch) Items() []Item {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Item(nil), b.items...)
+}
+
+// Key returns the item key with parameters, e.g. rcon.latency[status].
+// Parameters with commas, brackets or quotes are quoted.
+func Key(name string, params ...string) string {
+	if len(params) == 0 {
+		return name
+	}
+
+	quoted := make([]string, len(params))
+
+	for i, param := range params {
+		quoted[i] = param
+		if strings.ContainsAny(param, `,[]"`) || strings.HasPrefix(param, " ") {
+			quoted[i] = `"` + strings.ReplaceAll(param, `"`, `\"`) + `"`
+		}
+	}
+
+	return name + "[" + strings.Join(quoted, ",") + "]"
+}
+
+// Send sends items to Zabbix server with the address. Returns ErrRejected
+// if some values are not processed.
+func Send(address string, items []Item, timeout time.Duration) (Response, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultPort)
+	}
+
+	data, err := json.Marshal(struct {
+		Request string `json:"request"`
+		Data    []Item `json:"data"`
+	}{Request: "sender data", Data: items})
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %s", ErrSend, err)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %s", ErrSend, err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err = conn.Write(Encode(data)); err != nil {
+		return Response{}, fmt.Errorf("%w: %s", ErrSend, err)
+	}
+
+	body, err := Decode(conn)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return parseResponse(body)
+}
+
+// Encode returns the packet with the header and the length of data.
+func Encode(data []byte) []byte {
+	packet := bytes.NewBuffer(make([]byte, 0, len(header)+8+len(data))) //nolint:gomnd // Length is uint64.
+	packet.WriteString(header)
+	_ = binary.Write(packet, binary.LittleEndian, uint64(len(data)))
+	packet.Write(data)
+
+	return packet.Bytes()
+}
+
+// Decode reads the packet and returns its data.
+func Decode(r io.Reader) ([]byte, error) {
+	head := make([]byte, len(header)+8) //nolint:gomnd // Length is uint64.
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, fmt.Errorf("%w: read response: %s", ErrSend, err)
+	}
+
+	if string(head[:len(header)]) != header {
+		return nil, fmt.Errorf("%w: invalid response header %q", ErrSend, head[:len(header)])
+	}
+
+	size := binary.LittleEndian.Uint64(head[len(header):])
+	if size > maxResponseSize {
+		return nil, fmt.Errorf("%w: response is too large: %d bytes", ErrSend, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("%w: read response: %s", ErrSend, err)
+	}
+
+	return data, nil
+}
+
+// parseResponse parses the response of the server.
+func parseResponse(body []byte) (Response, error) {
+	var response struct {
+		Response string `json:"response"`
+		Info     string `json:"info"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return Response{}, fmt.Errorf("%w: parse response: %s", ErrSend, err)
+	}
+
+	if response.Response != "success" {
+		return Response{}, fmt.Errorf("%w: %s %s", ErrSend, response.Response, response.Info)
+	}
+
+	var result Response
+
+	if match := infoPattern.FindStringSubmatch(response.Info); match != nil {
+		result.Processed, _ = strconv.Atoi(match[1])
+		result.Failed, _ = strconv.Atoi(match[2])
+		result.Total, _ = strconv.Atoi(match[3])
+	}
+
+	if result.Failed != 0 {
+		return result, fmt.Errorf("%w: %s", ErrRejected, response.Info)
+	}
+
+	return result, nil
+}
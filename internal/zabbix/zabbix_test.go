@@ -0,0 +1,108 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gorcon/rcon-cli/internal/zabbix"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve starts fake Zabbix trapper which sends the response to the sender
+// and passes received data to the channel.
+func serve(t *testing.T, response string) (string, chan []byte) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []byte, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		data, err := zabbix.Decode(conn)
+		if err != nil {
+			return
+		}
+
+		received <- data
+		_, _ = conn.Write(zabbix.Encode([]byte(response)))
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestEncode(t *testing.T) {
+	packet := zabbix.Encode([]byte("{}"))
+	assert.Equal(t, []byte("ZBXD\x01\x02\x00\x00\x00\x00\x00\x00\x00{}"), packet)
+
+	data, err := zabbix.Decode(bytes.NewReader(packet))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("{}"), data)
+
+	_, err = zabbix.Decode(bytes.NewReader([]byte("HTTP/1.1 400 Bad")))
+	assert.ErrorIs(t, err, zabbix.ErrSend)
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "rcon.players", zabbix.Key("rcon.players"))
+	assert.Equal(t, "rcon.latency[status]", zabbix.Key("rcon.latency", "status"))
+	assert.Equal(t, `rcon.latency["say \"a, b\""]`, zabbix.Key("rcon.latency", `say "a, b"`))
+}
+
+func TestSend(t *testing.T) {
+	items := []zabbix.Item{{Host: "rust-eu", Key: "rcon.players", Value: "42", Clock: 1700000000}}
+
+	t.Run("success", func(t *testing.T) {
+		address, received := serve(t, `{"response":"success","info":"processed: 1; failed: 0; total: 1; seconds spent: 0.000055"}`)
+
+		response, err := zabbix.Send(address, items, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, zabbix.Response{Processed: 1, Total: 1}, response)
+
+		var request struct {
+			Request string        `json:"request"`
+			Data    []zabbix.Item `json:"data"`
+		}
+
+		assert.NoError(t, json.Unmarshal(<-received, &request))
+		assert.Equal(t, "sender data", request.Request)
+		assert.Equal(t, items, request.Data)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		address, _ := serve(t, `{"response":"success","info":"processed: 0; failed: 1; total: 1; seconds spent: 0.000055"}`)
+
+		response, err := zabbix.Send(address, items, time.Second)
+		assert.ErrorIs(t, err, zabbix.ErrRejected)
+		assert.Equal(t, 1, response.Failed)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		address, _ := serve(t, `{"response":"failed","info":"host is not monitored"}`)
+
+		_, err := zabbix.Send(address, items, time.Second)
+		assert.ErrorIs(t, err, zabbix.ErrSend)
+		assert.EqualError(t, err, "zabbix: failed host is not monitored")
+	})
+}
+
+func TestBatch(t *testing.T) {
+	var batch zabbix.Batch
+	batch.Add("rust-eu", "rcon.players", "42")
+
+	items := batch.Items()
+	assert.Len(t, items, 1)
+	assert.Equal(t, "rcon.players", items[0].Key)
+	assert.NotZero(t, items[0].Clock)
+}